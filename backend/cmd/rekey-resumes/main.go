@@ -0,0 +1,33 @@
+// Command rekey-resumes moves resumes uploaded under the old shared keys to a unique key
+// per submission in the folder of the company's job. It is run once after deploying the
+// collision-free keys, after applying internal/database/scripts/resume_file_names.sql:
+//
+//	go run ./cmd/rekey-resumes -dry-run
+//	go run ./cmd/rekey-resumes
+package main
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/services"
+	"context"
+	"flag"
+	"log"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only log the resumes that would be moved")
+	flag.Parse()
+
+	config.LoadConfig()
+	database.Connect()
+
+	result, err := services.RekeyResumes(context.Background(), *dryRun)
+	if result != nil {
+		log.Printf("Old keys: %d, moved: %d, shared: %d, missing: %d, deleted: %d",
+			result.Keys, result.Rekeyed, result.Shared, result.Missing, result.Deleted)
+	}
+	if err != nil {
+		log.Fatalf("Could not re-key resumes: %s\n", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"backend/internal/api"
 	"backend/internal/config"
 	"backend/internal/database"
+	"backend/internal/services"
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -16,7 +18,24 @@ func main() {
 	config.LoadConfig()
 	database.Connect()
 
+	// Fail fast when the file storage is misconfigured
+	if _, err := services.GetStorage(); err != nil {
+		log.Fatalf("Could not set up file storage: %s\n", err)
+	}
+	if _, err := services.GetScanner(); err != nil {
+		log.Fatalf("Could not set up malware scanner: %s\n", err)
+	}
+
+	// Send candidate emails queued by bulk actions, retrying failed deliveries
+	go services.RunEmailQueueWorker(context.Background(), time.Minute)
+	// Remove stored files left behind by applications that could not be saved
+	if storageConfig := config.GetConfig().Storage; storageConfig.SweepInterval > 0 {
+		go services.RunOrphanSweeper(context.Background(), storageConfig.SweepInterval, storageConfig.SweepGracePeriod)
+	}
+
 	router := gin.Default()
+	// Uploaded files larger than this are written to temporary files while the form is parsed instead of kept in memory
+	router.MaxMultipartMemory = 1 << 20
 
 	// ✅ Custom CORS setup to allow Authorization header
 	router.Use(cors.New(cors.Config{
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+    "log"
     "net/http"
     "github.com/gin-gonic/gin"
     "backend/internal/models"
@@ -91,6 +92,13 @@ func GetFormDetailsH(ctx *gin.Context) {
         return
     }
 
+    // Record the view for form analytics, a tracking failure should not block the candidate
+    var tracking models.FormTracking
+    _ = ctx.ShouldBindQuery(&tracking)
+    if err := services.RecordFormEvent(ctx, formUUID, models.FormEventView, tracking); err != nil {
+        log.Printf("Failed to record form view: %v", err)
+    }
+
     ctx.JSON(http.StatusOK, formResponse)
 }
 
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListCandidatesH returns the candidates of the user's company, most recent applicants first (?q=&page=&page_size=)
+func ListCandidatesH(ctx *gin.Context) {
+	var req models.CandidateListRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid parameters", "error": err.Error()})
+		return
+	}
+
+	result, err := services.ListCandidates(ctx, req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve candidates", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":    "Candidates retrieved successfully",
+		"data":       result.Candidates,
+		"pagination": result.Pagination,
+	})
+}
+
+// GetCandidateH returns a candidate with all their applications, interviews and feedback
+func GetCandidateH(ctx *gin.Context) {
+	candidateID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid candidate ID format"})
+		return
+	}
+
+	profile, err := services.GetCandidateProfile(ctx, candidateID)
+	if err != nil {
+		if err == services.ErrCandidateNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve candidate", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
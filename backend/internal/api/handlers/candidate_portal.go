@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// candidatePortalError maps candidate portal errors to a response
+func candidatePortalError(ctx *gin.Context, err error, msg string) {
+	switch {
+	case err == services.ErrInvalidCandidateToken:
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case err == services.ErrApplicationClosed:
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err == services.ErrResumeType || errors.Is(err, services.ErrAttachmentTooLarge):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInfectedFile):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": msg, "error": err.Error()})
+	}
+}
+
+// GetCandidateApplicationH returns the application status for the magic link token (unauthenticated)
+func GetCandidateApplicationH(ctx *gin.Context) {
+	status, err := services.GetCandidateApplication(ctx, ctx.Query("token"))
+	if err != nil {
+		candidatePortalError(ctx, err, "Failed to fetch application status")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, status)
+}
+
+// WithdrawCandidateApplicationH lets the candidate withdraw their application (unauthenticated)
+func WithdrawCandidateApplicationH(ctx *gin.Context) {
+	status, err := services.WithdrawCandidateApplication(ctx, ctx.Query("token"))
+	if err != nil {
+		candidatePortalError(ctx, err, "Failed to withdraw application")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Application withdrawn successfully",
+		"data":    status,
+	})
+}
+
+// UpdateCandidateResumeH lets the candidate upload an updated resume (unauthenticated)
+func UpdateCandidateResumeH(ctx *gin.Context) {
+	file, err := ctx.FormFile("resume")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "resume file is required"})
+		return
+	}
+
+	status, err := services.ReplaceCandidateResume(ctx, ctx.Query("token"), file)
+	if err != nil {
+		candidatePortalError(ctx, err, "Failed to update resume")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message": "Resume updated successfully",
+		"data":    status,
+	})
+}
+
+// RequestStatusLinkH emails a fresh magic link to a candidate (unauthenticated)
+func RequestStatusLinkH(ctx *gin.Context) {
+	var request models.RequestStatusLinkRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := services.RequestApplicationStatusLink(ctx, request.JobID, request.Email); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to send status link", "error": err.Error()})
+		return
+	}
+
+	// Same answer whether or not an application exists
+	ctx.JSON(http.StatusAccepted, gin.H{"message": "If an application exists for this email, a status link has been sent"})
+}
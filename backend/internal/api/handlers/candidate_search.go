@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchCandidatesH searches all submissions to the user's jobs by name, email, skill, form answers
+// and resume text, with optional job, status, score and date filters (?q=&job_id=&status=&skill=&min_score=&max_score=&from_date=&to_date=&page=&page_size=)
+func SearchCandidatesH(ctx *gin.Context) {
+	var req models.CandidateSearchRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid search parameters", "error": err.Error()})
+		return
+	}
+
+	result, err := services.SearchCandidates(ctx, req)
+	if err != nil {
+		if err == services.ErrInvalidScoreRange || err == services.ErrInvalidDateRange {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to search candidates", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"message":    "Candidates retrieved successfully",
+		"data":       result.Results,
+		"pagination": result.Pagination,
+	})
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"backend/internal/storage"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServeFileH streams a file of the local or in-memory storage requested through a signed URL
+func ServeFileH(ctx *gin.Context) {
+	file, info, err := services.OpenSignedFile(ctx, ctx.Param("key"), ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		switch err {
+		case services.ErrInvalidFileSignature:
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case storage.ErrNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve file", "error": err.Error()})
+		}
+		return
+	}
+	defer file.Close()
+
+	ctx.DataFromReader(http.StatusOK, info.Size, info.ContentType, file, nil)
+}
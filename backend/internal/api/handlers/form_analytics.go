@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RecordFormEventH records a start event sent by the public application form page
+func RecordFormEventH(ctx *gin.Context) {
+	formUUID := ctx.Param("form_uuid")
+
+	// Validate form_uuid format before proceeding
+	if _, err := uuid.Parse(formUUID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid form UUID format", "error": err.Error()})
+		return
+	}
+
+	var request models.RecordFormEventRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := services.RecordFormEvent(ctx, formUUID, request.EventType, request.FormTracking); err != nil {
+		if err == services.ErrFormNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"msg": "Form not found", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to record form event", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Form event recorded successfully"})
+}
+
+// GetFormFunnelH returns the view -> start -> submit funnel of a form, broken down by source and day
+func GetFormFunnelH(ctx *gin.Context) {
+	formUUID := ctx.Param("form_uuid")
+
+	// Validate form_uuid format before proceeding
+	if _, err := uuid.Parse(formUUID); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid form UUID format", "error": err.Error()})
+		return
+	}
+
+	funnel, err := services.GetFormFunnel(ctx, formUUID, ctx.Query("from_date"), ctx.Query("to_date"), ctx.Query("source"))
+	if err != nil {
+		if err == services.ErrFormNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"msg": "Form not found", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to fetch form analytics", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, funnel)
+}
@@ -15,6 +15,8 @@ func CreateFormTemplateH(ctx *gin.Context) {
 		return
 	}
 
+	// copied_from_id is only set when copying through CopyFormTemplateH
+	template.CopiedFromID = nil
 
 	if err := services.CreateFormTemplate(ctx, &template); err != nil {
 
@@ -75,3 +77,64 @@ func DeleteFormTemplateH(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Form template deleted successfully"})
 }
+
+// ListSharedFormTemplatesH lists the company template library
+func ListSharedFormTemplatesH(ctx *gin.Context) {
+	templates, err := services.GetSharedFormTemplates(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve template library", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, templates)
+}
+
+// ShareFormTemplateH adds or removes one of the user's own templates from the company library
+func ShareFormTemplateH(ctx *gin.Context) {
+	templateID := ctx.Param("form_template_id")
+
+	var request models.ShareFormTemplateRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := services.UpdateFormTemplateSharing(ctx, templateID, *request.Shared)
+	if err != nil {
+		if err == services.ErrFormTemplateIdDoesNotExists {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Bad request", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to update template sharing", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, template)
+}
+
+// CopyFormTemplateH copies an own or company-shared template so it can be customized
+func CopyFormTemplateH(ctx *gin.Context) {
+	templateID := ctx.Param("form_template_id")
+
+	var request models.CopyFormTemplateRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := services.CopyFormTemplate(ctx, templateID, request.NewFormTemplateID)
+	if err != nil {
+		if err == services.ErrFormTemplateNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Form Template not found"})
+			return
+		}
+		if err == services.ErrFormTemplateIdExists {
+			ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Bad request", "error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to copy form template", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, template)
+}
@@ -22,9 +22,14 @@ func CreateInterviewH(ctx *gin.Context) {
 	interview, err := services.CreateInterview(ctx, &req); 
 
 	if err != nil {
-		if err == services.ErrOverlappingSlot {
+		switch err {
+		case services.ErrOverlappingSlot, services.ErrDuplicatePanelist, services.ErrSlotNotOwned, services.ErrSlotInPast,
+			services.ErrNoAvailability, services.ErrSubmissionNotForJob:
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
+		case services.ErrJobNotFound, services.ErrSubmissionNotFound, services.ErrInterviewRoundNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
 		}
 
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create interview", "msg": err.Error()})
@@ -34,7 +39,7 @@ func CreateInterviewH(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, interview)
 }
 
-// DeleteInterviewH handles the deletion of an interview
+// DeleteInterviewH cancels an interview without a reason, the interview is kept with the cancelled status
 func DeleteInterviewH(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
@@ -43,7 +48,7 @@ func DeleteInterviewH(ctx *gin.Context) {
 	}
 
 	if err := services.DeleteInterview(ctx, id); err != nil {
-		if err == services.ErrInterviewNotFound {
+		if err == services.ErrInterviewNotFound || err == services.ErrInterviewClosed {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -51,7 +56,94 @@ func DeleteInterviewH(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Interview deleted successfully"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "Interview cancelled successfully"})
+}
+
+// GetInterviewH returns an interview with its panel and its reschedules and cancellations
+func GetInterviewH(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	interview, err := services.GetInterview(ctx, id)
+	if err != nil {
+		if err == services.ErrInterviewNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve interview", "msg": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, interview)
+}
+
+// RescheduleInterviewH moves an interview to another slot of its interviewer
+func RescheduleInterviewH(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var req models.RescheduleInterviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	interview, err := services.RescheduleInterview(ctx, id, &req)
+	if err != nil {
+		switch err {
+		case services.ErrOverlappingSlot, services.ErrDuplicatePanelist, services.ErrSlotNotOwned, services.ErrSlotInPast,
+			services.ErrNoAvailability, services.ErrNotOnPanel:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case services.ErrInterviewNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case services.ErrInterviewClosed:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reschedule interview", "msg": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, interview)
+}
+
+// CancelInterviewH cancels an interview with a reason, its slots can then be booked again
+func CancelInterviewH(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var req models.CancelInterviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	interview, err := services.CancelInterview(ctx, id, req.Reason)
+	if err != nil {
+		switch err {
+		case services.ErrInterviewNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case services.ErrInterviewClosed:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel interview", "msg": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, interview)
 }
 
 // ListInterviewsH retrieves all interviews for the authenticated user
@@ -81,7 +173,10 @@ func SubmitFeedbackH(ctx *gin.Context) {
 	}
 
 	if err := services.SubmitFeedback(ctx, &req, id); err != nil {
-		if err == services.ErrInterviewNotFound || err == services.ErrUnauthorizedFeedback {
+		switch err {
+		case services.ErrInterviewNotFound, services.ErrUnauthorizedFeedback, services.ErrInterviewCancelled,
+			services.ErrUnknownCompetency, services.ErrScorecardIncomplete, services.ErrRatingOutOfScale,
+			services.ErrCommentRequired, services.ErrFeedbackRequired:
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListInterviewRoundsH returns the ordered interview rounds of a job
+func ListInterviewRoundsH(ctx *gin.Context) {
+	rounds, err := services.ListInterviewRounds(ctx, ctx.Param("job_id"))
+	if err != nil {
+		if err == services.ErrJobNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve interview rounds", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rounds)
+}
+
+// CreateInterviewRoundH adds an interview round, e.g. screening, technical or culture, to a job
+func CreateInterviewRoundH(ctx *gin.Context) {
+	var req models.CreateInterviewRoundRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	round, err := services.CreateInterviewRound(ctx, ctx.Param("job_id"), req)
+	if err != nil {
+		switch err {
+		case services.ErrJobNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case services.ErrInterviewRoundExists:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to create interview round", "error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, round)
+}
+
+// DeleteInterviewRoundH removes an interview round of a job, its interviews are kept
+func DeleteInterviewRoundH(ctx *gin.Context) {
+	roundID, err := strconv.Atoi(ctx.Param("round_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid round ID format"})
+		return
+	}
+
+	if err := services.DeleteInterviewRound(ctx, ctx.Param("job_id"), roundID); err != nil {
+		if err == services.ErrJobNotFound || err == services.ErrInterviewRoundNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to delete interview round", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Interview round deleted successfully"})
+}
+
+// GetSubmissionInterviewRoundsH returns the interview rounds of a submission with every interviewer's
+// feedback and the aggregate verdict of each round
+func GetSubmissionInterviewRoundsH(ctx *gin.Context) {
+	submissionID, err := strconv.Atoi(ctx.Param("submission_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+
+	rounds, err := services.GetSubmissionInterviewRounds(ctx, submissionID)
+	if err != nil {
+		if err == services.ErrSubmissionNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve interview rounds", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rounds)
+}
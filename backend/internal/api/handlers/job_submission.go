@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"backend/internal/database"
+	"backend/internal/models"
 	"backend/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -29,17 +34,35 @@ func HandleFormSubmission(c *gin.Context) {
 	submission, err := formService.HandleFormSubmission(c)
 	if err != nil {
 		log.Printf("Error handling form submission: %v", err)
+		if err == services.ErrDuplicateApplication || err == services.ErrResubmissionWindowClosed {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err == services.ErrResubmissionNotVerified {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrInfectedFile) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Job application submitted successfully",
+	statusCode, message := http.StatusCreated, "Job application submitted successfully"
+	if submission.Revision > 1 {
+		statusCode, message = http.StatusOK, "Job application updated successfully"
+	}
+
+	c.JSON(statusCode, gin.H{
+		"message": message,
 		"data": gin.H{
 			"job_id":     submission.JobID,
 			"ats_score":  submission.ATSScore,
-			"resume_url": submission.ResumeURL,
-			"status":     submission.Status,
+			"status":      submission.Status,
+			"revision":    submission.Revision,
+			"attachments": submission.Attachments,
 		},
 	})
 }
@@ -94,31 +117,34 @@ func GetFormSubmissions(c *gin.Context) {
 		return
 	}
 	
-	// Get status filter from query parameter
+	// Get status and tag filters from query parameters
 	status := c.Query("status")
-	
-	// Construct query based on whether status filter is provided
-	var query string
-	var args []interface{}
-	
+	tags := services.NormalizeTags(strings.Split(c.Query("tags"), ","))
+
+	// Construct query based on the provided filters
+	query := `
+		SELECT id, job_id, username, email, skills, resume_key, resume_file_name, resume_data, ats_score, ats_breakdown, status, assigned_to, candidate_id, created_at
+		FROM job_submissions
+		WHERE job_id = $1`
+	args := []interface{}{jobID}
+
 	if status != "" {
-		query = `
-			SELECT id, job_id, username, email, skills, resume_url, ats_score, status, created_at
-			FROM job_submissions
-			WHERE job_id = $1 AND status = $2
-			ORDER BY created_at DESC
-		`
-		args = []interface{}{jobID, status}
-	} else {
-		query = `
-			SELECT id, job_id, username, email, skills, resume_url, ats_score, status, created_at
-			FROM job_submissions
-			WHERE job_id = $1
-			ORDER BY created_at DESC
-		`
-		args = []interface{}{jobID}
+		args = append(args, status)
+		query += fmt.Sprintf(" AND status = $%d", len(args))
 	}
 
+	// Submissions must have every requested tag
+	if len(tags) > 0 {
+		args = append(args, pq.Array(tags))
+		query += fmt.Sprintf(` AND id IN (
+			SELECT job_submission_id FROM submission_tags
+			WHERE tag = ANY($%d)
+			GROUP BY job_submission_id
+			HAVING COUNT(DISTINCT tag) = %d)`, len(args), len(tags))
+	}
+
+	query += " ORDER BY created_at DESC"
+
 	// Debug the query
 	log.Printf("Executing query: %s with args: %v", query, args)
 
@@ -128,10 +154,21 @@ func GetFormSubmissions(c *gin.Context) {
 		Username  string         `json:"username" db:"username"`
 		Email     string         `json:"email" db:"email"`
 		Skills    pq.StringArray `json:"skills" db:"skills"`
-		ResumeURL string         `json:"resume_url" db:"resume_url"`
+		ResumeKey string         `json:"-" db:"resume_key"`
+		ResumeURL string         `json:"resume_url" db:"-"` // expiring signed URL
+		ResumeFileName string    `json:"resume_file_name" db:"resume_file_name"`
 		ATSScore  int            `json:"ats_score" db:"ats_score"`
 		Status    string         `json:"status" db:"status"`
 		CreatedAt time.Time      `json:"created_at" db:"created_at"`
+
+		AssignedTo  *int `json:"assigned_to" db:"assigned_to"`
+		CandidateID *int `json:"candidate_id" db:"candidate_id"`
+
+		ATSBreakdown *models.ATSScoreBreakdown `json:"ats_breakdown" db:"ats_breakdown"`
+		ResumeData   *models.ParsedResume       `json:"resume_data" db:"resume_data"`
+
+		Attachments []models.SubmissionAttachment `json:"attachments" db:"-"`
+		Tags        []string                      `json:"tags" db:"-"`
 	}
 
 	err = db.Select(&submissions, query, args...)
@@ -141,7 +178,41 @@ func GetFormSubmissions(c *gin.Context) {
 		return
 	}
 
-	log.Printf("Found %d submissions for job ID: %s with status: %s", len(submissions), jobID, status)
+	// Attach the additional uploaded files of each submission
+	submissionIDs := make([]int, len(submissions))
+	for i, submission := range submissions {
+		submissionIDs[i] = submission.ID
+	}
+	attachments, err := services.GetAttachmentsForSubmissions(c.Request.Context(), submissionIDs)
+	if err != nil {
+		log.Printf("Error retrieving submission attachments: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve submissions"})
+		return
+	}
+	submissionTags, err := services.GetTagsForSubmissions(c.Request.Context(), submissionIDs)
+	if err != nil {
+		log.Printf("Error retrieving submission tags: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve submissions"})
+		return
+	}
+	for i := range submissions {
+		submissions[i].ResumeURL, err = services.SignedResumeURL(c.Request.Context(), submissions[i].ResumeKey)
+		if err != nil {
+			log.Printf("Error signing resume URL: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve submissions"})
+			return
+		}
+		submissions[i].Tags = submissionTags[submissions[i].ID]
+		if submissions[i].Tags == nil {
+			submissions[i].Tags = []string{}
+		}
+		submissions[i].Attachments = attachments[submissions[i].ID]
+		if submissions[i].Attachments == nil {
+			submissions[i].Attachments = []models.SubmissionAttachment{}
+		}
+	}
+
+	log.Printf("Found %d submissions for job ID: %s with status: %s and tags: %v", len(submissions), jobID, status, tags)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Job submissions retrieved successfully",
@@ -149,42 +220,198 @@ func GetFormSubmissions(c *gin.Context) {
 	})
 }
 
-// UpdateSubmissionStatusH handles updating the status of a job submission
+// UpdateSubmissionStatusH moves a submission to another stage of the job's pipeline
 func UpdateSubmissionStatusH(c *gin.Context) {
-	// Get submission ID from URL
-	submissionID := c.Param("submission_id")
-	if submissionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "submission ID is required"})
+	submissionID, err := strconv.Atoi(c.Param("submission_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
 		return
 	}
 
-	// Parse request body
-	var request struct {
-		Status string `json:"status" binding:"required,oneof=applied under_review shortlisted rejected selected"`
-	}
+	var request models.MoveSubmissionRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Update submission status in database
-	db := database.GetDB()
-	query := "UPDATE job_submissions SET status = $1 WHERE id = $2 RETURNING id, status"
-	
-	var updatedID int
-	var updatedStatus string
-	err := db.QueryRow(query, request.Status, submissionID).Scan(&updatedID, &updatedStatus)
+	change, err := services.MoveSubmission(c, submissionID, request)
 	if err != nil {
-		log.Printf("Error updating submission status: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission status"})
+		switch err {
+		case services.ErrSubmissionNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case services.ErrUnknownStage, services.ErrInvalidStageTransition, services.ErrSubmissionAlreadyInStage, services.ErrSubmissionWithdrawn:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Error updating submission status: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission status"})
+		}
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Submission status updated successfully",
 		"data": gin.H{
-			"id":     updatedID,
-			"status": updatedStatus,
+			"id":              change.JobSubmissionID,
+			"status":          change.ToStage,
+			"previous_status": change.FromStage,
+			"changed_at":      change.CreatedAt,
 		},
 	})
-} 
\ No newline at end of file
+}
+
+// BulkUpdateSubmissionsH moves, rejects, tags or assigns many submissions at once and
+// optionally queues a templated email for each affected candidate
+func BulkUpdateSubmissionsH(c *gin.Context) {
+	var request models.BulkSubmissionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response, err := services.BulkUpdateSubmissions(c, request)
+	if err != nil {
+		switch err {
+		case services.ErrBulkStageRequired, services.ErrBulkTagsRequired, services.ErrAssigneeNotFound:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Error applying bulk action: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submissions"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Bulk action applied",
+		"data":    response,
+	})
+}
+
+// GetSubmissionStagesH returns the timestamped stage history of a submission
+func GetSubmissionStagesH(c *gin.Context) {
+	submissionID, err := strconv.Atoi(c.Param("submission_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+
+	history, err := services.GetSubmissionStageHistory(c, submissionID)
+	if err != nil {
+		if err == services.ErrSubmissionNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Error retrieving submission stage history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve stage history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Stage history retrieved successfully",
+		"data":    history,
+	})
+}
+
+// GetSubmissionHistoryH returns the previous versions of a submission the candidate has updated
+func GetSubmissionHistoryH(c *gin.Context) {
+	submissionID, err := strconv.Atoi(c.Param("submission_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+
+	history, err := services.GetSubmissionHistory(c, submissionID)
+	if err != nil {
+		if err == services.ErrSubmissionNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Error retrieving submission history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve submission history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Submission history retrieved successfully",
+		"data":    history,
+	})
+}
+
+// DownloadResumeH redirects to a short-lived signed URL of the resume of a submission, or streams
+// the file with ?stream=true. ?revision= downloads the resume of a previous version.
+func DownloadResumeH(c *gin.Context) {
+	submissionID, err := strconv.Atoi(c.Param("submission_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+	revision := 0
+	if value := c.Query("revision"); value != "" {
+		revision, err = strconv.Atoi(value)
+		if err != nil || revision < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision"})
+			return
+		}
+	}
+
+	resumeErrorStatus := func(err error) int {
+		switch err {
+		case services.ErrSubmissionNotFound, services.ErrRevisionNotFound, services.ErrResumeNotFound:
+			return http.StatusNotFound
+		default:
+			return http.StatusInternalServerError
+		}
+	}
+
+	if c.Query("stream") != "true" {
+		url, err := services.GetResumeDownloadURL(c, submissionID, revision)
+		if err != nil {
+			c.JSON(resumeErrorStatus(err), gin.H{"msg": "Failed to retrieve resume", "error": err.Error()})
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusFound, url)
+		return
+	}
+
+	file, info, fileName, err := services.OpenResume(c, submissionID, revision)
+	if err != nil {
+		c.JSON(resumeErrorStatus(err), gin.H{"msg": "Failed to retrieve resume", "error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, info.Size, info.ContentType, file, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, fileName),
+		"Cache-Control":       "no-store",
+	})
+}
+
+// GetResumePreviewH returns the resume of a submission as sanitized HTML and a first page thumbnail, see DownloadResumeH for ?revision
+func GetResumePreviewH(c *gin.Context) {
+	submissionID, err := strconv.Atoi(c.Param("submission_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+	revision := 0
+	if value := c.Query("revision"); value != "" {
+		revision, err = strconv.Atoi(value)
+		if err != nil || revision < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision"})
+			return
+		}
+	}
+
+	preview, err := services.GetResumePreview(c, submissionID, revision)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch err {
+		case services.ErrSubmissionNotFound, services.ErrRevisionNotFound, services.ErrResumeNotFound:
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"msg": "Failed to retrieve resume preview", "error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, preview)
+}
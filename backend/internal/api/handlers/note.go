@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// noteErrorStatus maps note and tag service errors to HTTP status codes
+func noteErrorStatus(err error) int {
+	switch err {
+	case services.ErrSubmissionNotFound, services.ErrNoteNotFound, services.ErrNotificationNotFound:
+		return http.StatusNotFound
+	case services.ErrNoteNotAuthor:
+		return http.StatusForbidden
+	case services.ErrNoteParentNotFound, services.ErrBulkTagsRequired:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListSubmissionNotesH returns the threaded internal notes of a submission
+func ListSubmissionNotesH(ctx *gin.Context) {
+	submissionID, err := strconv.Atoi(ctx.Param("submission_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+
+	notes, err := services.ListSubmissionNotes(ctx, submissionID)
+	if err != nil {
+		ctx.JSON(noteErrorStatus(err), gin.H{"msg": "Failed to retrieve notes", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notes)
+}
+
+// CreateSubmissionNoteH adds a note or reply to a submission, @username mentions notify teammates
+func CreateSubmissionNoteH(ctx *gin.Context) {
+	submissionID, err := strconv.Atoi(ctx.Param("submission_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+
+	var req models.CreateNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	note, err := services.CreateSubmissionNote(ctx, submissionID, req)
+	if err != nil {
+		ctx.JSON(noteErrorStatus(err), gin.H{"msg": "Failed to create note", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, note)
+}
+
+// UpdateSubmissionNoteH changes the text of a note (author only)
+func UpdateSubmissionNoteH(ctx *gin.Context) {
+	submissionID, err := strconv.Atoi(ctx.Param("submission_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+	noteID, err := strconv.Atoi(ctx.Param("note_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID format"})
+		return
+	}
+
+	var req models.UpdateNoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	note, err := services.UpdateSubmissionNote(ctx, submissionID, noteID, req)
+	if err != nil {
+		ctx.JSON(noteErrorStatus(err), gin.H{"msg": "Failed to update note", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, note)
+}
+
+// DeleteSubmissionNoteH deletes a note (author only)
+func DeleteSubmissionNoteH(ctx *gin.Context) {
+	submissionID, err := strconv.Atoi(ctx.Param("submission_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+	noteID, err := strconv.Atoi(ctx.Param("note_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID format"})
+		return
+	}
+
+	if err := services.DeleteSubmissionNote(ctx, submissionID, noteID); err != nil {
+		ctx.JSON(noteErrorStatus(err), gin.H{"msg": "Failed to delete note", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Note deleted successfully"})
+}
+
+// GetSubmissionTagsH returns the tags of a submission
+func GetSubmissionTagsH(ctx *gin.Context) {
+	submissionID, err := strconv.Atoi(ctx.Param("submission_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+
+	tags, err := services.GetSubmissionTags(ctx, submissionID)
+	if err != nil {
+		ctx.JSON(noteErrorStatus(err), gin.H{"msg": "Failed to retrieve tags", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// AddSubmissionTagsH adds free-form tags to a submission
+func AddSubmissionTagsH(ctx *gin.Context) {
+	submissionID, err := strconv.Atoi(ctx.Param("submission_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+
+	var req models.SubmissionTagsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	tags, err := services.AddSubmissionTags(ctx, submissionID, req.Tags)
+	if err != nil {
+		ctx.JSON(noteErrorStatus(err), gin.H{"msg": "Failed to add tags", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// RemoveSubmissionTagH removes a tag from a submission
+func RemoveSubmissionTagH(ctx *gin.Context) {
+	submissionID, err := strconv.Atoi(ctx.Param("submission_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+
+	if err := services.RemoveSubmissionTag(ctx, submissionID, ctx.Param("tag")); err != nil {
+		ctx.JSON(noteErrorStatus(err), gin.H{"msg": "Failed to remove tag", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tag removed successfully"})
+}
+
+// ListNotificationsH returns the notifications of the logged in user (?unread=true for unread only)
+func ListNotificationsH(ctx *gin.Context) {
+	notifications, err := services.ListNotifications(ctx, ctx.Query("unread") == "true")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve notifications", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+// MarkNotificationReadH marks a notification as read
+func MarkNotificationReadH(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := services.MarkNotificationRead(ctx, id); err != nil {
+		ctx.JSON(noteErrorStatus(err), gin.H{"msg": "Failed to update notification", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllNotificationsReadH marks all notifications of the logged in user as read
+func MarkAllNotificationsReadH(ctx *gin.Context) {
+	if err := services.MarkAllNotificationsRead(ctx); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to update notifications", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "All notifications marked as read"})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJobPipelineH returns the ordered pipeline stages of a job
+func GetJobPipelineH(ctx *gin.Context) {
+	pipeline, err := services.GetJobPipeline(ctx, ctx.Param("job_id"))
+	if err != nil {
+		if err == services.ErrJobNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve pipeline", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pipeline)
+}
+
+// UpdateJobPipelineH replaces the ordered pipeline stages of a job
+func UpdateJobPipelineH(ctx *gin.Context) {
+	var req models.UpdatePipelineRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	pipeline, err := services.UpdateJobPipeline(ctx, ctx.Param("job_id"), req)
+	if err != nil {
+		switch err {
+		case services.ErrJobNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case services.ErrPipelineStageKeyExists, services.ErrPipelineStageKeyReserved, services.ErrPipelineStageKeyTooLong, services.ErrPipelineFirstStage, services.ErrPipelineTransition:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case services.ErrPipelineStageInUse:
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to update pipeline", "error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pipeline)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJobRankingH returns the top candidates of a job ranked by ATS score, knockout criteria,
+// interview verdicts and recency, with an explanation per candidate (?limit=)
+func GetJobRankingH(ctx *gin.Context) {
+	var req models.RankingRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid parameters", "error": err.Error()})
+		return
+	}
+
+	ranking, err := services.GetJobRanking(ctx, ctx.Param("job_id"), req)
+	if err != nil {
+		if err == services.ErrJobNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to rank candidates", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ranking)
+}
+
+// GetJobRankingWeightsH returns the ranking weights of a job
+func GetJobRankingWeightsH(ctx *gin.Context) {
+	weights, err := services.GetJobRankingWeights(ctx, ctx.Param("job_id"))
+	if err != nil {
+		if err == services.ErrJobNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve ranking weights", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, weights)
+}
+
+// UpdateJobRankingWeightsH sets the ranking weights of a job
+func UpdateJobRankingWeightsH(ctx *gin.Context) {
+	var req models.RankingWeights
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	weights, err := services.UpdateJobRankingWeights(ctx, ctx.Param("job_id"), req)
+	if err != nil {
+		switch err {
+		case services.ErrJobNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case services.ErrRankingWeightsZero:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to update ranking weights", "error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, weights)
+}
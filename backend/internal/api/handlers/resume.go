@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseResumeH parses an uploaded resume into contact details, work history, education and skills
+// so the application form can be pre-filled (unauthenticated)
+func ParseResumeH(ctx *gin.Context) {
+	file, err := ctx.FormFile("resume")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "resume file is required"})
+		return
+	}
+
+	parsed, err := services.ParseResumeFile(file)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAttachmentTooLarge), err == services.ErrResumeType:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case err == services.ErrUnsupportedResumeFormat:
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to parse resume", "error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, parsed)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetScorecardH returns the scorecard interviewers fill in for a job
+func GetScorecardH(ctx *gin.Context) {
+	scorecard, err := services.GetScorecard(ctx, ctx.Param("job_id"))
+	if err != nil {
+		if err == services.ErrJobNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve scorecard", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scorecard)
+}
+
+// UpdateScorecardH replaces the competencies of a job's scorecard
+func UpdateScorecardH(ctx *gin.Context) {
+	var req models.UpdateScorecardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	scorecard, err := services.UpdateScorecard(ctx, ctx.Param("job_id"), req)
+	if err != nil {
+		switch err {
+		case services.ErrJobNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case services.ErrDuplicateCompetency:
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to update scorecard", "error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scorecard)
+}
+
+// GetInterviewScorecardH returns the scorecard an interviewer fills in for an interview
+func GetInterviewScorecardH(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	scorecard, err := services.GetInterviewScorecard(ctx, id)
+	if err != nil {
+		if err == services.ErrInterviewNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve scorecard", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scorecard)
+}
+
+// GetSubmissionScorecardH returns the scores of all interviewers of a candidate, averaged per competency
+func GetSubmissionScorecardH(ctx *gin.Context) {
+	submissionID, err := strconv.Atoi(ctx.Param("submission_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID format"})
+		return
+	}
+
+	scorecard, err := services.GetSubmissionScorecard(ctx, submissionID)
+	if err != nil {
+		if err == services.ErrSubmissionNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve scorecard", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, scorecard)
+}
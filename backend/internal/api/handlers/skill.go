@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// skillErrorStatus maps skill service errors to HTTP status codes
+func skillErrorStatus(err error) int {
+	switch err {
+	case services.ErrSkillTaxonomyAccess:
+		return http.StatusForbidden
+	case services.ErrSkillNotFound:
+		return http.StatusNotFound
+	case services.ErrSkillExists, services.ErrSkillNameRequired, services.ErrSkillParentNotFound, services.ErrSkillParentCycle:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ListSkillsH lists the skills taxonomy entries of the company
+func ListSkillsH(ctx *gin.Context) {
+	skills, err := services.ListSkills(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to retrieve skills", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, skills)
+}
+
+// CreateSkillH adds a skill with its aliases and optional parent category to the company taxonomy (HR action)
+func CreateSkillH(ctx *gin.Context) {
+	var skill models.Skill
+	if err := ctx.ShouldBindJSON(&skill); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if err := services.CreateSkill(ctx, &skill); err != nil {
+		ctx.JSON(skillErrorStatus(err), gin.H{"msg": "Failed to create skill", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, skill)
+}
+
+// UpdateSkillH changes the name, aliases or parent category of a skill (HR action)
+func UpdateSkillH(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID"})
+		return
+	}
+
+	var skill models.Skill
+	if err := ctx.ShouldBindJSON(&skill); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"msg": "Invalid input", "error": err.Error()})
+		return
+	}
+
+	if err := services.UpdateSkill(ctx, id, &skill); err != nil {
+		ctx.JSON(skillErrorStatus(err), gin.H{"msg": "Failed to update skill", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, skill)
+}
+
+// DeleteSkillH removes a skill from the company taxonomy (HR action)
+func DeleteSkillH(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skill ID"})
+		return
+	}
+
+	if err := services.DeleteSkill(ctx, id); err != nil {
+		ctx.JSON(skillErrorStatus(err), gin.H{"msg": "Failed to delete skill", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Skill deleted successfully"})
+}
+
+// AutocompleteSkillsH suggests canonical skill names for a partial input (?q=&limit=)
+func AutocompleteSkillsH(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	suggestions, err := services.AutocompleteSkills(ctx, ctx.Query("q"), limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"msg": "Failed to autocomplete skills", "error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, suggestions)
+}
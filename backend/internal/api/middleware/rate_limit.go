@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"backend/internal/ratelimit"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitRequestsPerIP rejects requests of a client IP beyond limit per window with 429
+func LimitRequestsPerIP(limit int, window time.Duration) gin.HandlerFunc {
+	limiter := ratelimit.New(limit, window)
+	return func(ctx *gin.Context) {
+		if !limiter.Allow(ctx.ClientIP()) {
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": "too many requests, please try again later"})
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"backend/internal/config"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LimitUploadSize rejects upload requests larger than the configured maximum request size.
+// Requests without a Content-Length fail while the multipart form is read once the limit is reached.
+func LimitUploadSize() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		limit := config.GetConfig().Upload.MaxRequestSize
+		if ctx.Request.ContentLength > limit {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request exceeds the maximum upload size of %d bytes", limit)})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+		ctx.Next()
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"backend/internal/api/handlers"
 	"backend/internal/api/middleware"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -21,7 +22,18 @@ func SetupRoutes(router *gin.Engine) {
 		public.POST("/register", handlers.RegisterH)
 
 		// candidate job_submission routes
-		public.POST("/jobs/:job_id/apply", handlers.HandleFormSubmission)    // Submit job application
+		public.POST("/jobs/:job_id/apply", middleware.LimitUploadSize(), handlers.HandleFormSubmission) // Submit job application
+		public.POST("/forms/:form_uuid/events", middleware.LimitRequestsPerIP(60, time.Hour), handlers.RecordFormEventH) // Record form start event for analytics, at most 60 per IP and hour, views are recorded when the form is fetched
+		public.POST("/resume/parse", middleware.LimitUploadSize(), handlers.ParseResumeH)               // Parse a resume to pre-fill the application form
+
+		// candidate status page routes, authorized by the magic link token (?token=)
+		public.GET("/candidate/application", handlers.GetCandidateApplicationH)                  // Get application status
+		public.POST("/candidate/application/withdraw", handlers.WithdrawCandidateApplicationH)   // Withdraw application
+		public.POST("/candidate/application/resume", middleware.LimitUploadSize(), handlers.UpdateCandidateResumeH) // Upload an updated resume
+		public.POST("/candidate/status-link", middleware.LimitRequestsPerIP(10, time.Hour), handlers.RequestStatusLinkH) // Email a new magic link, at most 10 requests per IP and hour
+
+		// files of the local and in-memory storage, authorized by the signed URL (?expires=&signature=)
+		public.GET("/files/*key", handlers.ServeFileH)
 	}
 
 	// Protected routes (auth required)
@@ -37,8 +49,32 @@ func SetupRoutes(router *gin.Engine) {
 			jobs.GET("/status/:status", handlers.GetJobsByStatusH)    // Get jobs by status
 			jobs.GET("", handlers.ListUserJobsH)                      // List all jobs for user
 			jobs.DELETE("/:job_id", handlers.DeleteJobH)               // Delete job
-			jobs.GET("/:job_id/submissions", handlers.GetFormSubmissions) // Get job submissions with optional status filter
+			jobs.GET("/:job_id/submissions", handlers.GetFormSubmissions) // Get job submissions with optional status and tags filters
 			jobs.PUT("/submissions/:submission_id/status", handlers.UpdateSubmissionStatusH) // Update candidate's submission status
+			jobs.GET("/submissions/:submission_id/history", handlers.GetSubmissionHistoryH)  // Get previous versions of an updated submission
+			jobs.GET("/submissions/:submission_id/resume", handlers.DownloadResumeH)          // Redirect to a signed resume URL, or stream it with ?stream=true
+			jobs.GET("/submissions/:submission_id/resume/preview", handlers.GetResumePreviewH) // Resume as sanitized HTML with a first page thumbnail
+			jobs.POST("/submissions/bulk", handlers.BulkUpdateSubmissionsH)                    // Move, reject, tag or assign many submissions at once
+			jobs.GET("/submissions/:submission_id/stages", handlers.GetSubmissionStagesH)    // Get timestamped pipeline stage history of a submission
+			jobs.GET("/submissions/:submission_id/notes", handlers.ListSubmissionNotesH)              // List threaded internal notes of a submission
+			jobs.POST("/submissions/:submission_id/notes", handlers.CreateSubmissionNoteH)            // Add a note or reply, @mentions notify teammates
+			jobs.PUT("/submissions/:submission_id/notes/:note_id", handlers.UpdateSubmissionNoteH)    // Edit own note
+			jobs.DELETE("/submissions/:submission_id/notes/:note_id", handlers.DeleteSubmissionNoteH) // Delete own note
+			jobs.GET("/submissions/:submission_id/tags", handlers.GetSubmissionTagsH)                 // List tags of a submission
+			jobs.POST("/submissions/:submission_id/tags", handlers.AddSubmissionTagsH)                // Add free-form tags to a submission
+			jobs.DELETE("/submissions/:submission_id/tags/:tag", handlers.RemoveSubmissionTagH)       // Remove a tag from a submission
+			jobs.GET("/:job_id/pipeline", handlers.GetJobPipelineH)                          // Get ordered pipeline stages of a job
+			jobs.PUT("/:job_id/pipeline", handlers.UpdateJobPipelineH)                       // Replace pipeline stages of a job
+			jobs.GET("/:job_id/ranking", handlers.GetJobRankingH)                            // Top-N shortlist ranked by score, knockouts, interviews and recency
+			jobs.GET("/:job_id/ranking/weights", handlers.GetJobRankingWeightsH)             // Get ranking weights of a job
+			jobs.PUT("/:job_id/ranking/weights", handlers.UpdateJobRankingWeightsH)          // Tune ranking weights of a job
+			jobs.GET("/:job_id/interview-rounds", handlers.ListInterviewRoundsH)                       // List ordered interview rounds of a job
+			jobs.POST("/:job_id/interview-rounds", handlers.CreateInterviewRoundH)                     // Add an interview round, e.g. screening, technical, culture
+			jobs.DELETE("/:job_id/interview-rounds/:round_id", handlers.DeleteInterviewRoundH)         // Remove an interview round, its interviews are kept
+			jobs.GET("/submissions/:submission_id/interview-rounds", handlers.GetSubmissionInterviewRoundsH) // Interview rounds of a candidate with each interviewer's feedback and the round verdict
+			jobs.GET("/:job_id/scorecard", handlers.GetScorecardH)                                     // Get the competencies and rating scales interviewers rate for a job
+			jobs.PUT("/:job_id/scorecard", handlers.UpdateScorecardH)                                  // Replace the scorecard competencies of a job(HR action)
+			jobs.GET("/submissions/:submission_id/scorecard", handlers.GetSubmissionScorecardH)        // Scores of all interviewers of a candidate averaged per competency, with their verdicts
 
             //TODO: job_submission route
             //jobs.PUT("/jobs/submissions/:submission_id/status", handlers.UpdateSubmissionStatusH)   // Update candidate's candidature status
@@ -49,6 +85,9 @@ func SetupRoutes(router *gin.Engine) {
 		formTemplates := api.Group("/forms/templates")
 		{
 			formTemplates.POST("", handlers.CreateFormTemplateH)                     // Create form template
+			formTemplates.GET("/library", handlers.ListSharedFormTemplatesH)         // List templates shared within the company
+			formTemplates.PATCH("/:form_template_id/share", handlers.ShareFormTemplateH) // Share/unshare own template with the company
+			formTemplates.POST("/:form_template_id/copy", handlers.CopyFormTemplateH)    // Copy own or shared template to customize it
 			formTemplates.GET("/:form_template_id", handlers.GetFormTemplateH)       // Get specific template
 			formTemplates.GET("", handlers.ListFormTemplatesH)                       // List all templates
 			formTemplates.DELETE("/:form_template_id", handlers.DeleteFormTemplateH) // Delete template
@@ -61,6 +100,7 @@ func SetupRoutes(router *gin.Engine) {
             applicationForms.PATCH("/forms/:form_uuid/status", handlers.UpdateFormStatusH)  // Update form status (active/inactive)
             applicationForms.GET("/forms/:form_uuid", handlers.GetFormDetailsH)             // Get job and form template details (unauthenticated)
             applicationForms.DELETE("/forms/:form_uuid", handlers.DeleteFormH)              // Delete form and unlink from job
+            applicationForms.GET("/forms/:form_uuid/analytics", handlers.GetFormFunnelH)    // Get form funnel (views, starts, submissions) by source and day
         }
 
 
@@ -83,13 +123,43 @@ func SetupRoutes(router *gin.Engine) {
             availability.GET("", handlers.GetAllAvailabilityH)         // Get all available people with with optional date range, profile filters
         }
 
+        // Candidate search routes
+        candidates := api.Group("/candidates")
+        {
+            candidates.GET("/search", handlers.SearchCandidatesH) // Full-text search across all submissions to own jobs with filters and pagination
+            candidates.GET("", handlers.ListCandidatesH)          // List the company's candidates (?q=&page=&page_size=)
+            candidates.GET("/:id", handlers.GetCandidateH)        // Candidate with all applications, interviews and feedback
+        }
+
+        // Notification routes
+        notifications := api.Group("/notifications")
+        {
+            notifications.GET("", handlers.ListNotificationsH)                // List own notifications (?unread=true)
+            notifications.PUT("/read-all", handlers.MarkAllNotificationsReadH) // Mark all own notifications as read
+            notifications.PUT("/:id/read", handlers.MarkNotificationReadH)     // Mark a notification as read
+        }
+
+        // Skills taxonomy routes
+        skills := api.Group("/skills")
+        {
+            skills.GET("", handlers.ListSkillsH)                      // List the company's skills taxonomy
+            skills.POST("", handlers.CreateSkillH)                    // Add a skill with aliases and parent category(HR action)
+            skills.GET("/autocomplete", handlers.AutocompleteSkillsH) // Suggest canonical skill names (?q=&limit=)
+            skills.PUT("/:id", handlers.UpdateSkillH)                 // Update a skill(HR action)
+            skills.DELETE("/:id", handlers.DeleteSkillH)              // Delete a skill(HR action)
+        }
+
         //Interview routes
         interviews := api.Group("/interviews")
         {
-            interviews.POST("", handlers.CreateInterviewH)                       // Reserve availability slot(HR action)
-            interviews.DELETE("/:id", handlers.DeleteInterviewH)                 // Cancel interview booking(HR action) 
+            interviews.POST("", handlers.CreateInterviewH)                       // Reserve availability slots of one interviewer or a panel, optionally in a job's round(HR action)
+            interviews.DELETE("/:id", handlers.DeleteInterviewH)                 // Cancel interview booking without a reason, the interview is kept as cancelled(HR/Interviewer action)
             interviews.GET("", handlers.ListAllInterviewsH)                      // List all interviews with optional filters(query params) and response based on the role logged in(HR/Interviewer)
-            interviews.POST("/:id/feedback", handlers.SubmitFeedbackH)           // Submit feedback for interview(Interviewer action)
+            interviews.GET("/:id", handlers.GetInterviewH)                       // Get an interview with its panel and reschedule/cancellation history(HR/Interviewer)
+            interviews.POST("/:id/reschedule", handlers.RescheduleInterviewH)    // Move an interview to another free slot, releasing the old one(HR/Interviewer action)
+            interviews.POST("/:id/cancel", handlers.CancelInterviewH)            // Cancel an interview with a reason, its slots can be booked again(HR/Interviewer action)
+            interviews.POST("/:id/feedback", handlers.SubmitFeedbackH)           // Submit a strong no to strong yes verdict with the ratings of the job's scorecard(Interviewer action)
+            interviews.GET("/:id/scorecard", handlers.GetInterviewScorecardH)    // Scorecard to fill in for an interview(HR/Interviewer)
         }
 
    }
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -12,6 +15,51 @@ type Config struct {
 	JWTSecret   string
 	TestMode    bool
 	DBConfig    postgresConfig
+	Mail        mailConfig
+	Storage     storageConfig
+	Upload      uploadConfig
+
+	// Base URL of the frontend, used to build links sent to candidates
+	AppBaseURL string
+	// How long candidate magic links stay valid
+	CandidateLinkTTL time.Duration
+	// How long signed resume download URLs stay valid
+	ResumeURLTTL time.Duration
+	// pdftoppm binary rendering the first page of PDF resumes as preview thumbnail, empty disables PDF thumbnails
+	PDFRenderer string
+	// Also link applications with a different email to an existing candidate with the same phone and a similar name
+	CandidateFuzzyMatching bool
+}
+
+// mailConfig holds the SMTP settings, mails are only logged when Host is empty
+type mailConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// storageConfig selects where uploaded files are stored
+type storageConfig struct {
+	Backend  string // s3, local or memory
+	LocalDir string // root directory of the local backend
+	// Public URL of this API, signed URLs of the local and memory backends point to it
+	BaseURL string
+	// How often files no submission refers to are removed, 0 disables the sweeper
+	SweepInterval time.Duration
+	// Unreferenced files younger than this are kept, their submission may still be saved
+	SweepGracePeriod time.Duration
+}
+
+// uploadConfig limits uploaded files and selects the malware scanner
+type uploadConfig struct {
+	MaxResumeSize     int64         // bytes
+	MaxAttachmentSize int64         // bytes, per additional file
+	MaxRequestSize    int64         // bytes, whole multipart request with all files
+	Scanner           string        // none or clamav
+	ClamAVAddress     string        // tcp://host:port or unix:///path/to/clamd.sock
+	ScanTimeout       time.Duration // per file
 }
 
 type postgresConfig struct {
@@ -48,6 +96,12 @@ func LoadConfig() error {
 	// Load test mode from environment
 	testMode := os.Getenv("TEST_MODE") == "true" || os.Getenv("S3_TEST_MODE") == "true"
 
+	// Test mode keeps uploaded files in memory unless a backend is configured
+	storageBackend := "s3"
+	if testMode {
+		storageBackend = "memory"
+	}
+
 	globalConfig = &Config{
 		AWSRegion:   getEnvOrDefault("AWS_REGION", "us-east-1"),
 		AWSEndpoint: getEnvOrDefault("AWS_ENDPOINT", ""),
@@ -61,6 +115,35 @@ func LoadConfig() error {
 			Password: getEnvOrDefault("DB_PASSWORD", "123456"),
 			Dbname:   getEnvOrDefault("DB_NAME", "go_db"),
 		},
+		Mail: mailConfig{
+			Host:     getEnvOrDefault("SMTP_HOST", ""),
+			Port:     getEnvOrDefault("SMTP_PORT", "587"),
+			Username: getEnvOrDefault("SMTP_USERNAME", ""),
+			Password: getEnvOrDefault("SMTP_PASSWORD", ""),
+			From:     getEnvOrDefault("MAIL_FROM", "no-reply@hireeasy.local"),
+		},
+		Storage: storageConfig{
+			Backend:  getEnvOrDefault("STORAGE_BACKEND", storageBackend),
+			LocalDir: getEnvOrDefault("STORAGE_LOCAL_DIR", "uploads"),
+			BaseURL:  getEnvOrDefault("API_BASE_URL", "http://localhost:8080"),
+
+			SweepInterval:    getDurationOrDefault("STORAGE_SWEEP_INTERVAL", time.Hour),
+			SweepGracePeriod: getDurationOrDefault("STORAGE_SWEEP_GRACE", 24*time.Hour),
+		},
+		Upload: uploadConfig{
+			MaxResumeSize:     getSizeOrDefault("UPLOAD_MAX_RESUME_SIZE", 10<<20),
+			MaxAttachmentSize: getSizeOrDefault("UPLOAD_MAX_ATTACHMENT_SIZE", 10<<20),
+			MaxRequestSize:    getSizeOrDefault("UPLOAD_MAX_REQUEST_SIZE", 50<<20),
+			Scanner:           getEnvOrDefault("MALWARE_SCANNER", "none"),
+			ClamAVAddress:     getEnvOrDefault("CLAMAV_ADDRESS", "tcp://localhost:3310"),
+			ScanTimeout:       getDurationOrDefault("MALWARE_SCAN_TIMEOUT", 30*time.Second),
+		},
+		AppBaseURL:       getEnvOrDefault("APP_BASE_URL", "http://localhost:3000"),
+		CandidateLinkTTL: getDurationOrDefault("CANDIDATE_LINK_TTL", 30*24*time.Hour),
+		ResumeURLTTL:     getDurationOrDefault("RESUME_URL_TTL", 15*time.Minute),
+		PDFRenderer:      getEnvOrDefault("RESUME_PDF_RENDERER", "pdftoppm"),
+
+		CandidateFuzzyMatching: os.Getenv("CANDIDATE_FUZZY_MATCHING") == "true",
 	}
 
 	// Debugging: Print loaded configuration
@@ -87,3 +170,39 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getDurationOrDefault parses a duration environment variable (e.g. "720h") or returns the default
+func getDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: invalid duration for %s (%q), using default: %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// getSizeOrDefault parses a size environment variable in bytes, KB or MB (e.g. "5MB") or returns the default
+func getSizeOrDefault(key string, defaultValue int64) int64 {
+	value := strings.ToUpper(strings.TrimSpace(os.Getenv(key)))
+	if value == "" {
+		return defaultValue
+	}
+
+	unit := int64(1)
+	for suffix, multiplier := range map[string]int64{"KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30} {
+		if strings.HasSuffix(value, suffix) {
+			value, unit = strings.TrimSpace(strings.TrimSuffix(value, suffix)), multiplier
+			break
+		}
+	}
+	size, err := strconv.ParseInt(strings.TrimSuffix(value, "B"), 10, 64)
+	if err != nil || size <= 0 {
+		log.Printf("Warning: invalid size for %s (%q), using default: %d bytes", key, os.Getenv(key), defaultValue)
+		return defaultValue
+	}
+	return size * unit
+}
@@ -20,6 +20,10 @@ CREATE TABLE IF NOT EXISTS jobs (
     job_status VARCHAR(50) NOT NULL DEFAULT 'active', -- active, inactive
     skills_required VARCHAR[] NOT NULL, -- CHECK (array_length(skills_required, 1) > 0), can vaidate in FE
     attributes JSONB, --FE Q&A dump
+    skills_nice_to_have VARCHAR[] NOT NULL DEFAULT '{}', -- weighted lower than skills_required by the ATS scorer
+    min_years_experience INT NOT NULL DEFAULT 0,
+    resubmission_policy VARCHAR(50) NOT NULL DEFAULT 'reject', -- reject, allow_update
+    resubmission_window_days INT NOT NULL DEFAULT 0, -- days after the first application during which allow_update applies
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE (job_id, user_id)
@@ -28,11 +32,15 @@ CREATE TABLE IF NOT EXISTS jobs (
 
 CREATE TABLE IF NOT EXISTS form_templates (
     id SERIAL PRIMARY KEY,
-    form_template_id VARCHAR(255) NOT NULL UNIQUE,
+    form_template_id VARCHAR(255) NOT NULL,
     user_id INTEGER NOT NULL REFERENCES users(id),
+    company_name VARCHAR(255) NOT NULL DEFAULT '', -- copied from users.company_name, template ids are unique per company
+    shared BOOLEAN NOT NULL DEFAULT FALSE, -- listed in the company template library
+    copied_from_id INTEGER REFERENCES form_templates(id) ON DELETE SET NULL,
     fields JSONB NOT NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (company_name, form_template_id)
 );
 
 CREATE TABLE IF NOT EXISTS application_form (
@@ -56,6 +64,17 @@ CREATE TABLE IF NOT EXISTS profiles (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS candidates (
+    id SERIAL PRIMARY KEY,
+    company_name VARCHAR(255) NOT NULL, -- candidates are shared by all users of a company
+    email VARCHAR(255) NOT NULL, -- lowercase, the same email is always the same candidate
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(50) NOT NULL DEFAULT '', -- digits only, used for fuzzy matching
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (company_name, email)
+);
+
 CREATE TABLE IF NOT EXISTS job_submissions (
     id SERIAL PRIMARY KEY,
     form_uuid UUID NOT NULL REFERENCES application_form(form_uuid) ON DELETE CASCADE,
@@ -63,15 +82,51 @@ CREATE TABLE IF NOT EXISTS job_submissions (
     username VARCHAR(255) NOT NULL,
     email VARCHAR(255) NOT NULL,
     form_data JSONB NOT NULL, -- Stores user responses dynamically
-    resume_url TEXT NOT NULL, -- Store S3 URL instead of local path
+    resume_key TEXT NOT NULL, -- key of the resume in the private file storage, downloaded through signed URLs
+    resume_file_name VARCHAR(255) NOT NULL DEFAULT '', -- name the resume was uploaded with
+    resume_text TEXT NOT NULL DEFAULT '', -- plain text extracted from the resume
+    resume_data JSONB, -- structured data parsed from resume_text
     ats_score INTEGER NOT NULL DEFAULT 0, -- ATS ranking score (0-100)
+    ats_breakdown JSONB, -- per-criterion scores explaining ats_score
+    search_vector TSVECTOR, -- full-text document of name, email, skills, form answers and resume text
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, 
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     skills VARCHAR[],
-    status VARCHAR(50) NOT NULL DEFAULT 'applied', -- applied/shortlisted/rejected/finalized
+    status VARCHAR(50) NOT NULL DEFAULT 'applied', -- key of the job's pipeline stage, or withdrawn
+    revision INT NOT NULL DEFAULT 1, -- incremented when the candidate updates the application
+    assigned_to INT REFERENCES users(id) ON DELETE SET NULL, -- recruiter or reviewer responsible for the candidate
+    candidate_id INT REFERENCES candidates(id) ON DELETE SET NULL,
+    candidate_match VARCHAR(20) NOT NULL DEFAULT '', -- email, or phone_name when linked by phone number and a similar name
     UNIQUE (job_id, email)
 );
 
+CREATE TABLE IF NOT EXISTS job_submission_history (
+    id SERIAL PRIMARY KEY,
+    job_submission_id INT NOT NULL REFERENCES job_submissions(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    form_data JSONB NOT NULL,
+    skills VARCHAR[],
+    resume_key TEXT NOT NULL,
+    resume_file_name VARCHAR(255) NOT NULL DEFAULT '',
+    ats_score INTEGER NOT NULL DEFAULT 0,
+    ats_breakdown JSONB,
+    attachments JSONB NOT NULL DEFAULT '[]', -- submission_attachments rows of this version
+    submitted_at TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS submission_attachments (
+    id SERIAL PRIMARY KEY,
+    job_submission_id INT NOT NULL REFERENCES job_submissions(id) ON DELETE CASCADE,
+    field_name VARCHAR(255) NOT NULL, -- question id of the `file` field in the form template
+    file_name VARCHAR(255) NOT NULL, -- original file name
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    file_key TEXT NOT NULL, -- object key, files are only downloaded through signed URLs
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE IF NOT EXISTS availabilities (
     id SERIAL PRIMARY KEY,
     user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
@@ -82,29 +137,219 @@ CREATE TABLE IF NOT EXISTS availabilities (
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
+CREATE TABLE IF NOT EXISTS interview_rounds (
+    id SERIAL PRIMARY KEY,
+    job_id INT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL, -- e.g. screening, technical, culture
+    position INT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (job_id, name)
+);
+
 CREATE TABLE IF NOT EXISTS interviews (
     id SERIAL PRIMARY KEY,
     job_id VARCHAR(255) NOT NULL REFERENCES jobs(job_id),
+    round_id INT REFERENCES interview_rounds(id) ON DELETE SET NULL, -- NULL for interviews scheduled without a round, each is a round of its own
     hr_user_id INT REFERENCES users(id) ON DELETE SET NULL,
     job_submission_id INT NOT NULL REFERENCES job_submissions(id),
     interviewer_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    availability_id INT NOT NULL UNIQUE REFERENCES availabilities(id) ON DELETE CASCADE,
+    availability_id INT REFERENCES availabilities(id) ON DELETE SET NULL, -- NULL once the slot of a cancelled interview is removed
     feedback TEXT DEFAULT NULL,
-    verdict VARCHAR(50) NOT NULL DEFAULT 'pending', -- pending, passed, failed
-    "status" VARCHAR(50) NOT NULL DEFAULT 'scheduled', --pending_feedback, completed 
+    verdict VARCHAR(50) NOT NULL DEFAULT 'pending', -- pending, strong_no, no, yes, strong_yes (passed, failed before scorecards)
+    "status" VARCHAR(50) NOT NULL DEFAULT 'scheduled', --pending_feedback, completed, cancelled
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
+-- Further interviewers of a panel interview, the first interviewer is interviews.interviewer_user_id
+CREATE TABLE IF NOT EXISTS interview_panelists (
+    id SERIAL PRIMARY KEY,
+    interview_id INT NOT NULL REFERENCES interviews(id) ON DELETE CASCADE,
+    interviewer_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    availability_id INT REFERENCES availabilities(id) ON DELETE SET NULL, -- slot of this interviewer
+    cancelled_at TIMESTAMP, -- set when the interview is cancelled, the slot can then be booked again
+    feedback TEXT DEFAULT NULL,
+    verdict VARCHAR(50) NOT NULL DEFAULT 'pending', -- pending, strong_no, no, yes, strong_yes (passed, failed before scorecards)
+    submitted_at TIMESTAMP,
+    UNIQUE (interview_id, interviewer_user_id)
+);
+
+-- Competencies interviewers rate for a job, in order
+CREATE TABLE IF NOT EXISTS scorecard_competencies (
+    id SERIAL PRIMARY KEY,
+    job_id INT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL, -- e.g. problem solving, communication
+    description TEXT NOT NULL DEFAULT '',
+    rating_scale INT NOT NULL DEFAULT 5, -- ratings go from 1 to rating_scale
+    comment_required BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL,
+    UNIQUE (job_id, name)
+);
+
+-- Ratings of each interviewer, name and scale are kept when the scorecard changes
+CREATE TABLE IF NOT EXISTS interview_scores (
+    id SERIAL PRIMARY KEY,
+    interview_id INT NOT NULL REFERENCES interviews(id) ON DELETE CASCADE,
+    interviewer_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    competency_id INT REFERENCES scorecard_competencies(id) ON DELETE SET NULL,
+    competency_name VARCHAR(255) NOT NULL,
+    rating INT NOT NULL,
+    rating_scale INT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Reschedules and cancellations of interviews with their reasons
+CREATE TABLE IF NOT EXISTS interview_changes (
+    id SERIAL PRIMARY KEY,
+    interview_id INT NOT NULL REFERENCES interviews(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE SET NULL, -- HR user or interviewer who made the change
+    action VARCHAR(20) NOT NULL, -- rescheduled, cancelled
+    reason TEXT NOT NULL DEFAULT '',
+    previous_slot VARCHAR(50) NOT NULL DEFAULT '', -- e.g. 2030-01-15 10:00-11:00
+    new_slot VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS form_events (
+    id SERIAL PRIMARY KEY,
+    form_uuid UUID NOT NULL REFERENCES application_form(form_uuid) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL, -- view, start, submit
+    source VARCHAR(255) NOT NULL DEFAULT 'direct', -- utm_source or source param of the form link
+    utm_source VARCHAR(255) NOT NULL DEFAULT '',
+    utm_medium VARCHAR(255) NOT NULL DEFAULT '',
+    utm_campaign VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS pipeline_stages (
+    id SERIAL PRIMARY KEY,
+    job_id INT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    stage_key VARCHAR(50) NOT NULL, -- stored in job_submissions.status
+    name VARCHAR(255) NOT NULL,
+    position INT NOT NULL,
+    stage_type VARCHAR(20) NOT NULL DEFAULT 'active', -- active/hired/rejected
+    transitions VARCHAR[] NOT NULL DEFAULT '{}', -- stage keys reachable from this stage, any later stage when empty
+    UNIQUE (job_id, stage_key)
+);
+
+CREATE TABLE IF NOT EXISTS submission_stage_history (
+    id SERIAL PRIMARY KEY,
+    job_submission_id INT NOT NULL REFERENCES job_submissions(id) ON DELETE CASCADE,
+    from_stage VARCHAR(50), -- NULL for the stage the application started in
+    to_stage VARCHAR(50) NOT NULL,
+    changed_by INT REFERENCES users(id) ON DELETE SET NULL, -- NULL when changed by the candidate
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS submission_tags (
+    job_submission_id INT NOT NULL REFERENCES job_submissions(id) ON DELETE CASCADE,
+    tag VARCHAR(50) NOT NULL, -- lowercase free-form tag
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (job_submission_id, tag)
+);
+
+CREATE TABLE IF NOT EXISTS submission_notes (
+    id SERIAL PRIMARY KEY,
+    job_submission_id INT NOT NULL REFERENCES job_submissions(id) ON DELETE CASCADE,
+    parent_id INT REFERENCES submission_notes(id) ON DELETE CASCADE, -- note this one replies to
+    author_id INT REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE, -- kept as a placeholder for its replies
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS note_mentions (
+    note_id INT NOT NULL REFERENCES submission_notes(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (note_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- recipient
+    type VARCHAR(50) NOT NULL, -- mention
+    actor_id INT REFERENCES users(id) ON DELETE SET NULL,
+    job_submission_id INT REFERENCES job_submissions(id) ON DELETE CASCADE,
+    note_id INT REFERENCES submission_notes(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS email_queue (
+    id SERIAL PRIMARY KEY,
+    job_submission_id INT REFERENCES job_submissions(id) ON DELETE SET NULL,
+    to_email VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending/sending/sent/failed
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    claimed_at TIMESTAMP, -- when a worker started sending
+    sent_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS skills (
+    id SERIAL PRIMARY KEY,
+    company_name VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    aliases VARCHAR[] NOT NULL DEFAULT '{}', -- other spellings normalized to name
+    parent_id INT REFERENCES skills(id) ON DELETE SET NULL, -- category of the skill
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (company_name, name)
+);
+
+CREATE TABLE IF NOT EXISTS job_ranking_weights (
+    job_id INT PRIMARY KEY REFERENCES jobs(id) ON DELETE CASCADE,
+    score_weight INT NOT NULL DEFAULT 50,
+    knockout_weight INT NOT NULL DEFAULT 20,
+    interview_weight INT NOT NULL DEFAULT 20,
+    recency_weight INT NOT NULL DEFAULT 10,
+    recency_days INT NOT NULL DEFAULT 30, -- applications older than this get no recency points
+    exclude_knocked_out BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Add indexes for common queries
 CREATE INDEX IF NOT EXISTS idx_jobs_user_id ON jobs(user_id);
 CREATE INDEX IF NOT EXISTS idx_jobs_id ON jobs(job_id);
 CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(job_status);
 CREATE INDEX IF NOT EXISTS idx_jobs_title ON jobs(job_title);
 
+CREATE INDEX IF NOT EXISTS idx_form_templates_user ON form_templates(user_id);
+
 CREATE INDEX IF NOT EXISTS idx_availabilities_user ON availabilities (user_id, date);
 CREATE INDEX IF NOT EXISTS idx_availabilities_time ON availabilities (date, from_time, to_time);
-CREATE INDEX IF NOT EXISTS idx_interview_availabilities ON interviews (availability_id);
+-- A slot can only be booked by one interview that is not cancelled
+CREATE UNIQUE INDEX IF NOT EXISTS idx_interview_availabilities ON interviews (availability_id) WHERE status <> 'cancelled';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_interview_panelist_availabilities ON interview_panelists (availability_id) WHERE cancelled_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_interview_changes_interview ON interview_changes (interview_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_interviews_submission ON interviews (job_submission_id);
+CREATE INDEX IF NOT EXISTS idx_interview_panelists_interviewer ON interview_panelists (interviewer_user_id);
+CREATE INDEX IF NOT EXISTS idx_interview_rounds_job ON interview_rounds (job_id, position);
+CREATE INDEX IF NOT EXISTS idx_scorecard_competencies_job ON scorecard_competencies (job_id, position);
+CREATE INDEX IF NOT EXISTS idx_interview_scores_interview ON interview_scores (interview_id, interviewer_user_id);
 
 CREATE INDEX  IF NOT EXISTS idx_job_submissions_job ON job_submissions(form_uuid);
-CREATE INDEX  IF NOT EXISTS idx_job_submissions_ats ON job_submissions(ats_score DESC);
\ No newline at end of file
+CREATE INDEX  IF NOT EXISTS idx_job_submissions_ats ON job_submissions(ats_score DESC);
+CREATE INDEX IF NOT EXISTS idx_job_submissions_search ON job_submissions USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_submission_attachments_submission ON submission_attachments(job_submission_id);
+CREATE INDEX IF NOT EXISTS idx_job_submissions_candidate ON job_submissions(candidate_id);
+CREATE INDEX IF NOT EXISTS idx_candidates_phone ON candidates (company_name, phone) WHERE phone <> '';
+CREATE INDEX IF NOT EXISTS idx_job_submission_history_submission ON job_submission_history(job_submission_id);
+
+CREATE INDEX IF NOT EXISTS idx_form_events_form ON form_events (form_uuid, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_skills_company ON skills (company_name);
+CREATE INDEX IF NOT EXISTS idx_submission_tags_tag ON submission_tags (tag);
+CREATE INDEX IF NOT EXISTS idx_submission_notes_submission ON submission_notes (job_submission_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_email_queue_pending ON email_queue (created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_pipeline_stages_job ON pipeline_stages (job_id, position);
+CREATE INDEX IF NOT EXISTS idx_submission_stage_history_submission ON submission_stage_history (job_submission_id, created_at);
@@ -0,0 +1,21 @@
+-- Stores the object key of application attachments instead of their public URL, links are signed when handed out.
+-- URLs of any storage backend, e.g. https://<bucket>.s3.amazonaws.com/attachments/<job>/<file> or
+-- <base url>/api/files/attachments/<job>/<file>, become attachments/<job>/<file>.
+ALTER TABLE submission_attachments ADD COLUMN IF NOT EXISTS file_key TEXT NOT NULL DEFAULT '';
+
+UPDATE submission_attachments
+SET file_key = COALESCE(substring(file_url from '(attachments/.*)$'), '')
+WHERE file_key = '';
+
+-- Attachments archived with earlier versions of a submission
+UPDATE job_submission_history h
+SET attachments = (
+    SELECT COALESCE(jsonb_agg(
+        (attachment - 'file_url') || jsonb_build_object('file_key', COALESCE(substring(attachment->>'file_url' from '(attachments/.*)$'), ''))
+        ORDER BY position), '[]')
+    FROM jsonb_array_elements(h.attachments) WITH ORDINALITY AS a(attachment, position)
+)
+WHERE EXISTS (SELECT 1 FROM jsonb_array_elements(h.attachments) AS a(attachment) WHERE attachment ? 'file_url');
+
+ALTER TABLE submission_attachments ALTER COLUMN file_key DROP DEFAULT;
+ALTER TABLE submission_attachments DROP COLUMN file_url;
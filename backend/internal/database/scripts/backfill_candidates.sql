@@ -0,0 +1,39 @@
+-- Creates the company-wide candidate records for submissions saved before candidates existed
+-- and links the submissions to them by email. Safe to run more than once.
+CREATE TABLE IF NOT EXISTS candidates (
+    id SERIAL PRIMARY KEY,
+    company_name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (company_name, email)
+);
+
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS candidate_id INT REFERENCES candidates(id) ON DELETE SET NULL;
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS candidate_match VARCHAR(20) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_job_submissions_candidate ON job_submissions(candidate_id);
+CREATE INDEX IF NOT EXISTS idx_candidates_phone ON candidates (company_name, phone) WHERE phone <> '';
+
+INSERT INTO candidates (company_name, email, name, created_at, updated_at)
+SELECT DISTINCT ON (u.company_name, LOWER(js.email))
+    u.company_name, LOWER(js.email), js.username, js.created_at, js.updated_at
+FROM job_submissions js
+JOIN application_form af ON js.form_uuid = af.form_uuid
+JOIN jobs j ON af.job_id = j.id
+JOIN users u ON j.user_id = u.id
+WHERE js.candidate_id IS NULL
+ORDER BY u.company_name, LOWER(js.email), js.created_at DESC
+ON CONFLICT (company_name, email) DO NOTHING;
+
+UPDATE job_submissions js
+SET candidate_id = c.id, candidate_match = 'email'
+FROM application_form af, jobs j, users u, candidates c
+WHERE js.form_uuid = af.form_uuid
+    AND af.job_id = j.id
+    AND j.user_id = u.id
+    AND c.company_name = u.company_name
+    AND c.email = LOWER(js.email)
+    AND js.candidate_id IS NULL;
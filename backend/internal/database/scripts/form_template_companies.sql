@@ -0,0 +1,17 @@
+-- Scopes form template ids per company and adds the shared template library.
+-- Existing templates get the company of their owner and stay private.
+ALTER TABLE form_templates ADD COLUMN IF NOT EXISTS company_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE form_templates ADD COLUMN IF NOT EXISTS shared BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE form_templates ADD COLUMN IF NOT EXISTS copied_from_id INTEGER REFERENCES form_templates(id) ON DELETE SET NULL;
+
+UPDATE form_templates ft
+SET company_name = u.company_name
+FROM users u
+WHERE ft.user_id = u.id AND ft.company_name = '';
+
+-- Template ids were unique across all companies, now they are unique within a company
+ALTER TABLE form_templates DROP CONSTRAINT IF EXISTS form_templates_form_template_id_key;
+ALTER TABLE form_templates DROP CONSTRAINT IF EXISTS form_templates_company_name_form_template_id_key;
+ALTER TABLE form_templates ADD CONSTRAINT form_templates_company_name_form_template_id_key UNIQUE (company_name, form_template_id);
+
+CREATE INDEX IF NOT EXISTS idx_form_templates_user ON form_templates(user_id);
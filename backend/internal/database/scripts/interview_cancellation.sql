@@ -0,0 +1,30 @@
+-- Cancelled interviews are kept and release their slots, reschedules and cancellations are recorded.
+-- Run after interview_rounds.sql.
+ALTER TABLE interviews DROP CONSTRAINT IF EXISTS interviews_availability_id_key;
+ALTER TABLE interviews DROP CONSTRAINT IF EXISTS interviews_availability_id_fkey;
+ALTER TABLE interviews ALTER COLUMN availability_id DROP NOT NULL;
+ALTER TABLE interviews ADD CONSTRAINT interviews_availability_id_fkey
+    FOREIGN KEY (availability_id) REFERENCES availabilities(id) ON DELETE SET NULL;
+
+ALTER TABLE interview_panelists DROP CONSTRAINT IF EXISTS interview_panelists_availability_id_key;
+ALTER TABLE interview_panelists DROP CONSTRAINT IF EXISTS interview_panelists_availability_id_fkey;
+ALTER TABLE interview_panelists ALTER COLUMN availability_id DROP NOT NULL;
+ALTER TABLE interview_panelists ADD CONSTRAINT interview_panelists_availability_id_fkey
+    FOREIGN KEY (availability_id) REFERENCES availabilities(id) ON DELETE SET NULL;
+ALTER TABLE interview_panelists ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS interview_changes (
+    id SERIAL PRIMARY KEY,
+    interview_id INT NOT NULL REFERENCES interviews(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    previous_slot VARCHAR(50) NOT NULL DEFAULT '',
+    new_slot VARCHAR(50) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+DROP INDEX IF EXISTS idx_interview_availabilities;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_interview_availabilities ON interviews (availability_id) WHERE status <> 'cancelled';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_interview_panelist_availabilities ON interview_panelists (availability_id) WHERE cancelled_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_interview_changes_interview ON interview_changes (interview_id, created_at);
@@ -0,0 +1,27 @@
+-- Interview rounds per job and panel interviews with several interviewers.
+-- Existing interviews keep their single interviewer and no round.
+CREATE TABLE IF NOT EXISTS interview_rounds (
+    id SERIAL PRIMARY KEY,
+    job_id INT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (job_id, name)
+);
+
+ALTER TABLE interviews ADD COLUMN IF NOT EXISTS round_id INT REFERENCES interview_rounds(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS interview_panelists (
+    id SERIAL PRIMARY KEY,
+    interview_id INT NOT NULL REFERENCES interviews(id) ON DELETE CASCADE,
+    interviewer_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    availability_id INT NOT NULL UNIQUE REFERENCES availabilities(id) ON DELETE CASCADE,
+    feedback TEXT DEFAULT NULL,
+    verdict VARCHAR(50) NOT NULL DEFAULT 'pending',
+    submitted_at TIMESTAMP,
+    UNIQUE (interview_id, interviewer_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interviews_submission ON interviews (job_submission_id);
+CREATE INDEX IF NOT EXISTS idx_interview_panelists_interviewer ON interview_panelists (interviewer_user_id);
+CREATE INDEX IF NOT EXISTS idx_interview_rounds_job ON interview_rounds (job_id, position);
@@ -0,0 +1,26 @@
+-- Per-job scorecards rated by interviewers. Existing feedback keeps its passed/failed verdict.
+CREATE TABLE IF NOT EXISTS scorecard_competencies (
+    id SERIAL PRIMARY KEY,
+    job_id INT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    rating_scale INT NOT NULL DEFAULT 5,
+    comment_required BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL,
+    UNIQUE (job_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS interview_scores (
+    id SERIAL PRIMARY KEY,
+    interview_id INT NOT NULL REFERENCES interviews(id) ON DELETE CASCADE,
+    interviewer_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    competency_id INT REFERENCES scorecard_competencies(id) ON DELETE SET NULL,
+    competency_name VARCHAR(255) NOT NULL,
+    rating INT NOT NULL,
+    rating_scale INT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_scorecard_competencies_job ON scorecard_competencies (job_id, position);
+CREATE INDEX IF NOT EXISTS idx_interview_scores_interview ON interview_scores (interview_id, interviewer_user_id);
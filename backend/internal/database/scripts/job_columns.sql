@@ -0,0 +1,6 @@
+-- Adds the job columns introduced after the jobs table was first created:
+-- ATS scoring criteria and the policy for candidates applying again. Safe to run more than once.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS skills_nice_to_have VARCHAR[] NOT NULL DEFAULT '{}';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS min_years_experience INT NOT NULL DEFAULT 0;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS resubmission_policy VARCHAR(50) NOT NULL DEFAULT 'reject';
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS resubmission_window_days INT NOT NULL DEFAULT 0;
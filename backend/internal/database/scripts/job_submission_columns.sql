@@ -0,0 +1,20 @@
+-- Adds the submission columns introduced after the job_submissions table was first created:
+-- resume text and parsed data, the ATS breakdown, the search document, revisions and assignments.
+-- Existing submissions get an empty resume text and revision 1. Safe to run more than once.
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS resume_text TEXT NOT NULL DEFAULT '';
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS resume_data JSONB;
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS ats_breakdown JSONB;
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS revision INT NOT NULL DEFAULT 1;
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS assigned_to INT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_job_submissions_search ON job_submissions USING GIN (search_vector);
+
+-- Search only matches stored documents through the index, earlier rows need theirs computed once
+UPDATE job_submissions js
+SET search_vector = (
+    setweight(to_tsvector('simple', js.username || ' ' || js.email || ' ' || translate(js.email, '@._-', '    ')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(array_to_string(js.skills, ' '), '')), 'A') ||
+    setweight(jsonb_to_tsvector('english', js.form_data, '["string"]'), 'B') ||
+    setweight(to_tsvector('english', js.resume_text), 'C'))
+WHERE js.search_vector IS NULL;
@@ -0,0 +1,5 @@
+-- Keeps the name resumes were uploaded with, their storage keys no longer contain it.
+-- Existing resumes keep an empty name and are downloaded as <username>_resume.<ext>.
+-- Run before cmd/rekey-resumes.
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS resume_file_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE job_submission_history ADD COLUMN IF NOT EXISTS resume_file_name VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,17 @@
+-- Stores the object key of resumes instead of their public URL.
+-- URLs of the form https://<bucket>.s3.amazonaws.com/<key> become <key>.
+ALTER TABLE job_submissions ADD COLUMN IF NOT EXISTS resume_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE job_submission_history ADD COLUMN IF NOT EXISTS resume_key TEXT NOT NULL DEFAULT '';
+
+UPDATE job_submissions
+SET resume_key = regexp_replace(resume_url, '^https?://[^/]+/', '')
+WHERE resume_key = '';
+
+UPDATE job_submission_history
+SET resume_key = regexp_replace(resume_url, '^https?://[^/]+/', '')
+WHERE resume_key = '';
+
+ALTER TABLE job_submissions ALTER COLUMN resume_key DROP DEFAULT;
+ALTER TABLE job_submission_history ALTER COLUMN resume_key DROP DEFAULT;
+ALTER TABLE job_submissions DROP COLUMN resume_url;
+ALTER TABLE job_submission_history DROP COLUMN resume_url;
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// ATS scoring criteria
+const (
+	ATSCriterionRequiredSkills   = "required_skills"
+	ATSCriterionNiceToHaveSkills = "nice_to_have_skills"
+	ATSCriterionExperience       = "experience"
+)
+
+// ATSScoreBreakdown explains how the ATS score of a submission was calculated
+type ATSScoreBreakdown struct {
+	Score    int                 `json:"score"`
+	Scorer   string              `json:"scorer"` // name of the scorer that produced the score
+	Criteria []ATSCriterionScore `json:"criteria"`
+}
+
+// ATSCriterionScore is the result of a single scoring criterion.
+// Points is the contribution to the total score, i.e. Score scaled by Weight.
+type ATSCriterionScore struct {
+	Criterion string   `json:"criterion"`
+	Weight    int      `json:"weight"` // share of the total score in percent
+	Score     int      `json:"score"`  // 0-100 within the criterion
+	Points    float64  `json:"points"`
+	Matched   []string `json:"matched,omitempty"`
+	Missing   []string `json:"missing,omitempty"`
+	Detail    string   `json:"detail,omitempty"`
+}
+
+// Value stores the breakdown as JSONB
+func (b ATSScoreBreakdown) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+// Scan reads the breakdown from a JSONB column
+func (b *ATSScoreBreakdown) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, b)
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	default:
+		return fmt.Errorf("unsupported type for ats breakdown: %T", src)
+	}
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Bulk actions on submissions
+const (
+	BulkActionMove   = "move"
+	BulkActionReject = "reject"
+	BulkActionTag    = "tag"
+	BulkActionAssign = "assign"
+)
+
+// BulkSubmissionRequest applies one action to many submissions in a single transaction
+type BulkSubmissionRequest struct {
+	SubmissionIDs []int  `json:"submission_ids" binding:"required,min=1,max=500"`
+	Action        string `json:"action" binding:"required,oneof=move reject tag assign"`
+
+	Stage      string   `json:"stage" binding:"max=50"`            // target stage key of move
+	Reason     string   `json:"reason" binding:"max=1000"`         // recorded in the stage history of move and reject
+	Tags       []string `json:"tags" binding:"max=20,dive,max=50"` // added by tag
+	AssigneeID *int     `json:"assignee_id"`                       // user of the company to assign, empty unassigns
+
+	// Optional email queued for every candidate the action succeeded for
+	Email *EmailTemplate `json:"email"`
+}
+
+// EmailTemplate is a candidate email with placeholders:
+// {{name}}, {{job_title}}, {{company}}, {{stage}} and {{status_link}}
+type EmailTemplate struct {
+	Subject string `json:"subject" binding:"required,max=255"`
+	Body    string `json:"body" binding:"required,max=10000"`
+}
+
+// BulkActionResult is the outcome of a bulk action for one submission
+type BulkActionResult struct {
+	SubmissionID int    `json:"submission_id"`
+	Success      bool   `json:"success"`
+	Status       string `json:"status,omitempty"` // stage of the submission after the action
+	EmailQueued  bool   `json:"email_queued,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// BulkActionResponse summarizes a bulk action
+type BulkActionResponse struct {
+	Action       string             `json:"action"`
+	Succeeded    int                `json:"succeeded"`
+	Failed       int                `json:"failed"`
+	EmailsQueued int                `json:"emails_queued"`
+	Results      []BulkActionResult `json:"results"`
+}
+
+// QueuedEmail is an email waiting in the outgoing email queue
+type QueuedEmail struct {
+	ID              int        `json:"id" db:"id"`
+	JobSubmissionID *int       `json:"job_submission_id,omitempty" db:"job_submission_id"`
+	ToEmail         string     `json:"to_email" db:"to_email"`
+	Subject         string     `json:"subject" db:"subject"`
+	Body            string     `json:"body" db:"body"`
+	Status          string     `json:"status" db:"status"` // pending/sending/sent/failed
+	Attempts        int        `json:"attempts" db:"attempts"`
+	LastError       string     `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	SentAt          *time.Time `json:"sent_at,omitempty" db:"sent_at"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// How a submission was linked to its candidate
+const (
+	CandidateMatchEmail = "email"
+	CandidateMatchFuzzy = "phone_name" // same phone number and a similar name, different email
+)
+
+// Candidate is a person who applied to one or more jobs of a company, deduplicated by email
+type Candidate struct {
+	ID               int       `json:"id" db:"id"`
+	CompanyName      string    `json:"company_name" db:"company_name"`
+	Email            string    `json:"email" db:"email"`
+	Name             string    `json:"name" db:"name"`
+	Phone            string    `json:"phone,omitempty" db:"phone"`
+	ApplicationCount int       `json:"application_count" db:"application_count"`
+	LastAppliedAt    time.Time `json:"last_applied_at" db:"last_applied_at"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CandidateListRequest holds the query parameters of the candidate list
+type CandidateListRequest struct {
+	Q        string `form:"q"` // part of the name or email
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// CandidateListResult is a page of candidates
+type CandidateListResult struct {
+	Candidates []Candidate `json:"candidates"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// CandidateApplication is one of the applications of a candidate
+type CandidateApplication struct {
+	SubmissionID int            `json:"submission_id" db:"id"`
+	JobID        string         `json:"job_id" db:"job_id"`
+	JobTitle     string         `json:"job_title" db:"job_title"`
+	Email        string         `json:"email" db:"email"`
+	Status       string         `json:"status" db:"status"`
+	ATSScore     int            `json:"ats_score" db:"ats_score"`
+	Revision     int            `json:"revision" db:"revision"`
+	MatchedBy    string         `json:"matched_by" db:"candidate_match"`
+	Tags         pq.StringArray `json:"tags" db:"tags"`
+	AppliedAt    time.Time      `json:"applied_at" db:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// CandidateInterview is an interview of a candidate with the interviewer's feedback
+type CandidateInterview struct {
+	ID              int       `json:"id" db:"id"`
+	JobSubmissionID int       `json:"job_submission_id" db:"job_submission_id"`
+	JobID           string    `json:"job_id" db:"job_id"`
+	InterviewerID   int       `json:"interviewer_user_id" db:"interviewer_user_id"`
+	InterviewerName string    `json:"interviewer_name" db:"interviewer_name"`
+	Date            string    `json:"date" db:"date"`
+	FromTime        string    `json:"from_time" db:"from_time"`
+	ToTime          string    `json:"to_time" db:"to_time"`
+	Status          string    `json:"status" db:"status"`
+	Verdict         string    `json:"verdict" db:"verdict"`
+	Feedback        string    `json:"feedback,omitempty" db:"feedback"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// CandidateProfile is the candidate with all their applications, interviews and feedback
+type CandidateProfile struct {
+	Candidate
+	Applications []CandidateApplication `json:"applications"`
+	Interviews   []CandidateInterview   `json:"interviews"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Submission status set by the candidate through the status page
+const SubmissionStatusWithdrawn = "withdrawn"
+
+// CandidateApplicationStatus is what a candidate sees on the magic-link status page.
+// It deliberately leaves out internal data such as ATS scores and recruiter notes.
+type CandidateApplicationStatus struct {
+	SubmissionID    int       `json:"submission_id"`
+	JobID           string    `json:"job_id"`
+	JobTitle        string    `json:"job_title"`
+	CompanyName     string    `json:"company_name"`
+	Name            string    `json:"name"`
+	Email           string    `json:"email"`
+	Status          string    `json:"status"`
+	Stage           string    `json:"stage"` // human readable status
+	Revision        int       `json:"revision"`
+	AppliedAt       time.Time `json:"applied_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	CanWithdraw     bool      `json:"can_withdraw"`
+	CanUpdateResume bool      `json:"can_update_resume"`
+}
+
+// RequestStatusLinkRequest asks for a new magic link to be emailed, e.g. after the previous one expired
+type RequestStatusLinkRequest struct {
+	JobID string `json:"job_id" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// CandidateSearchRequest holds the query parameters of the candidate search.
+// Q is matched against name, email, skills, form answers and resume text.
+type CandidateSearchRequest struct {
+	Q        string `form:"q"`
+	JobID    string `form:"job_id"`
+	Status   string `form:"status"`
+	Skill    string `form:"skill"`
+	MinScore *int   `form:"min_score" binding:"omitempty,min=0,max=100"`
+	MaxScore *int   `form:"max_score" binding:"omitempty,min=0,max=100"`
+	FromDate string `form:"from_date" binding:"omitempty,datetime=2006-01-02"` // applied on or after, YYYY-MM-DD
+	ToDate   string `form:"to_date" binding:"omitempty,datetime=2006-01-02"`   // applied on or before, YYYY-MM-DD
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// CandidateSearchHit is a submission matching the candidate search
+type CandidateSearchHit struct {
+	ID          int            `json:"id" db:"id"`
+	JobID       string         `json:"job_id" db:"job_id"`
+	JobTitle    string         `json:"job_title" db:"job_title"`
+	Username    string         `json:"username" db:"username"`
+	Email       string         `json:"email" db:"email"`
+	Skills      pq.StringArray `json:"skills" db:"skills"`
+	ResumeKey   string         `json:"-" db:"resume_key"`
+	ResumeURL   string         `json:"resume_url" db:"-"` // expiring signed URL
+	ATSScore    int            `json:"ats_score" db:"ats_score"`
+	Status      string         `json:"status" db:"status"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	CandidateID *int           `json:"candidate_id" db:"candidate_id"`
+	Rank        float64        `json:"rank" db:"rank"`                     // full-text relevance, 0 without a search term
+	Highlight   string         `json:"highlight,omitempty" db:"highlight"` // resume excerpt around the matched terms
+}
+
+// Pagination describes the page of results returned by a paginated endpoint
+type Pagination struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// CandidateSearchResult is a page of candidate search results
+type CandidateSearchResult struct {
+	Results    []CandidateSearchHit `json:"results"`
+	Pagination Pagination           `json:"pagination"`
+}
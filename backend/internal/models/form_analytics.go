@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Form event types recorded against an application form
+const (
+	FormEventView   = "view"
+	FormEventStart  = "start"
+	FormEventSubmit = "submit"
+)
+
+// FormTracking carries the source/UTM parameters captured from a shared form link
+type FormTracking struct {
+	Source      string `json:"source" form:"source"`
+	UTMSource   string `json:"utm_source" form:"utm_source"`
+	UTMMedium   string `json:"utm_medium" form:"utm_medium"`
+	UTMCampaign string `json:"utm_campaign" form:"utm_campaign"`
+}
+
+// FormEvent represents a single view/start/submit event for an application form
+type FormEvent struct {
+	ID          int       `json:"id" db:"id"`
+	FormUUID    string    `json:"form_uuid" db:"form_uuid"`
+	EventType   string    `json:"event_type" db:"event_type"`
+	Source      string    `json:"source" db:"source"`
+	UTMSource   string    `json:"utm_source,omitempty" db:"utm_source"`
+	UTMMedium   string    `json:"utm_medium,omitempty" db:"utm_medium"`
+	UTMCampaign string    `json:"utm_campaign,omitempty" db:"utm_campaign"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// RecordFormEventRequest is sent by the public form page when a candidate starts filling in the form.
+// Views are recorded when the form is fetched and submissions when the application is saved.
+type RecordFormEventRequest struct {
+	EventType string `json:"event_type" binding:"required,oneof=start"`
+	FormTracking
+}
+
+// FunnelStats holds the counts and rates for one slice of the form funnel
+type FunnelStats struct {
+	Views          int     `json:"views" db:"views"`
+	Starts         int     `json:"starts" db:"starts"`
+	Submissions    int     `json:"submissions" db:"submissions"`
+	ConversionRate float64 `json:"conversion_rate"` // submissions / views, in percent
+	StartRate      float64 `json:"start_rate"`      // starts / views, in percent
+	DropOff        int     `json:"drop_off"`        // started but never submitted
+}
+
+type SourceFunnel struct {
+	Source string `json:"source" db:"source"`
+	FunnelStats
+}
+
+type DailyFunnel struct {
+	Day    string `json:"day" db:"day"`
+	Source string `json:"source,omitempty" db:"source"`
+	FunnelStats
+}
+
+// FormFunnelResponse is the per-form analytics report returned to HR
+type FormFunnelResponse struct {
+	FormUUID       string         `json:"form_uuid"`
+	FromDate       string         `json:"from_date,omitempty"`
+	ToDate         string         `json:"to_date,omitempty"`
+	Totals         FunnelStats    `json:"totals"`
+	BySource       []SourceFunnel `json:"by_source"`
+	ByDay          []DailyFunnel  `json:"by_day"`
+	BySourceAndDay []DailyFunnel  `json:"by_source_and_day"`
+}
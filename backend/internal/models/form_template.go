@@ -8,7 +8,19 @@ type FormTemplate struct {
     ID             int       `json:"id" db:"id"`
     FormTemplateID string    `json:"form_template_id" binding:"required" db:"form_template_id"`
     UserID         int       `json:"user_id" db:"user_id"`
+    CompanyName    string    `json:"company_name,omitempty" db:"company_name"`
+    Shared         bool      `json:"shared" db:"shared"` // visible in the company template library
+    CopiedFromID   *int      `json:"copied_from_id,omitempty" db:"copied_from_id"`
+    OwnerUsername  string    `json:"owner_username,omitempty" db:"owner_username"`
     Fields         [] map[string]interface{}  `json:"fields" db:"fields"`
     CreatedAt      time.Time `json:"created_at" db:"created_at"`
     UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+}
+
+type ShareFormTemplateRequest struct {
+    Shared *bool `json:"shared" binding:"required"`
+}
+
+type CopyFormTemplateRequest struct {
+    NewFormTemplateID string `json:"new_form_template_id" binding:"required"`
+}
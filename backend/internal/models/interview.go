@@ -4,18 +4,58 @@ import (
     "time"
     )
 
+// Interview verdicts. Interviewers decide strong no to strong yes, passed and failed are
+// the outcome of a round and the verdicts of feedback given before scorecards.
+const (
+	InterviewVerdictPending   = "pending"
+	InterviewVerdictPassed    = "passed"
+	InterviewVerdictFailed    = "failed"
+	InterviewVerdictStrongNo  = "strong_no"
+	InterviewVerdictNo        = "no"
+	InterviewVerdictYes       = "yes"
+	InterviewVerdictStrongYes = "strong_yes"
+)
+
+// Interview statuses
+const (
+	InterviewStatusScheduled       = "scheduled"
+	InterviewStatusPendingFeedback = "pending_feedback"
+	InterviewStatusCompleted       = "completed"
+	InterviewStatusCancelled       = "cancelled"
+)
+
+// Interview changes
+const (
+	InterviewChangeRescheduled = "rescheduled"
+	InterviewChangeCancelled   = "cancelled"
+)
+
 type Interview struct {
 	ID               int       `json:"id" db:"id" binding:"required"`
 	JobID            string       `json:"job_id,omitempty" db:"job_id" binding:"required"`
+	RoundID          *int      `json:"round_id,omitempty" db:"round_id"`
 	HRUserID         int       `json:"hr_user_id,omitempty" db:"hr_user_id" binding:"required"`
 	JobSubmissionID  int       `json:"job_submission_id,omitempty" db:"job_submission_id" binding:"required"`
 	InterviewerID    int       `json:"interviewer_user_id,omitempty" db:"interviewer_user_id" binding:"required"`
 	AvailabilityID   int       `json:"availability_id,omitempty" db:"availability_id" binding:"required"`
+	Date             string    `json:"date,omitempty" db:"date"`           // of the slot, YYYY-MM-DD
+	FromTime         string    `json:"from_time,omitempty" db:"from_time"` // HH:MM
+	ToTime           string    `json:"to_time,omitempty" db:"to_time"`     // HH:MM
 	Feedback         string   `json:"feedback,omitempty" db:"feedback"`
 	Verdict          string    `json:"verdict,omitempty" db:"verdict"`
 	Status           string    `json:"status,omitempty" db:"status" binding:"required"`
 	CreatedAt        time.Time `json:"created_at,omitempty" db:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at,omitempty" db:"updated_at"`
+	Panelists        []InterviewPanelist `json:"panelists,omitempty" db:"-"` // further interviewers of a panel interview
+	Changes          []InterviewChange   `json:"changes,omitempty" db:"-"`   // reschedules and cancellations, oldest first
+}
+
+// InterviewPanelist is a further interviewer of a panel interview with their own feedback
+type InterviewPanelist struct {
+	InterviewerID  int    `json:"interviewer_user_id" db:"interviewer_user_id"`
+	AvailabilityID int    `json:"availability_id" db:"availability_id"`
+	Feedback       string `json:"feedback,omitempty" db:"feedback"`
+	Verdict        string `json:"verdict" db:"verdict"`
 }
 
 type CreateInterviewRequest struct {
@@ -23,8 +63,79 @@ type CreateInterviewRequest struct {
 	JobSubmissionID int `json:"job_submission_id" binding:"required"`
 	InterviewerID   int `json:"interviewer_user_id" binding:"required"`
 	AvailabilityID  int `json:"availability_id" binding:"required"`
+	RoundID         *int `json:"round_id"` // optional, the interview is a round of its own without it
+	Panelists       []PanelistRequest `json:"panelists" binding:"omitempty,dive"` // further interviewers of a panel interview
+}
+
+// PanelistRequest adds an interviewer to a panel interview with one of their own availability slots
+type PanelistRequest struct {
+	InterviewerID  int `json:"interviewer_user_id" binding:"required"`
+	AvailabilityID int `json:"availability_id" binding:"required"`
 }
+// FeedbackRequest is the verdict of an interviewer with the ratings of the job's scorecard
 type FeedbackRequest struct {
-	Verdict  string `json:"verdict" binding:"required"`
-	Feedback string `json:"feedback" binding:"required"`
+	Verdict  string         `json:"verdict" binding:"required,oneof=strong_no no yes strong_yes"`
+	Feedback string         `json:"feedback" binding:"max=5000"` // overall notes, required when the job has no scorecard
+	Scores   []ScoreRequest `json:"scores" binding:"omitempty,dive"`   // one per competency of the scorecard
+}
+
+// ScoreRequest rates one competency of the scorecard
+type ScoreRequest struct {
+	CompetencyID int    `json:"competency_id" binding:"required"`
+	Rating       int    `json:"rating" binding:"required,min=1"`
+	Comment      string `json:"comment" binding:"max=2000"`
+}
+
+// InterviewRound is a step of a job's interview loop, e.g. screening, technical or culture
+type InterviewRound struct {
+	ID        int       `json:"id" db:"id"`
+	JobID     string    `json:"job_id" db:"-"`
+	Name      string    `json:"name" db:"name"`
+	Position  int       `json:"position" db:"position"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateInterviewRoundRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// InterviewerFeedback is the verdict of one interviewer of an interview
+type InterviewerFeedback struct {
+	InterviewID     int    `json:"interview_id" db:"interview_id"`
+	InterviewerID   int    `json:"interviewer_user_id" db:"interviewer_user_id"`
+	InterviewerName string `json:"interviewer_name" db:"interviewer_name"`
+	Status          string `json:"status" db:"status"`
+	Verdict         string `json:"verdict" db:"verdict"`
+	Feedback        string `json:"feedback,omitempty" db:"feedback"`
+}
+
+// InterviewRoundResult is the outcome of an interview round for one submission
+type InterviewRoundResult struct {
+	RoundID  *int                  `json:"round_id"` // nil for an interview scheduled without a round
+	Name     string                `json:"name"`
+	Verdict  string                `json:"verdict"` // aggregate of all interviewers of the round
+	Feedback []InterviewerFeedback `json:"feedback"`
+}
+
+// InterviewChange records a reschedule or cancellation of an interview
+type InterviewChange struct {
+	ID           int       `json:"id" db:"id"`
+	UserID       *int      `json:"user_id" db:"user_id"` // who made the change, nil when the user was deleted
+	Action       string    `json:"action" db:"action"`   // rescheduled or cancelled
+	Reason       string    `json:"reason" db:"reason"`
+	PreviousSlot string    `json:"previous_slot" db:"previous_slot"` // e.g. 2030-01-15 10:00-11:00
+	NewSlot      string    `json:"new_slot,omitempty" db:"new_slot"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// RescheduleInterviewRequest moves an interview to another slot of its interviewer.
+// Panelists listed move to the given slots of their own, the others keep theirs.
+type RescheduleInterviewRequest struct {
+	AvailabilityID int               `json:"availability_id" binding:"required"`
+	Panelists      []PanelistRequest `json:"panelists" binding:"omitempty,dive"`
+	Reason         string            `json:"reason" binding:"required,max=1000"`
+}
+
+type CancelInterviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
 }
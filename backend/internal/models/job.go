@@ -16,4 +16,18 @@ type Job struct {
     CreatedAt       time.Time            `json:"created_at,omitempty" db:"created_at"`
     UpdatedAt       time.Time            `json:"updated_at,omitempty" db:"updated_at"`
     Attributes      map[string]interface{} `json:"attributes,omitempty" db:"attributes"`
-};
\ No newline at end of file
+
+    // Used by the ATS scorer together with SkillsRequired
+    SkillsNiceToHave   []string `json:"skills_nice_to_have,omitempty" db:"skills_nice_to_have"`
+    MinYearsExperience int      `json:"min_years_experience,omitempty" binding:"omitempty,min=0,max=50" db:"min_years_experience"`
+
+    // What happens when a candidate applies again with the same email
+    ResubmissionPolicy     string `json:"resubmission_policy,omitempty" binding:"omitempty,oneof=reject allow_update" db:"resubmission_policy"`
+    ResubmissionWindowDays int    `json:"resubmission_window_days,omitempty" binding:"omitempty,min=0,max=365" db:"resubmission_window_days"` // days after the first application during which updates are allowed
+};
+
+// Resubmission policies of a job
+const (
+    ResubmissionPolicyReject      = "reject"       // repeated applications are rejected with 409
+    ResubmissionPolicyAllowUpdate = "allow_update" // candidates may update their application through their magic link within the window, previous versions are kept
+)
\ No newline at end of file
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"mime/multipart"
 	"time"
 
@@ -16,20 +17,81 @@ type FormSubmissionRequest struct {
 	FormData string                `form:"form_data" binding:"required"`
 	FormUUID string                `form:"form_uuid" binding:"required"`
 	Resume   *multipart.FileHeader `form:"resume" binding:"required"`
+
+	// Magic link token of the candidate, required to update an earlier application
+	Token string `form:"token"`
+
+	// Optional source/UTM parameters forwarded from the form link, used for form analytics
+	FormTracking
 }
 
 // JobSubmission represents a job application in the database
 type JobSubmission struct {
-	ID        int            `json:"id" db:"id"`
-	JobID     string         `json:"job_id" db:"job_id"`
-	Username  string         `json:"username" db:"username"`
-	Email     string         `json:"email" db:"email"`
-	FormData  []byte         `json:"-" db:"form_data"` // Store raw JSON, process later
-	FormUUID  string         `json:"form_uuid" db:"form_uuid"`
-	Skills    pq.StringArray `json:"skills" db:"skills"`
-	ResumeURL string         `json:"resume_url" db:"resume_url"`
-	ATSScore  int            `json:"ats_score" db:"ats_score"`
-	Status    string         `json:"status" db:"status"`
-	CreatedAt time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+	ID             int            `json:"id" db:"id"`
+	JobID          string         `json:"job_id" db:"job_id"`
+	Username       string         `json:"username" db:"username"`
+	Email          string         `json:"email" db:"email"`
+	FormData       []byte         `json:"-" db:"form_data"` // Store raw JSON, process later
+	FormUUID       string         `json:"form_uuid" db:"form_uuid"`
+	Skills         pq.StringArray `json:"skills" db:"skills"`
+	ResumeKey      string         `json:"-" db:"resume_key"`                      // the resume is private, HR downloads it through signed URLs
+	ResumeFileName string         `json:"resume_file_name" db:"resume_file_name"` // name the resume was uploaded with
+	ResumeText     string         `json:"-" db:"resume_text"`                     // plain text extracted from the resume, used by scoring and search
+	ATSScore       int            `json:"ats_score" db:"ats_score"`
+	Status         string         `json:"status" db:"status"`
+	Revision       int            `json:"revision" db:"revision"` // incremented every time the candidate updates the application
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+
+	// Contact details, work history, education and skills parsed from the resume
+	ResumeData *ParsedResume `json:"resume_data,omitempty" db:"resume_data"`
+
+	// Per-criterion explanation of ATSScore
+	ATSBreakdown *ATSScoreBreakdown `json:"ats_breakdown,omitempty" db:"ats_breakdown"`
+
+	// Company-wide candidate the application belongs to, and whether it matched by email or fuzzily
+	CandidateID    *int   `json:"candidate_id,omitempty" db:"candidate_id"`
+	CandidateMatch string `json:"candidate_match,omitempty" db:"candidate_match"`
+
+	// Additional files declared by `file` fields of the form template
+	Attachments []SubmissionAttachment `json:"attachments" db:"-"`
+}
+
+// SubmissionAttachment is an additional file uploaded with a job application
+type SubmissionAttachment struct {
+	ID              int       `json:"id" db:"id"`
+	JobSubmissionID int       `json:"job_submission_id" db:"job_submission_id"`
+	FieldName       string    `json:"field_name" db:"field_name"` // question id of the `file` field in the form template
+	FileName        string    `json:"file_name" db:"file_name"`   // original file name
+	ContentType     string    `json:"content_type" db:"content_type"`
+	SizeBytes       int64     `json:"size_bytes" db:"size_bytes"`
+	FileKey         string    `json:"-" db:"file_key"`
+	FileURL         string    `json:"file_url,omitempty" db:"-"` // expiring signed URL
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// SubmissionRevision is an archived version of an application that the candidate later updated
+type SubmissionRevision struct {
+	ID              int                    `json:"id" db:"id"`
+	JobSubmissionID int                    `json:"job_submission_id" db:"job_submission_id"`
+	Revision        int                    `json:"revision" db:"revision"`
+	Username        string                 `json:"username" db:"username"`
+	FormData        json.RawMessage        `json:"form_data" db:"form_data"`
+	Skills          pq.StringArray         `json:"skills" db:"skills"`
+	ResumeKey       string                 `json:"-" db:"resume_key"`
+	ResumeFileName  string                 `json:"resume_file_name" db:"resume_file_name"`
+	ResumeURL       string                 `json:"resume_url" db:"-"` // expiring signed URL
+	ATSScore        int                    `json:"ats_score" db:"ats_score"`
+	ATSBreakdown    *ATSScoreBreakdown     `json:"ats_breakdown,omitempty" db:"ats_breakdown"`
+	Attachments     []SubmissionAttachment `json:"attachments" db:"-"`
+	SubmittedAt     time.Time              `json:"submitted_at" db:"submitted_at"`
+	ArchivedAt      time.Time              `json:"archived_at" db:"archived_at"`
+}
+
+// ResumePreview lets recruiters glance at a resume without downloading it
+type ResumePreview struct {
+	JobSubmissionID int    `json:"job_submission_id"`
+	Revision        int    `json:"revision,omitempty"` // 0 for the current resume
+	HTML            string `json:"html"`               // sanitized HTML of the extracted text, empty when no text could be extracted
+	ThumbnailURL    string `json:"thumbnail_url"`      // expiring signed URL of the first page image, empty when none could be rendered
 }
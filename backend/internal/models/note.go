@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Notification types
+const NotificationTypeMention = "mention"
+
+// SubmissionNote is an internal recruiter note on a submission, never shown to the candidate.
+// Replies reference the note they answer through ParentID.
+type SubmissionNote struct {
+	ID              int              `json:"id" db:"id"`
+	JobSubmissionID int              `json:"job_submission_id" db:"job_submission_id"`
+	ParentID        *int             `json:"parent_id,omitempty" db:"parent_id"`
+	AuthorID        *int             `json:"author_id,omitempty" db:"author_id"`
+	AuthorName      string           `json:"author_name" db:"author_name"`
+	Body            string           `json:"body" db:"body"`
+	Mentions        []string         `json:"mentions" db:"-"`                // usernames of the mentioned teammates
+	Deleted         bool             `json:"deleted,omitempty" db:"deleted"` // kept as a placeholder while it has replies
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+	Replies         []SubmissionNote `json:"replies" db:"-"`
+}
+
+// CreateNoteRequest adds a note or a reply to a submission, @username mentions notify teammates
+type CreateNoteRequest struct {
+	Body     string `json:"body" binding:"required,max=5000"`
+	ParentID *int   `json:"parent_id"`
+}
+
+// UpdateNoteRequest changes the text of a note
+type UpdateNoteRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+// SubmissionTagsRequest adds free-form tags to a submission
+type SubmissionTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20,dive,max=50"`
+}
+
+// Notification tells a user about something that needs their attention, e.g. a mention in a note
+type Notification struct {
+	ID              int        `json:"id" db:"id"`
+	Type            string     `json:"type" db:"type"`
+	ActorID         *int       `json:"actor_id,omitempty" db:"actor_id"`
+	ActorName       string     `json:"actor_name" db:"actor_name"`
+	JobSubmissionID *int       `json:"job_submission_id,omitempty" db:"job_submission_id"`
+	NoteID          *int       `json:"note_id,omitempty" db:"note_id"`
+	Message         string     `json:"message" db:"message"`
+	ReadAt          *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// ParsedResume is the structured data read from the text of a resume.
+// Every field carries a confidence between 0 and 1, fields that were not found have confidence 0.
+type ParsedResume struct {
+	Name  ParsedField `json:"name"`
+	Email ParsedField `json:"email"`
+	Phone ParsedField `json:"phone"`
+
+	WorkHistory []ParsedWorkExperience `json:"work_history"`
+	Education   []ParsedEducation      `json:"education"`
+
+	Skills           []string `json:"skills"` // normalized skill names
+	SkillsConfidence float64  `json:"skills_confidence"`
+
+	YearsOfExperience           float64 `json:"years_of_experience"` // total of the work history, overlaps counted once
+	YearsOfExperienceConfidence float64 `json:"years_of_experience_confidence"`
+}
+
+// ParsedField is a single value read from a resume
+type ParsedField struct {
+	Value      string  `json:"value"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ParsedWorkExperience is a position of the work history.
+// Dates are formatted as YYYY-MM, or YYYY when the resume gives no month.
+type ParsedWorkExperience struct {
+	Title      string  `json:"title"`
+	Company    string  `json:"company"`
+	StartDate  string  `json:"start_date"`
+	EndDate    string  `json:"end_date,omitempty"` // empty for the current position
+	Current    bool    `json:"current"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ParsedEducation is a degree of the education section
+type ParsedEducation struct {
+	Degree         string  `json:"degree"`
+	Institution    string  `json:"institution"`
+	GraduationYear int     `json:"graduation_year,omitempty"`
+	Confidence     float64 `json:"confidence"`
+}
+
+// Value stores the parsed resume as JSONB
+func (r ParsedResume) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan reads the parsed resume from a JSONB column
+func (r *ParsedResume) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, r)
+	case string:
+		return json.Unmarshal([]byte(v), r)
+	default:
+		return fmt.Errorf("unsupported type for parsed resume: %T", src)
+	}
+}
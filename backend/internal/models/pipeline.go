@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Pipeline stage types. Hired and rejected stages end the process for the candidate.
+const (
+	StageTypeActive   = "active"
+	StageTypeHired    = "hired"
+	StageTypeRejected = "rejected"
+)
+
+// PipelineStage is a step of a job's hiring pipeline, job_submissions.status holds its key
+type PipelineStage struct {
+	ID       int    `json:"id,omitempty" db:"id"`
+	Key      string `json:"key" binding:"omitempty,max=50" db:"stage_key"` // derived from the name when empty
+	Name     string `json:"name" binding:"required,max=255" db:"name"`
+	Position int    `json:"position" db:"position"`
+	Type     string `json:"type" binding:"omitempty,oneof=active hired rejected" db:"stage_type"`
+
+	// Keys of the stages a candidate may be moved to from this stage.
+	// When empty, any later stage is allowed. Rejected stages can always be reached from active ones.
+	Transitions pq.StringArray `json:"transitions" db:"transitions"`
+}
+
+// Pipeline is the ordered list of stages of a job
+type Pipeline struct {
+	JobID     string          `json:"job_id"`
+	IsDefault bool            `json:"is_default"` // the job has not configured its own stages
+	Stages    []PipelineStage `json:"stages"`
+}
+
+// UpdatePipelineRequest replaces the stages of a job, in order
+type UpdatePipelineRequest struct {
+	Stages []PipelineStage `json:"stages" binding:"required,min=1,dive"`
+}
+
+// MoveSubmissionRequest moves a submission to another stage of the job's pipeline
+type MoveSubmissionRequest struct {
+	Status string `json:"status" binding:"required,max=50"` // key of the target stage
+	Reason string `json:"reason" binding:"max=1000"`
+}
+
+// StageChange is an entry of the stage history of a submission
+type StageChange struct {
+	ID              int       `json:"id" db:"id"`
+	JobSubmissionID int       `json:"job_submission_id" db:"job_submission_id"`
+	FromStage       string    `json:"from_stage,omitempty" db:"from_stage"` // empty for the initial stage
+	ToStage         string    `json:"to_stage" db:"to_stage"`
+	ChangedBy       *int      `json:"changed_by,omitempty" db:"changed_by"` // empty when the candidate or the system changed it
+	ChangedByName   string    `json:"changed_by_name,omitempty" db:"changed_by_name"`
+	Reason          string    `json:"reason,omitempty" db:"reason"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Ranking factors, each scored 0-100 and weighted by the job's ranking weights
+const (
+	RankingFactorScore      = "ats_score"
+	RankingFactorKnockout   = "knockout"
+	RankingFactorInterviews = "interviews"
+	RankingFactorRecency    = "recency"
+)
+
+// RankingWeights tune how the candidates of a job are ranked. Weights are relative to each other.
+type RankingWeights struct {
+	Score             int  `json:"score" binding:"min=0,max=100" db:"score_weight"`
+	Knockout          int  `json:"knockout" binding:"min=0,max=100" db:"knockout_weight"`
+	Interviews        int  `json:"interviews" binding:"min=0,max=100" db:"interview_weight"`
+	Recency           int  `json:"recency" binding:"min=0,max=100" db:"recency_weight"`
+	RecencyDays       int  `json:"recency_days" binding:"omitempty,min=1,max=365" db:"recency_days"` // applications older than this get no recency points, 30 when empty
+	ExcludeKnockedOut bool `json:"exclude_knocked_out" db:"exclude_knocked_out"`                     // leave candidates failing a knockout criterion out of the shortlist
+}
+
+// JobRankingWeights are the ranking weights of a job
+type JobRankingWeights struct {
+	JobID     string `json:"job_id"`
+	IsDefault bool   `json:"is_default"` // the job has not configured its own weights
+	RankingWeights
+}
+
+// RankingRequest holds the query parameters of the ranking endpoint
+type RankingRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"` // size of the shortlist, 10 when empty
+}
+
+// RankingComponent is the contribution of a single factor to the ranking score.
+// Points is Score scaled by Weight.
+type RankingComponent struct {
+	Factor string  `json:"factor"`
+	Weight int     `json:"weight"` // share of the ranking score in percent
+	Score  int     `json:"score"`  // 0-100 within the factor
+	Points float64 `json:"points"`
+	Detail string  `json:"detail"`
+}
+
+// RankedCandidate is a candidate of the shortlist of a job
+type RankedCandidate struct {
+	Rank         int                `json:"rank"`
+	SubmissionID int                `json:"submission_id"`
+	CandidateID  *int               `json:"candidate_id"`
+	Username     string             `json:"username"`
+	Email        string             `json:"email"`
+	Status       string             `json:"status"`
+	ATSScore     int                `json:"ats_score"`
+	RankingScore float64            `json:"ranking_score"` // 0-100
+	KnockedOut   bool               `json:"knocked_out"`
+	Explanation  string             `json:"explanation"`
+	Components   []RankingComponent `json:"components"`
+	AppliedAt    time.Time          `json:"applied_at"`
+}
+
+// JobRanking is the shortlist of the best candidates of a job
+type JobRanking struct {
+	JobID      string            `json:"job_id"`
+	Weights    RankingWeights    `json:"weights"`
+	Candidates int               `json:"candidates"` // number of ranked candidates, the shortlist holds the top ones
+	Shortlist  []RankedCandidate `json:"shortlist"`
+}
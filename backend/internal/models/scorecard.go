@@ -0,0 +1,56 @@
+package models
+
+// ScorecardCompetency is a competency interviewers rate for a job, e.g. problem solving
+type ScorecardCompetency struct {
+	ID              int    `json:"id" db:"id"`
+	Name            string `json:"name" db:"name"`
+	Description     string `json:"description,omitempty" db:"description"`
+	RatingScale     int    `json:"rating_scale" db:"rating_scale"` // ratings go from 1 to RatingScale
+	CommentRequired bool   `json:"comment_required" db:"comment_required"`
+	Position        int    `json:"position" db:"position"`
+}
+
+// Scorecard is the template interviewers fill in for a job, without competencies feedback is free text
+type Scorecard struct {
+	JobID        string                `json:"job_id"`
+	Competencies []ScorecardCompetency `json:"competencies"`
+}
+
+// UpdateScorecardRequest replaces the competencies of a job's scorecard, an empty list removes the scorecard.
+// Competencies keep their id when their name is unchanged.
+type UpdateScorecardRequest struct {
+	Competencies []ScorecardCompetencyRequest `json:"competencies" binding:"omitempty,dive"`
+}
+
+type ScorecardCompetencyRequest struct {
+	Name            string `json:"name" binding:"required,max=255"`
+	Description     string `json:"description" binding:"max=1000"`
+	RatingScale     int    `json:"rating_scale" binding:"omitempty,min=2,max=10"` // 5 when omitted
+	CommentRequired bool   `json:"comment_required"`
+}
+
+// InterviewerRating is the rating of one interviewer for a competency
+type InterviewerRating struct {
+	InterviewID     int    `json:"interview_id" db:"interview_id"`
+	InterviewerID   int    `json:"interviewer_user_id" db:"interviewer_user_id"`
+	InterviewerName string `json:"interviewer_name" db:"interviewer_name"`
+	Rating          int    `json:"rating" db:"rating"`
+	Comment         string `json:"comment,omitempty" db:"comment"`
+}
+
+// CompetencyScores are the ratings of all interviewers for a competency
+type CompetencyScores struct {
+	CompetencyID  *int                `json:"competency_id"` // nil once the competency was removed from the scorecard
+	Name          string              `json:"name"`
+	RatingScale   int                 `json:"rating_scale"`
+	AverageRating float64             `json:"average_rating"`
+	Ratings       []InterviewerRating `json:"ratings"`
+}
+
+// CandidateScorecard aggregates the scorecards of all interviewers of a submission
+type CandidateScorecard struct {
+	JobSubmissionID int                `json:"job_submission_id"`
+	Verdict         string             `json:"verdict"`  // pending, passed or failed across all interviewers
+	Verdicts        map[string]int     `json:"verdicts"` // number of interviewers per verdict
+	Competencies    []CompetencyScores `json:"competencies"`
+}
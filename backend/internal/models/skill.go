@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// Skill is an entry of a company's skills taxonomy.
+// Aliases are other spellings that are normalized to Name, ParentID points to the category the skill belongs to.
+type Skill struct {
+	ID          int       `json:"id" db:"id"`
+	CompanyName string    `json:"company_name,omitempty" db:"company_name"`
+	Name        string    `json:"name" binding:"required,max=255" db:"name"`
+	Aliases     []string  `json:"aliases" db:"aliases"`
+	ParentID    *int      `json:"parent_id,omitempty" db:"parent_id"`
+	ParentName  string    `json:"parent_name,omitempty" db:"parent_name"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SkillSuggestion is a result of the skills autocomplete
+type SkillSuggestion struct {
+	Name         string `json:"name"`                    // canonical name to use
+	MatchedAlias string `json:"matched_alias,omitempty"` // alias that matched the query, if not the name
+	Parent       string `json:"parent,omitempty"`
+}
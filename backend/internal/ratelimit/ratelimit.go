@@ -0,0 +1,59 @@
+// Package ratelimit counts requests per key, e.g. per client IP or email address, in fixed time windows.
+// Counts are kept in memory, so every server instance limits on its own.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter allows up to limit requests per key in each window
+type Limiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	counts    map[string]*windowCount
+	lastPrune time.Time
+}
+
+type windowCount struct {
+	start time.Time
+	count int
+}
+
+// New returns a limiter allowing limit requests per key in each window
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{limit: limit, window: window, counts: map[string]*windowCount{}}
+}
+
+// Allow counts a request for the key and reports whether it is within the limit
+func (l *Limiter) Allow(key string) bool {
+	return l.allow(key, time.Now())
+}
+
+func (l *Limiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget keys whose window has ended, so the map does not grow with every client ever seen
+	if now.Sub(l.lastPrune) >= l.window {
+		for k, c := range l.counts {
+			if now.Sub(c.start) >= l.window {
+				delete(l.counts, k)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	c, ok := l.counts[key]
+	if !ok || now.Sub(c.start) >= l.window {
+		c = &windowCount{start: now}
+		l.counts[key] = c
+	}
+	if c.count >= l.limit {
+		return false
+	}
+	c.count++
+	return true
+}
@@ -0,0 +1,27 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	limiter := New(2, time.Hour)
+	now := time.Date(2030, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	assert.True(t, limiter.allow("1.2.3.4", now))
+	assert.True(t, limiter.allow("1.2.3.4", now.Add(time.Minute)))
+	assert.False(t, limiter.allow("1.2.3.4", now.Add(2*time.Minute)))
+
+	// Keys are counted separately
+	assert.True(t, limiter.allow("5.6.7.8", now.Add(2*time.Minute)))
+
+	// A new window starts once the previous one has ended
+	assert.True(t, limiter.allow("1.2.3.4", now.Add(time.Hour)))
+
+	// Keys whose window ended are forgotten
+	limiter.allow("9.9.9.9", now.Add(3*time.Hour))
+	assert.Len(t, limiter.counts, 1)
+}
@@ -0,0 +1,118 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamAVChunkSize is the size of the chunks streamed to clamd, well below its default StreamMaxLength
+const clamAVChunkSize = 32 << 10
+
+// ClamAVScanner streams files to a clamd daemon with the INSTREAM command.
+// Files are sent in chunks as they are read, they are never held in memory.
+type ClamAVScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVScanner returns a scanner for clamd listening on tcp://host:port, host:port or unix:///path/to/clamd.sock
+func NewClamAVScanner(address string, timeout time.Duration) (*ClamAVScanner, error) {
+	scanner := &ClamAVScanner{network: "tcp", address: address, timeout: timeout}
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		scanner.network, scanner.address = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		scanner.address = strings.TrimPrefix(address, "tcp://")
+	case strings.Contains(address, "://"):
+		return nil, fmt.Errorf("unsupported clamd address %q", address)
+	}
+	if scanner.address == "" {
+		return nil, errors.New("clamd address is required")
+	}
+	return scanner, nil
+}
+
+func (s *ClamAVScanner) Scan(ctx context.Context, body io.Reader) (*Result, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("clamd connection error: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// clamd answers as soon as it rejects the stream (e.g. size limit exceeded), so a write
+	// error is only reported when there is no answer to read
+	writeErr := writeInstream(conn, body)
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		if writeErr != nil {
+			return nil, fmt.Errorf("clamd stream error: %w", writeErr)
+		}
+		return nil, fmt.Errorf("clamd reply error: %w", err)
+	}
+	return parseClamAVReply(reply)
+}
+
+// writeInstream sends the body as length-prefixed chunks followed by a zero-length chunk
+func writeInstream(w io.Writer, body io.Reader) error {
+	if _, err := io.WriteString(w, "zINSTREAM\x00"); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, clamAVChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := body.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, werr := w.Write(size); werr != nil {
+				return werr
+			}
+			if _, werr := w.Write(chunk[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	binary.BigEndian.PutUint32(size, 0)
+	_, err := w.Write(size)
+	return err
+}
+
+// parseClamAVReply reads replies such as "stream: OK" or "stream: Eicar-Test-Signature FOUND"
+func parseClamAVReply(reply string) (*Result, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	status := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+
+	switch {
+	case status == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &Result{Infected: true, Signature: strings.TrimSuffix(status, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd error: %s", reply)
+	}
+}
@@ -0,0 +1,47 @@
+// Package scanner checks uploaded files for malware before they are stored.
+// The scanner is selected by the MALWARE_SCANNER setting.
+package scanner
+
+import (
+	"backend/internal/config"
+	"context"
+	"fmt"
+	"io"
+)
+
+// Scanners
+const (
+	BackendNone   = "none"
+	BackendClamAV = "clamav"
+)
+
+// Result is the verdict of a scan
+type Result struct {
+	Infected  bool
+	Signature string // name of the detected malware, e.g. Eicar-Test-Signature
+}
+
+// Scanner reads a file to the end and reports whether it is infected.
+// An error means the file could not be checked, it is not a verdict.
+type Scanner interface {
+	Scan(ctx context.Context, body io.Reader) (*Result, error)
+}
+
+// New returns the scanner selected by the configuration
+func New(cfg *config.Config) (Scanner, error) {
+	switch cfg.Upload.Scanner {
+	case BackendNone, "":
+		return NoopScanner{}, nil
+	case BackendClamAV:
+		return NewClamAVScanner(cfg.Upload.ClamAVAddress, cfg.Upload.ScanTimeout)
+	default:
+		return nil, fmt.Errorf("unknown malware scanner %q", cfg.Upload.Scanner)
+	}
+}
+
+// NoopScanner accepts every file, it is used when no scanner is configured
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(ctx context.Context, body io.Reader) (*Result, error) {
+	return &Result{}, nil
+}
@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd answers INSTREAM commands like clamd, files containing the EICAR test string are infected
+func fakeClamd(t *testing.T, reply func(data []byte) string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				if command, err := r.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+					io.WriteString(conn, "UNKNOWN COMMAND\x00")
+					return
+				}
+
+				var data []byte
+				size := make([]byte, 4)
+				for {
+					if _, err := io.ReadFull(r, size); err != nil {
+						return
+					}
+					n := binary.BigEndian.Uint32(size)
+					if n == 0 {
+						break
+					}
+					chunk := make([]byte, n)
+					if _, err := io.ReadFull(r, chunk); err != nil {
+						return
+					}
+					data = append(data, chunk...)
+				}
+				io.WriteString(conn, reply(data)+"\x00")
+			}(conn)
+		}
+	}()
+	return "tcp://" + listener.Addr().String()
+}
+
+func clamdReply(data []byte) string {
+	if bytes.Contains(data, []byte(eicar)) {
+		return "stream: Eicar-Test-Signature FOUND"
+	}
+	return "stream: OK"
+}
+
+func TestClamAVScanner(t *testing.T) {
+	scanner, err := NewClamAVScanner(fakeClamd(t, clamdReply), time.Second)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	result, err := scanner.Scan(ctx, strings.NewReader("%PDF-1.4 clean resume"))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	// Larger than one chunk, the signature spans two chunks
+	infected := strings.Repeat("a", clamAVChunkSize-10) + eicar
+	result, err = scanner.Scan(ctx, strings.NewReader(infected))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+}
+
+func TestClamAVScannerErrors(t *testing.T) {
+	scanner, err := NewClamAVScanner(fakeClamd(t, func([]byte) string {
+		return "INSTREAM size limit exceeded. ERROR"
+	}), time.Second)
+	require.NoError(t, err)
+	_, err = scanner.Scan(context.Background(), strings.NewReader("resume"))
+	assert.ErrorContains(t, err, "size limit exceeded")
+
+	// Nothing listening
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	listener.Close()
+	scanner, err = NewClamAVScanner(address, time.Second)
+	require.NoError(t, err)
+	_, err = scanner.Scan(context.Background(), strings.NewReader("resume"))
+	assert.Error(t, err)
+
+	_, err = NewClamAVScanner("http://localhost:3310", time.Second)
+	assert.Error(t, err)
+}
+
+func TestNewClamAVScannerAddress(t *testing.T) {
+	scanner, err := NewClamAVScanner("unix:///var/run/clamav/clamd.ctl", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "unix", scanner.network)
+	assert.Equal(t, "/var/run/clamav/clamd.ctl", scanner.address)
+
+	scanner, err = NewClamAVScanner("localhost:3310", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "tcp", scanner.network)
+	assert.Equal(t, "localhost:3310", scanner.address)
+}
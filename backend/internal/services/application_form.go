@@ -37,8 +37,11 @@ func LinkJobToFormTemplate(ctx context.Context, jobID string, formTemplateID str
         return nil, err
     }
 
-    // Check if form template exists
-    err = db.QueryRowContext(ctx, "SELECT id FROM form_templates WHERE form_template_id = $1 and user_id= $2", formTemplateID, userID).Scan(&dbFormID)
+    // Check if form template exists, either owned by the user or shared in the company template library
+    err = db.QueryRowContext(ctx, `SELECT id FROM form_templates
+        WHERE form_template_id = $1
+        AND company_name = (SELECT company_name FROM users WHERE id = $2)
+        AND (user_id = $2 OR shared)`, formTemplateID, userID).Scan(&dbFormID)
     if err != nil {
         if err == sql.ErrNoRows {
             return nil, ErrFormTemplateNotFound
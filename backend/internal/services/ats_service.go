@@ -1,45 +1,333 @@
 package services
 
 import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
 	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
 )
 
-// MockATSScore calculates ATS score based on candidate and job-required skills
-func MockATSScore(candidateSkills []string, requiredSkills []string) int {
-	if len(requiredSkills) == 0 {
-		return 50 // Default score if no required skills are provided
+// ATSScoreInput is everything a scorer may use to rate an application against a job
+type ATSScoreInput struct {
+	CandidateSkills []string
+	FormData        map[string]interface{}
+	ResumeText      string               // extracted text of the resume, empty if it could not be read
+	ParsedResume    *models.ParsedResume // structured resume data, nil if the resume could not be read
+	Taxonomy        *SkillTaxonomy       // skills taxonomy of the job's company, the built-in one if nil
+
+	RequiredSkills     []string
+	NiceToHaveSkills   []string
+	MinYearsExperience int
+}
+
+// ATSScorer rates an application from 0 to 100 and explains the result per criterion
+type ATSScorer interface {
+	Score(input ATSScoreInput) models.ATSScoreBreakdown
+}
+
+// neutralATSScore is used when the job defines nothing to score against
+const neutralATSScore = 50
+
+// experienceFieldKeys are the form answers checked for the candidate's years of experience, in order
+var experienceFieldKeys = []string{"q_experience", "q_yearsofexperience", "q_years_of_experience", "years_of_experience", "experience"}
+
+var numberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// DefaultATSScorer weighs required skills, nice-to-have skills and years of experience.
+// Criteria the job does not define are left out and the remaining weights are scaled up.
+// Skills are compared after normalization against the skills taxonomy.
+type DefaultATSScorer struct {
+	RequiredSkillsWeight   int
+	NiceToHaveSkillsWeight int
+	ExperienceWeight       int
+}
+
+// NewDefaultATSScorer returns the scorer used unless another one is configured
+func NewDefaultATSScorer() *DefaultATSScorer {
+	return &DefaultATSScorer{
+		RequiredSkillsWeight:   60,
+		NiceToHaveSkillsWeight: 20,
+		ExperienceWeight:       20,
+	}
+}
+
+func (s *DefaultATSScorer) Score(input ATSScoreInput) models.ATSScoreBreakdown {
+	breakdown := models.ATSScoreBreakdown{Scorer: "default", Criteria: []models.ATSCriterionScore{}}
+	taxonomy := input.Taxonomy
+	if taxonomy == nil {
+		taxonomy = DefaultSkillTaxonomy()
+	}
+
+	candidateSkills := skillKeys(taxonomy, input.CandidateSkills)
+	resume := resumeEvidence{text: strings.ToLower(input.ResumeText), skills: map[string]bool{}}
+	if input.ParsedResume != nil {
+		resume.skills = skillKeys(taxonomy, input.ParsedResume.Skills)
+	}
+
+	if len(input.RequiredSkills) > 0 {
+		breakdown.Criteria = append(breakdown.Criteria,
+			scoreSkills(models.ATSCriterionRequiredSkills, s.RequiredSkillsWeight, taxonomy, candidateSkills, resume, input.RequiredSkills))
+	}
+	if len(input.NiceToHaveSkills) > 0 {
+		breakdown.Criteria = append(breakdown.Criteria,
+			scoreSkills(models.ATSCriterionNiceToHaveSkills, s.NiceToHaveSkillsWeight, taxonomy, candidateSkills, resume, input.NiceToHaveSkills))
+	}
+	if input.MinYearsExperience > 0 {
+		breakdown.Criteria = append(breakdown.Criteria,
+			scoreExperience(s.ExperienceWeight, input.FormData, input.ParsedResume, input.MinYearsExperience))
+	}
+
+	totalWeight := 0
+	for _, criterion := range breakdown.Criteria {
+		totalWeight += criterion.Weight
+	}
+	if totalWeight == 0 {
+		breakdown.Criteria = []models.ATSCriterionScore{}
+		breakdown.Score = neutralATSScore
+		return breakdown
+	}
+
+	// Scale the weights of the applicable criteria so they add up to 100
+	total := 0.0
+	for i := range breakdown.Criteria {
+		criterion := &breakdown.Criteria[i]
+		weight := float64(criterion.Weight) * 100 / float64(totalWeight)
+		criterion.Weight = int(math.Round(weight))
+		criterion.Points = math.Round(float64(criterion.Score)*weight) / 100
+		total += float64(criterion.Score) * weight / 100
+	}
+
+	breakdown.Score = int(math.Round(total))
+	if breakdown.Score > 100 {
+		breakdown.Score = 100
+	}
+	return breakdown
+}
+
+// skillKeys returns the normalized keys of a list of skills
+func skillKeys(taxonomy *SkillTaxonomy, skills []string) map[string]bool {
+	keys := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		if key := taxonomy.Key(skill); key != "" {
+			keys[key] = true
+		}
+	}
+	return keys
+}
+
+// resumeEvidence is what the resume tells about the candidate's skills
+type resumeEvidence struct {
+	text   string          // lowercased resume text
+	skills map[string]bool // normalized skills parsed from the resume
+}
+
+// scoreSkills rates the share of the job's skills the candidate listed or mentioned in the resume
+func scoreSkills(criterion string, weight int, taxonomy *SkillTaxonomy, candidateSkills map[string]bool, resume resumeEvidence, jobSkills []string) models.ATSCriterionScore {
+	result := models.ATSCriterionScore{Criterion: criterion, Weight: weight, Matched: []string{}, Missing: []string{}}
+
+	fromResume := 0
+	seen := map[string]bool{}
+	for _, skill := range jobSkills {
+		normalized := taxonomy.Key(skill)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+
+		if candidateSkills[normalized] {
+			result.Matched = append(result.Matched, skill)
+		} else if resume.skills[normalized] || resumeMentionsSkill(taxonomy, resume.text, skill) {
+			result.Matched = append(result.Matched, skill)
+			fromResume++
+		} else {
+			result.Missing = append(result.Missing, skill)
+		}
+	}
+
+	if len(seen) > 0 {
+		result.Score = int(math.Round(float64(len(result.Matched)) * 100 / float64(len(seen))))
 	}
+	result.Detail = fmt.Sprintf("%d of %d skills matched", len(result.Matched), len(seen))
+	if fromResume > 0 {
+		result.Detail += fmt.Sprintf(", %d found only in the resume", fromResume)
+	}
+	return result
+}
+
+// resumeMentionsSkill reports whether the lowercased resume text mentions the skill or one of its aliases
+func resumeMentionsSkill(taxonomy *SkillTaxonomy, resumeText string, skill string) bool {
+	if resumeText == "" {
+		return false
+	}
+	for _, term := range taxonomy.Terms(skill) {
+		if containsTerm(resumeText, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsTerm finds term in text as a whole word, so "go" does not match "google"
+func containsTerm(text string, term string) bool {
+	for offset := 0; offset < len(text); {
+		index := strings.Index(text[offset:], term)
+		if index < 0 {
+			return false
+		}
+		start := offset + index
+		end := start + len(term)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (start == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+			return true
+		}
+		offset = start + 1
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#'
+}
+
+// scoreExperience rates the candidate's years of experience against the job's minimum.
+// The form answer wins over the work history parsed from the resume.
+func scoreExperience(weight int, formData map[string]interface{}, parsedResume *models.ParsedResume, minYears int) models.ATSCriterionScore {
+	result := models.ATSCriterionScore{Criterion: models.ATSCriterionExperience, Weight: weight}
+
+	source := ""
+	years, ok := yearsOfExperience(formData)
+	if !ok && parsedResume != nil && parsedResume.YearsOfExperienceConfidence > 0 {
+		years, ok, source = parsedResume.YearsOfExperience, true, " (from resume work history)"
+	}
+	if !ok {
+		result.Detail = fmt.Sprintf("years of experience not provided, %d required", minYears)
+		return result
+	}
+
+	result.Score = 100
+	if years < float64(minYears) {
+		result.Score = int(math.Round(years * 100 / float64(minYears)))
+	}
+	result.Detail = fmt.Sprintf("%s years of experience%s, %d required", strconv.FormatFloat(years, 'f', -1, 64), source, minYears)
+	return result
+}
 
-	// Normalize skills (convert to lowercase)
-	normalize := func(skills []string) map[string]bool {
-		skillMap := make(map[string]bool)
-		for _, skill := range skills {
-			skillMap[strings.ToLower(skill)] = true
+// yearsOfExperience reads the years of experience from the form answers.
+// Answers may be numbers or text such as "5+ years", in which case the first number is used.
+// Other questions mentioning experience are tried in the order of their keys, so the result never depends on map order.
+func yearsOfExperience(formData map[string]interface{}) (float64, bool) {
+	keys := make([]string, 0, len(formData))
+	for key := range formData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	answers := make(map[string]interface{}, len(formData))
+	for _, key := range keys {
+		if _, ok := answers[strings.ToLower(key)]; !ok {
+			answers[strings.ToLower(key)] = formData[key]
 		}
-		return skillMap
 	}
 
-	candidateSkillsMap := normalize(candidateSkills)
-	requiredSkillsMap := normalize(requiredSkills)
+	for _, key := range experienceFieldKeys {
+		if years, ok := parseYears(answers[key]); ok {
+			return years, true
+		}
+	}
+	for _, key := range keys {
+		if strings.Contains(strings.ToLower(key), "experience") {
+			if years, ok := parseYears(formData[key]); ok {
+				return years, true
+			}
+		}
+	}
+	return 0, false
+}
 
-	// Count matching skills
-	matchCount := 0
-	for skill := range candidateSkillsMap {
-		if requiredSkillsMap[skill] {
-			matchCount++
+func parseYears(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, v >= 0
+	case string:
+		match := numberPattern.FindString(v)
+		if match == "" {
+			return 0, false
 		}
+		years, err := strconv.ParseFloat(match, 64)
+		return years, err == nil
+	default:
+		return 0, false
 	}
+}
 
-	// Calculate match percentage
-	matchPercentage := (float64(matchCount) / float64(len(requiredSkills))) * 100
+var atsScorer ATSScorer
 
-	// Scale score between 50 and 100
-	score := 50 + int(math.Round(matchPercentage/2))
+// GetATSScorer returns the scorer used for new applications
+func GetATSScorer() ATSScorer {
+	if atsScorer != nil {
+		return atsScorer
+	}
+	return NewDefaultATSScorer()
+}
 
-	if score > 100 {
-		score = 100
+// SetATSScorer overrides the scorer, e.g. with a custom implementation or a fake in tests
+func SetATSScorer(s ATSScorer) {
+	atsScorer = s
+}
+
+// getJobScoringCriteria loads what the job of the application form expects from candidates
+func getJobScoringCriteria(ctx context.Context, formUUID string) (ATSScoreInput, error) {
+	db := database.GetDB()
+
+	var input ATSScoreInput
+	var requiredSkills, niceToHaveSkills pq.StringArray
+	err := db.QueryRowContext(ctx, `
+		SELECT j.skills_required, j.skills_nice_to_have, j.min_years_experience
+		FROM application_form af
+		JOIN jobs j ON af.job_id = j.id
+		WHERE af.form_uuid = $1`, formUUID).Scan(&requiredSkills, &niceToHaveSkills, &input.MinYearsExperience)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return input, ErrFormNotFound
+		}
+		return input, err
+	}
+
+	input.RequiredSkills = requiredSkills
+	input.NiceToHaveSkills = niceToHaveSkills
+	return input, nil
+}
+
+// scoreApplication rates an application against the job of its form with the configured scorer.
+// candidate holds what is known about the applicant, the job criteria are filled in here.
+func scoreApplication(ctx context.Context, formUUID string, candidate ATSScoreInput) (*models.ATSScoreBreakdown, error) {
+	input, err := getJobScoringCriteria(ctx, formUUID)
+	if err != nil {
+		return nil, err
+	}
+	input.CandidateSkills = candidate.CandidateSkills
+	input.FormData = candidate.FormData
+	input.ResumeText = candidate.ResumeText
+	input.ParsedResume = candidate.ParsedResume
+
+	input.Taxonomy = candidate.Taxonomy
+	if input.Taxonomy == nil {
+		if input.Taxonomy, err = getFormSkillTaxonomy(ctx, formUUID); err != nil {
+			return nil, err
+		}
 	}
 
-	return score
+	breakdown := GetATSScorer().Score(input)
+	return &breakdown, nil
 }
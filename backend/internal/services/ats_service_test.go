@@ -0,0 +1,125 @@
+package services
+
+import (
+	"backend/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultATSScorer(t *testing.T) {
+	scorer := NewDefaultATSScorer()
+
+	testCases := []struct {
+		name          string
+		input         ATSScoreInput
+		expectedScore int
+		criteria      []string
+	}{
+		{
+			name:          "No job criteria",
+			input:         ATSScoreInput{CandidateSkills: []string{"Go"}},
+			expectedScore: neutralATSScore,
+			criteria:      []string{},
+		},
+		{
+			name: "All required skills only",
+			input: ATSScoreInput{
+				CandidateSkills: []string{"golang", "Postgres"},
+				RequiredSkills:  []string{"Go", "PostgreSQL"},
+			},
+			expectedScore: 100,
+			criteria:      []string{models.ATSCriterionRequiredSkills},
+		},
+		{
+			name: "Half of required skills",
+			input: ATSScoreInput{
+				CandidateSkills: []string{"Go"},
+				RequiredSkills:  []string{"Go", "Docker"},
+			},
+			expectedScore: 50,
+			criteria:      []string{models.ATSCriterionRequiredSkills},
+		},
+		{
+			name: "Required, nice to have and experience",
+			input: ATSScoreInput{
+				CandidateSkills:    []string{"Go", "Docker", "k8s"},
+				FormData:           map[string]interface{}{"Q_Experience": "2 years"},
+				RequiredSkills:     []string{"Go", "Docker"},
+				NiceToHaveSkills:   []string{"Kubernetes", "AWS"},
+				MinYearsExperience: 4,
+			},
+			// 60 * 1.0 + 20 * 0.5 + 20 * 0.5
+			expectedScore: 80,
+			criteria: []string{
+				models.ATSCriterionRequiredSkills,
+				models.ATSCriterionNiceToHaveSkills,
+				models.ATSCriterionExperience,
+			},
+		},
+		{
+			name: "Missing experience answer",
+			input: ATSScoreInput{
+				CandidateSkills:    []string{"Go"},
+				FormData:           map[string]interface{}{},
+				RequiredSkills:     []string{"Go"},
+				MinYearsExperience: 3,
+			},
+			// weights scaled to 75/25
+			expectedScore: 75,
+			criteria:      []string{models.ATSCriterionRequiredSkills, models.ATSCriterionExperience},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			breakdown := scorer.Score(tc.input)
+			assert.Equal(t, tc.expectedScore, breakdown.Score)
+
+			criteria := []string{}
+			for _, criterion := range breakdown.Criteria {
+				criteria = append(criteria, criterion.Criterion)
+			}
+			assert.Equal(t, tc.criteria, criteria)
+		})
+	}
+}
+
+func TestDefaultATSScorerBreakdown(t *testing.T) {
+	breakdown := NewDefaultATSScorer().Score(ATSScoreInput{
+		CandidateSkills: []string{"JS", "React"},
+		RequiredSkills:  []string{"JavaScript", "React", "GraphQL"},
+	})
+
+	assert.Len(t, breakdown.Criteria, 1)
+	criterion := breakdown.Criteria[0]
+	assert.Equal(t, 100, criterion.Weight)
+	assert.Equal(t, 67, criterion.Score)
+	assert.Equal(t, []string{"JavaScript", "React"}, criterion.Matched)
+	assert.Equal(t, []string{"GraphQL"}, criterion.Missing)
+	assert.Equal(t, "2 of 3 skills matched", criterion.Detail)
+}
+
+func TestYearsOfExperience(t *testing.T) {
+	testCases := []struct {
+		name     string
+		formData map[string]interface{}
+		expected float64
+		found    bool
+	}{
+		{"Number answer", map[string]interface{}{"Q_Experience": 5.0}, 5, true},
+		{"Text answer", map[string]interface{}{"q_experience": "5+ years"}, 5, true},
+		{"Other experience question", map[string]interface{}{"Q_TotalExperience": "3.5"}, 3.5, true},
+		{"Several experience questions", map[string]interface{}{"Q_RelevantExperience": "2", "Q_TotalExperience": "6", "Q_ExperienceLevel": "senior"}, 2, true},
+		{"No number", map[string]interface{}{"Q_Experience": "a lot"}, 0, false},
+		{"No answer", map[string]interface{}{"Q_Skills": []interface{}{"Go"}}, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			years, found := yearsOfExperience(tc.formData)
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.expected, years)
+		})
+	}
+}
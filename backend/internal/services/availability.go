@@ -99,12 +99,12 @@ func DeleteAvailability(ctx context.Context, availabilityID int) error {
 		return ErrNoAvailability
 	}
 
-	// Check if slot is used in interviews
+	// Check if slot is used in interviews, slots of cancelled interviews can be removed
 	var count int
 	err = db.GetContext(ctx, &count, `
-		SELECT COUNT(*) FROM interviews 
-		WHERE availability_id = $1`,
-		availabilityID)
+		SELECT (SELECT COUNT(*) FROM interviews WHERE availability_id = $1 AND status <> $2)
+			+ (SELECT COUNT(*) FROM interview_panelists WHERE availability_id = $1 AND cancelled_at IS NULL)`,
+		availabilityID, models.InterviewStatusCancelled)
 	if err != nil {
 		return err
 	}
@@ -225,9 +225,18 @@ func GetAllAvailability(ctx *gin.Context) ([]*models.GetAllAvailability, error)
 	yearsExperience := ctx.Query("years_experience")
 	jobTitle := ctx.Query("job_title")
 
+	// Match interviewers by any spelling of a skill, and by every skill of a category
 	var areasOfExpertise []string
 	if expertise := ctx.Query("areas_of_expertise"); expertise != "" {
-		areasOfExpertise = strings.Split(strings.ToLower(expertise), ",")
+		taxonomy, err := getUserSkillTaxonomy(ctx.Request.Context(), ctx.Value("userID"))
+		if err != nil {
+			return nil, err
+		}
+		for _, area := range strings.Split(expertise, ",") {
+			for _, key := range taxonomy.Expand(area) {
+				areasOfExpertise = appendUnique(areasOfExpertise, key)
+			}
+		}
 	}
 
 	query := `SELECT DISTINCT a.id, a.user_id, u.username, a.from_time, a.to_time, a.date, a.created_at, a.updated_at
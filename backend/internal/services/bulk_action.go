@@ -0,0 +1,214 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrBulkStageRequired = errors.New("stage is required to move submissions")
+	ErrBulkTagsRequired  = errors.New("at least one tag is required to tag submissions")
+	ErrAssigneeNotFound  = errors.New("assignee must be a user of your company")
+	ErrNoRejectedStage   = errors.New("the job's pipeline has no rejected stage")
+)
+
+// NormalizeTags trims tags, collapses whitespace, lowercases them and drops duplicates
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(cleanSkillName(tag))
+		if tag != "" {
+			normalized = appendUnique(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// validateBulkRequest checks the parameters needed by the action before anything is changed
+func validateBulkRequest(ctx context.Context, userID int, req *models.BulkSubmissionRequest) error {
+	switch req.Action {
+	case models.BulkActionMove:
+		if strings.TrimSpace(req.Stage) == "" {
+			return ErrBulkStageRequired
+		}
+	case models.BulkActionTag:
+		req.Tags = NormalizeTags(req.Tags)
+		if len(req.Tags) == 0 {
+			return ErrBulkTagsRequired
+		}
+	case models.BulkActionAssign:
+		if req.AssigneeID == nil {
+			return nil
+		}
+		var sameCompany bool
+		err := database.GetDB().GetContext(ctx, &sameCompany, `
+			SELECT EXISTS(
+				SELECT 1 FROM users
+				WHERE id = $1 AND company_name = (SELECT company_name FROM users WHERE id = $2))`,
+			*req.AssigneeID, userID)
+		if err != nil {
+			return err
+		}
+		if !sameCompany {
+			return ErrAssigneeNotFound
+		}
+	}
+	return nil
+}
+
+// BulkUpdateSubmissions applies an action to many submissions of the current user's jobs in a single transaction.
+// Submissions the action fails for are reported and left unchanged, the others are committed together.
+func BulkUpdateSubmissions(ctx context.Context, req models.BulkSubmissionRequest) (*models.BulkActionResponse, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	if err := validateBulkRequest(ctx, userID, &req); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	response := &models.BulkActionResponse{Action: req.Action, Results: []models.BulkActionResult{}}
+	pipelines := map[int][]models.PipelineStage{}
+	seen := map[int]bool{}
+
+	for _, submissionID := range req.SubmissionIDs {
+		if seen[submissionID] {
+			continue
+		}
+		seen[submissionID] = true
+
+		// A savepoint per submission keeps the transaction usable after a failed submission
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT bulk_submission"); err != nil {
+			return nil, err
+		}
+
+		result, err := applyBulkAction(ctx, tx, userID, submissionID, &req, pipelines)
+		if err != nil {
+			if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_submission"); rollbackErr != nil {
+				return nil, rollbackErr
+			}
+			response.Results = append(response.Results, models.BulkActionResult{SubmissionID: submissionID, Error: err.Error()})
+			response.Failed++
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT bulk_submission"); err != nil {
+			return nil, err
+		}
+
+		response.Results = append(response.Results, *result)
+		response.Succeeded++
+		if result.EmailQueued {
+			response.EmailsQueued++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if response.EmailsQueued > 0 {
+		deliverQueuedEmailsAsync()
+	}
+	return response, nil
+}
+
+// applyBulkAction applies the action to one submission and queues the candidate email
+func applyBulkAction(ctx context.Context, tx *sqlx.Tx, userID int, submissionID int, req *models.BulkSubmissionRequest, pipelines map[int][]models.PipelineStage) (*models.BulkActionResult, error) {
+	submission, err := lockOwnedSubmission(ctx, tx, userID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	stages, ok := pipelines[submission.JobDBID]
+	if !ok {
+		stages, _, err = getJobStages(ctx, tx, submission.JobDBID)
+		if err != nil {
+			return nil, err
+		}
+		pipelines[submission.JobDBID] = stages
+	}
+
+	switch req.Action {
+	case models.BulkActionMove:
+		_, err = changeSubmissionStage(ctx, tx, userID, submission, stages, req.Stage, req.Reason)
+	case models.BulkActionReject:
+		rejected := ""
+		for _, stage := range stages {
+			if stage.Type == models.StageTypeRejected {
+				rejected = stage.Key
+				break
+			}
+		}
+		if rejected == "" {
+			return nil, ErrNoRejectedStage
+		}
+		_, err = changeSubmissionStage(ctx, tx, userID, submission, stages, rejected, req.Reason)
+	case models.BulkActionTag:
+		err = addSubmissionTags(ctx, tx, userID, submission.ID, req.Tags)
+	case models.BulkActionAssign:
+		_, err = tx.ExecContext(ctx, "UPDATE job_submissions SET assigned_to = $1, updated_at = NOW() WHERE id = $2", req.AssigneeID, submission.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BulkActionResult{SubmissionID: submission.ID, Success: true, Status: submission.Status}
+	if req.Email != nil {
+		if err := queueBulkEmail(ctx, tx, submission, stages, req.Email); err != nil {
+			return nil, err
+		}
+		result.EmailQueued = true
+	}
+	return result, nil
+}
+
+// addSubmissionTags adds tags to a submission, tags it already has are kept
+func addSubmissionTags(ctx context.Context, tx *sqlx.Tx, userID int, submissionID int, tags []string) error {
+	for _, tag := range tags {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO submission_tags (job_submission_id, tag, created_by)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (job_submission_id, tag) DO NOTHING`, submissionID, tag, userID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queueBulkEmail renders the email template for a candidate and adds it to the email queue
+func queueBulkEmail(ctx context.Context, tx *sqlx.Tx, submission *ownedSubmission, stages []models.PipelineStage, template *models.EmailTemplate) error {
+	link, err := candidateStatusLink(submission.ID, submission.Email)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]string{
+		"name":        submission.Username,
+		"job_title":   submission.JobTitle,
+		"company":     submission.CompanyName,
+		"stage":       candidateStageLabel(submission.Status, findStage(stages, submission.Status)),
+		"status_link": link,
+	}
+
+	submissionID := submission.ID
+	_, err = queueEmail(ctx, tx, &submissionID, EmailMessage{
+		To:      submission.Email,
+		Subject: renderEmailTemplate(template.Subject, values),
+		Body:    renderEmailTemplate(template.Body, values),
+	})
+	if err != nil {
+		log.Printf("Failed to queue email for submission %d: %v", submission.ID, err)
+	}
+	return err
+}
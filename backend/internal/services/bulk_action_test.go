@@ -0,0 +1,21 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"strong backend", "relocation"}, NormalizeTags([]string{" Strong  Backend", "relocation", "strong backend", ""}))
+	assert.Empty(t, NormalizeTags([]string{" "}))
+}
+
+func TestRenderEmailTemplate(t *testing.T) {
+	body := renderEmailTemplate("Hi {{name}}, your application for {{job_title}} is now: {{stage}}. {{unknown}}", map[string]string{
+		"name":      "Jane",
+		"job_title": "Backend Engineer",
+		"stage":     "Shortlisted",
+	})
+	assert.Equal(t, "Hi Jane, your application for Backend Engineer is now: Shortlisted. {{unknown}}", body)
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrCandidateNotFound = errors.New("candidate not found")
+
+const (
+	// Phone numbers are compared on their last digits, so a missing country code still matches
+	phoneMatchDigits = 10
+	minPhoneDigits   = 7
+	// Names with a similarity of at least this ratio are considered the same person
+	nameSimilarityThreshold = 0.85
+)
+
+// normalizePhone returns the last digits of a phone number, or "" when it is too short to identify someone
+func normalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	normalized := digits.String()
+	if len(normalized) < minPhoneDigits {
+		return ""
+	}
+	if len(normalized) > phoneMatchDigits {
+		normalized = normalized[len(normalized)-phoneMatchDigits:]
+	}
+	return normalized
+}
+
+// nameTokens returns the lowercase words of a name, without punctuation
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// similarNames reports whether two names likely belong to the same person. The same words in
+// another order ("Doe, Jane") match, as do small typos.
+func similarNames(a, b string) bool {
+	tokensA, tokensB := nameTokens(a), nameTokens(b)
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return false
+	}
+	sort.Strings(tokensA)
+	sort.Strings(tokensB)
+	sortedA, sortedB := strings.Join(tokensA, " "), strings.Join(tokensB, " ")
+	if sortedA == sortedB {
+		return true
+	}
+
+	longest := len([]rune(sortedA))
+	if n := len([]rune(sortedB)); n > longest {
+		longest = n
+	}
+	similarity := 1 - float64(levenshtein(sortedA, sortedB))/float64(longest)
+	return similarity >= nameSimilarityThreshold
+}
+
+// levenshtein returns the number of single character edits between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// applicationPhone returns the phone number of an application, taken from a phone question
+// of the form or else from the resume
+func applicationPhone(formData map[string]interface{}, resume *models.ParsedResume) string {
+	keys := make([]string, 0, len(formData))
+	for key := range formData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !strings.Contains(strings.ToLower(key), "phone") {
+			continue
+		}
+		if value, ok := formData[key].(string); ok && normalizePhone(value) != "" {
+			return normalizePhone(value)
+		}
+	}
+	if resume != nil {
+		return normalizePhone(resume.Phone.Value)
+	}
+	return ""
+}
+
+// getFormCompanyName returns the company of the HR user owning the job of an application form
+func getFormCompanyName(ctx context.Context, formUUID string) (string, error) {
+	db := database.GetDB()
+
+	var companyName string
+	err := db.GetContext(ctx, &companyName, `
+		SELECT u.company_name
+		FROM application_form af
+		JOIN jobs j ON af.job_id = j.id
+		JOIN users u ON j.user_id = u.id
+		WHERE af.form_uuid = $1`, formUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrFormNotFound
+		}
+		return "", err
+	}
+	return companyName, nil
+}
+
+type candidateMatch struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+// linkCandidate returns the company's candidate an application belongs to, creating it for a new email.
+// With fuzzy matching enabled an unknown email is linked to an existing candidate with the same
+// phone number and a similar name. It returns how the candidate was matched.
+func linkCandidate(ctx context.Context, q sqlx.QueryerContext, companyName string, email string, name string, phone string) (int, string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	var candidateID int
+	err := sqlx.GetContext(ctx, q, &candidateID, `
+		SELECT id FROM candidates WHERE company_name = $1 AND email = $2`, companyName, email)
+	if err == nil {
+		return candidateID, models.CandidateMatchEmail, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, "", err
+	}
+
+	if config.GetConfig().CandidateFuzzyMatching && phone != "" {
+		matches := []candidateMatch{}
+		err := sqlx.SelectContext(ctx, q, &matches, `
+			SELECT id, name FROM candidates WHERE company_name = $1 AND phone = $2 ORDER BY id`, companyName, phone)
+		if err != nil {
+			return 0, "", err
+		}
+		for _, match := range matches {
+			if similarNames(match.Name, name) {
+				return match.ID, models.CandidateMatchFuzzy, nil
+			}
+		}
+	}
+
+	// Another application with the same email may have created the candidate in the meantime
+	err = sqlx.GetContext(ctx, q, &candidateID, `
+		INSERT INTO candidates (company_name, email, name, phone)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (company_name, email) DO UPDATE SET updated_at = NOW()
+		RETURNING id`, companyName, email, name, phone)
+	if err != nil {
+		return 0, "", err
+	}
+	return candidateID, models.CandidateMatchEmail, nil
+}
+
+// candidateListColumns are the columns of a candidate with the number of applications, for a query aliased c
+const candidateListColumns = `
+	c.id, c.company_name, c.email, c.name, c.phone, c.created_at, c.updated_at,
+	COUNT(js.id) AS application_count,
+	COALESCE(MAX(js.created_at), c.created_at) AS last_applied_at`
+
+// ListCandidates returns the candidates of the user's company, most recent applicants first
+func ListCandidates(ctx context.Context, req models.CandidateListRequest) (*models.CandidateListResult, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID")
+
+	companyName, err := getUserCompanyName(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > maxSearchPageSize {
+		req.PageSize = defaultSearchPageSize
+	}
+
+	where := `WHERE c.company_name = $1`
+	args := []interface{}{companyName}
+	if q := strings.TrimSpace(req.Q); q != "" {
+		where += ` AND (c.name ILIKE $2 OR c.email ILIKE $2)`
+		args = append(args, "%"+q+"%")
+	}
+
+	var total int
+	if err := db.GetContext(ctx, &total, `SELECT COUNT(*) FROM candidates c `+where, args...); err != nil {
+		return nil, err
+	}
+
+	candidates := []models.Candidate{}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM candidates c
+		LEFT JOIN job_submissions js ON js.candidate_id = c.id
+		%s
+		GROUP BY c.id
+		ORDER BY last_applied_at DESC, c.id DESC
+		LIMIT $%d OFFSET $%d`, candidateListColumns, where, len(args)+1, len(args)+2)
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+	err = db.SelectContext(ctx, &candidates, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CandidateListResult{
+		Candidates: candidates,
+		Pagination: models.Pagination{
+			Page:       req.Page,
+			PageSize:   req.PageSize,
+			Total:      total,
+			TotalPages: (total + req.PageSize - 1) / req.PageSize,
+		},
+	}, nil
+}
+
+// GetCandidateProfile returns a candidate of the user's company with all their applications,
+// interviews and interview feedback
+func GetCandidateProfile(ctx context.Context, candidateID int) (*models.CandidateProfile, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID")
+
+	var profile models.CandidateProfile
+	err := db.GetContext(ctx, &profile.Candidate, `
+		SELECT `+candidateListColumns+`
+		FROM candidates c
+		LEFT JOIN job_submissions js ON js.candidate_id = c.id
+		WHERE c.id = $1 AND c.company_name = (SELECT company_name FROM users WHERE id = $2)
+		GROUP BY c.id`, candidateID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrCandidateNotFound
+		}
+		return nil, err
+	}
+
+	profile.Applications = []models.CandidateApplication{}
+	err = db.SelectContext(ctx, &profile.Applications, `
+		SELECT js.id, js.job_id, j.job_title, js.email, js.status, js.ats_score, js.revision, js.candidate_match,
+			ARRAY(SELECT st.tag FROM submission_tags st WHERE st.job_submission_id = js.id ORDER BY st.tag) AS tags,
+			js.created_at, js.updated_at
+		FROM job_submissions js
+		JOIN application_form af ON js.form_uuid = af.form_uuid
+		JOIN jobs j ON af.job_id = j.id
+		WHERE js.candidate_id = $1
+		ORDER BY js.created_at DESC, js.id DESC`, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Panel interviews are listed once per interviewer, each with their own feedback
+	profile.Interviews = []models.CandidateInterview{}
+	err = db.SelectContext(ctx, &profile.Interviews, `
+		SELECT i.id, i.job_submission_id, i.job_id, f.interviewer_user_id, u.username AS interviewer_name,
+			COALESCE(TO_CHAR(a.date, 'YYYY-MM-DD'), '') AS date, COALESCE(TO_CHAR(a.from_time, 'HH24:MI'), '') AS from_time,
+			COALESCE(TO_CHAR(a.to_time, 'HH24:MI'), '') AS to_time,
+			i.status, f.verdict, COALESCE(f.feedback, '') AS feedback, i.created_at
+		FROM interviews i
+		JOIN (
+			SELECT id AS interview_id, interviewer_user_id, verdict, feedback FROM interviews
+			UNION ALL
+			SELECT interview_id, interviewer_user_id, verdict, feedback FROM interview_panelists
+		) f ON f.interview_id = i.id
+		JOIN job_submissions js ON i.job_submission_id = js.id
+		LEFT JOIN availabilities a ON i.availability_id = a.id
+		JOIN users u ON f.interviewer_user_id = u.id
+		WHERE js.candidate_id = $1
+		ORDER BY a.date DESC NULLS LAST, a.from_time DESC, i.id, f.interviewer_user_id`, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
@@ -0,0 +1,397 @@
+package services
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/internal/ratelimit"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	ErrInvalidCandidateToken = errors.New("this link is invalid or has expired, please request a new one")
+	ErrApplicationClosed     = errors.New("this application can no longer be changed")
+	ErrResumeType            = errors.New("resume must be a PDF, DOCX or TXT file")
+)
+
+// candidateTokenPurpose keeps candidate magic links from being usable as HR tokens and vice versa
+const candidateTokenPurpose = "candidate_application"
+
+// candidateStages maps the submission status to what the candidate sees
+var candidateStages = map[string]string{
+	"applied":                        "Application received",
+	"under_review":                   "Under review",
+	"shortlisted":                    "Shortlisted",
+	"rejected":                       "Not selected",
+	"selected":                       "Selected",
+	models.SubmissionStatusWithdrawn: "Withdrawn",
+}
+
+// closedStatuses cannot be changed by the candidate anymore
+var closedStatuses = map[string]bool{
+	"rejected":                       true,
+	models.SubmissionStatusWithdrawn: true,
+}
+
+// GenerateCandidateToken signs an expiring token that gives access to a single application
+func GenerateCandidateToken(submissionID int, email string) (string, error) {
+	cfg := config.GetConfig()
+
+	claims := jwt.MapClaims{
+		"submission_id": submissionID,
+		"email":         strings.ToLower(email),
+		"purpose":       candidateTokenPurpose,
+		"exp":           time.Now().Add(cfg.CandidateLinkTTL).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(cfg.JWTSecret))
+}
+
+// parseCandidateToken validates a magic link token and returns the submission id and email it was issued for
+func parseCandidateToken(tokenString string) (int, string, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidCandidateToken
+		}
+		return []byte(config.GetConfig().JWTSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, "", ErrInvalidCandidateToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != candidateTokenPurpose {
+		return 0, "", ErrInvalidCandidateToken
+	}
+
+	submissionID, ok := claims["submission_id"].(float64)
+	if !ok {
+		return 0, "", ErrInvalidCandidateToken
+	}
+	email, ok := claims["email"].(string)
+	if !ok {
+		return 0, "", ErrInvalidCandidateToken
+	}
+
+	return int(submissionID), email, nil
+}
+
+// getCandidateSubmission loads the submission a magic link token was issued for
+func getCandidateSubmission(ctx context.Context, token string) (*models.JobSubmission, error) {
+	db := database.GetDB()
+
+	submissionID, email, err := parseCandidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var submission models.JobSubmission
+	err = db.GetContext(ctx, &submission, `
+		SELECT id, job_id, username, email, form_data, form_uuid, skills, resume_key, resume_file_name, resume_text, resume_data, ats_score, ats_breakdown, status, revision, created_at, updated_at
+		FROM job_submissions
+		WHERE id = $1 AND LOWER(email) = $2`, submissionID, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidCandidateToken
+		}
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// isApplicationClosed reports whether the candidate can no longer change the application,
+// because it was withdrawn or moved to a rejected stage of the job's pipeline
+func isApplicationClosed(ctx context.Context, submission *models.JobSubmission) (bool, *models.PipelineStage, error) {
+	stage, err := getSubmissionStage(ctx, submission)
+	if err != nil {
+		return false, nil, err
+	}
+	closed := closedStatuses[submission.Status] || (stage != nil && stage.Type == models.StageTypeRejected)
+	return closed, stage, nil
+}
+
+// candidateStageLabel is the stage shown to the candidate. Custom pipeline stages are shown
+// by name, hired and rejected ones like the default stages.
+func candidateStageLabel(status string, stage *models.PipelineStage) string {
+	if label, ok := candidateStages[status]; ok {
+		return label
+	}
+	if stage != nil {
+		switch stage.Type {
+		case models.StageTypeHired:
+			return candidateStages["selected"]
+		case models.StageTypeRejected:
+			return candidateStages["rejected"]
+		default:
+			return stage.Name
+		}
+	}
+	return "In progress"
+}
+
+// buildCandidateStatus converts a submission into the candidate facing view
+func buildCandidateStatus(ctx context.Context, submission *models.JobSubmission) (*models.CandidateApplicationStatus, error) {
+	db := database.GetDB()
+
+	closed, stage, err := isApplicationClosed(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.CandidateApplicationStatus{
+		SubmissionID:    submission.ID,
+		JobID:           submission.JobID,
+		Name:            submission.Username,
+		Email:           submission.Email,
+		Status:          submission.Status,
+		Stage:           candidateStageLabel(submission.Status, stage),
+		Revision:        submission.Revision,
+		AppliedAt:       submission.CreatedAt,
+		UpdatedAt:       submission.UpdatedAt,
+		CanWithdraw:     !closed,
+		CanUpdateResume: !closed,
+	}
+
+	err = db.QueryRowContext(ctx, `
+		SELECT j.job_title, u.company_name
+		FROM application_form af
+		JOIN jobs j ON af.job_id = j.id
+		JOIN users u ON j.user_id = u.id
+		WHERE af.form_uuid = $1`, submission.FormUUID).Scan(&status.JobTitle, &status.CompanyName)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// candidateStatusLink returns the magic link to the status page of an application
+func candidateStatusLink(submissionID int, email string) (string, error) {
+	token, err := GenerateCandidateToken(submissionID, email)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/application-status?token=%s", strings.TrimRight(config.GetConfig().AppBaseURL, "/"), url.QueryEscape(token)), nil
+}
+
+// SendApplicationStatusLink emails the candidate a magic link to the status page of their application
+func SendApplicationStatusLink(ctx context.Context, submission *models.JobSubmission) error {
+	link, err := candidateStatusLink(submission.ID, submission.Email)
+	if err != nil {
+		return err
+	}
+
+	status, err := buildCandidateStatus(ctx, submission)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.GetConfig()
+
+	jobTitle := status.JobTitle
+	if jobTitle == "" {
+		jobTitle = submission.JobID
+	}
+
+	body := fmt.Sprintf(`Hi %s,
+
+Thank you for applying for %s.
+
+You can follow the status of your application, upload an updated resume or withdraw your application here:
+%s
+
+This link is personal and expires on %s.`,
+		submission.Username,
+		jobTitle,
+		link,
+		time.Now().Add(cfg.CandidateLinkTTL).Format("January 2, 2006"))
+
+	return GetMailer().Send(EmailMessage{
+		To:      submission.Email,
+		Subject: fmt.Sprintf("Your application for %s", jobTitle),
+		Body:    body,
+	})
+}
+
+// sendApplicationStatusLinkAsync sends the magic link without delaying the response to the candidate
+func sendApplicationStatusLinkAsync(submission *models.JobSubmission) {
+	go func() {
+		if err := SendApplicationStatusLink(context.Background(), submission); err != nil {
+			log.Printf("Failed to send application status link to %s: %v", submission.Email, err)
+		}
+	}()
+}
+
+// statusLinkLimiter limits the magic links emailed to the same address on request
+var statusLinkLimiter = ratelimit.New(3, time.Hour)
+
+// RequestApplicationStatusLink emails a new magic link if the candidate applied for the job.
+// It never reports whether an application exists, so it cannot be used to probe emails: the email
+// is sent in the background and requests beyond the limit per address are dropped silently.
+func RequestApplicationStatusLink(ctx context.Context, jobID string, email string) error {
+	if !statusLinkLimiter.Allow(strings.ToLower(email)) {
+		return nil
+	}
+	submission, err := findExistingSubmission(ctx, jobID, email)
+	if err != nil {
+		return err
+	}
+	if submission == nil {
+		return nil
+	}
+	sendApplicationStatusLinkAsync(submission)
+	return nil
+}
+
+// GetCandidateApplication returns the status page data for a magic link token
+func GetCandidateApplication(ctx context.Context, token string) (*models.CandidateApplicationStatus, error) {
+	submission, err := getCandidateSubmission(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return buildCandidateStatus(ctx, submission)
+}
+
+// WithdrawCandidateApplication lets the candidate withdraw their application
+func WithdrawCandidateApplication(ctx context.Context, token string) (*models.CandidateApplicationStatus, error) {
+	db := database.GetDB()
+
+	submission, err := getCandidateSubmission(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	closed, _, err := isApplicationClosed(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		return nil, ErrApplicationClosed
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	previousStatus := submission.Status
+	err = tx.QueryRowContext(ctx, `
+		UPDATE job_submissions SET status = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING status, updated_at`,
+		models.SubmissionStatusWithdrawn, submission.ID).Scan(&submission.Status, &submission.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	err = recordStageChange(ctx, tx, &models.StageChange{
+		JobSubmissionID: submission.ID,
+		FromStage:       previousStatus,
+		ToStage:         submission.Status,
+		Reason:          "Withdrawn by the candidate",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return buildCandidateStatus(ctx, submission)
+}
+
+// ReplaceCandidateResume stores an updated resume for the application, the previous version is kept in the history
+func ReplaceCandidateResume(ctx context.Context, token string, file *multipart.FileHeader) (_ *models.CandidateApplicationStatus, err error) {
+	submission, err := getCandidateSubmission(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	closed, _, err := isApplicationClosed(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+	if closed {
+		return nil, ErrApplicationClosed
+	}
+
+	if err := validateResume(file); err != nil {
+		return nil, err
+	}
+
+	companyName, err := getFormCompanyName(ctx, submission.FormUUID)
+	if err != nil {
+		return nil, err
+	}
+	if err := scanUploads(ctx, companyName, submission.JobID, file); err != nil {
+		return nil, err
+	}
+	resumeKey, err := UploadResume(ctx, file, companyName, submission.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload resume: %v", err)
+	}
+	// Remove the new resume again when the application is not updated
+	stored := &storedFiles{keys: []string{resumeKey}}
+	defer func() {
+		if err != nil {
+			stored.remove(ctx)
+		}
+	}()
+
+	// Attachments are not changed by a resume update
+	attachments, err := GetAttachmentsForSubmissions(ctx, []int{submission.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	taxonomy, err := getFormSkillTaxonomy(ctx, submission.FormUUID)
+	if err != nil {
+		return nil, err
+	}
+	resumeText, resumeData := readResume(file)
+	if resumeData != nil {
+		resumeData.Skills = taxonomy.NormalizeAll(resumeData.Skills)
+	}
+
+	// The resume may mention skills the previous one did not, score the application again
+	var formData map[string]interface{}
+	if err := json.Unmarshal(submission.FormData, &formData); err != nil {
+		return nil, err
+	}
+	atsBreakdown, err := scoreApplication(ctx, submission.FormUUID, ATSScoreInput{
+		CandidateSkills: submission.Skills,
+		FormData:        formData,
+		ResumeText:      resumeText,
+		ParsedResume:    resumeData,
+		Taxonomy:        taxonomy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *submission
+	updated.ResumeKey = resumeKey
+	updated.ResumeFileName = file.Filename
+	updated.ResumeText = resumeText
+	updated.ResumeData = resumeData
+	updated.ATSScore = atsBreakdown.Score
+	updated.ATSBreakdown = atsBreakdown
+	updated.UpdatedAt = time.Now()
+	updated.Attachments = attachments[submission.ID]
+
+	if err := updateJobSubmission(ctx, submission, &updated); err != nil {
+		return nil, err
+	}
+
+	return buildCandidateStatus(ctx, &updated)
+}
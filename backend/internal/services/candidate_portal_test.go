@@ -0,0 +1,53 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/config"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCandidateToken(t *testing.T) {
+	assert.NoError(t, config.LoadConfig())
+	secret := []byte(config.GetConfig().JWTSecret)
+
+	t.Run("Round trip", func(t *testing.T) {
+		token, err := GenerateCandidateToken(42, "Jane@Example.com")
+		assert.NoError(t, err)
+
+		submissionID, email, err := parseCandidateToken(token)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, submissionID)
+		assert.Equal(t, "jane@example.com", email)
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"submission_id": 42,
+			"email":         "jane@example.com",
+			"purpose":       candidateTokenPurpose,
+			"exp":           time.Now().Add(-time.Minute).Unix(),
+		}).SignedString(secret)
+
+		_, _, err := parseCandidateToken(token)
+		assert.Equal(t, ErrInvalidCandidateToken, err)
+	})
+
+	t.Run("HR login token", func(t *testing.T) {
+		token, err := generateToken(7)
+		assert.NoError(t, err)
+
+		_, _, err = parseCandidateToken(token)
+		assert.Equal(t, ErrInvalidCandidateToken, err)
+	})
+
+	t.Run("Tampered token", func(t *testing.T) {
+		token, _ := GenerateCandidateToken(42, "jane@example.com")
+
+		_, _, err := parseCandidateToken(token + "x")
+		assert.Equal(t, ErrInvalidCandidateToken, err)
+	})
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalidScoreRange = errors.New("min_score cannot be greater than max_score")
+	ErrInvalidDateRange  = errors.New("from_date cannot be after to_date")
+)
+
+const (
+	defaultSearchPageSize = 20
+	maxSearchPageSize     = 100
+)
+
+// submissionSearchDocument builds the full-text document of a submission aliased js.
+// Name, email and skills weigh most, then form answers, then the resume text.
+// Names, emails and skills use the simple configuration so they are not stemmed.
+const submissionSearchDocument = `(
+	setweight(to_tsvector('simple', js.username || ' ' || js.email || ' ' || translate(js.email, '@._-', '    ')), 'A') ||
+	setweight(to_tsvector('simple', COALESCE(array_to_string(js.skills, ' '), '')), 'A') ||
+	setweight(jsonb_to_tsvector('english', js.form_data, '["string"]'), 'B') ||
+	setweight(to_tsvector('english', js.resume_text), 'C'))`
+
+// searchQuery matches both stemmed and unstemmed terms of the search input
+const searchQuery = `(websearch_to_tsquery('simple', %[1]s) || websearch_to_tsquery('english', %[1]s))`
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// refreshSubmissionSearchVector recomputes the stored search document after a submission is saved
+func refreshSubmissionSearchVector(ctx context.Context, db execer, submissionID int) error {
+	_, err := db.ExecContext(ctx, `UPDATE job_submissions js SET search_vector = `+submissionSearchDocument+` WHERE js.id = $1`, submissionID)
+	return err
+}
+
+// SearchCandidates searches the submissions to all jobs of the current user, ranked by full-text relevance
+func SearchCandidates(ctx context.Context, req models.CandidateSearchRequest) (*models.CandidateSearchResult, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID")
+
+	if req.MinScore != nil && req.MaxScore != nil && *req.MinScore > *req.MaxScore {
+		return nil, ErrInvalidScoreRange
+	}
+	// YYYY-MM-DD dates compare correctly as strings
+	if req.FromDate != "" && req.ToDate != "" && req.FromDate > req.ToDate {
+		return nil, ErrInvalidDateRange
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > maxSearchPageSize {
+		req.PageSize = defaultSearchPageSize
+	}
+
+	where := `
+		FROM job_submissions js
+		JOIN application_form af ON js.form_uuid = af.form_uuid
+		JOIN jobs j ON af.job_id = j.id
+		WHERE j.user_id = $1`
+	args := []interface{}{userID}
+	argCount := 2
+
+	q := strings.TrimSpace(req.Q)
+	tsQuery := ""
+	if q != "" {
+		// Matched against the stored document so the GIN index is used, earlier rows are backfilled by job_submission_columns.sql
+		tsQuery = fmt.Sprintf(searchQuery, fmt.Sprintf("$%d", argCount))
+		where += fmt.Sprintf(` AND js.search_vector @@ %s`, tsQuery)
+		args = append(args, q)
+		argCount++
+	}
+
+	if req.JobID != "" {
+		where += fmt.Sprintf(` AND j.job_id = $%d`, argCount)
+		args = append(args, req.JobID)
+		argCount++
+	}
+
+	if req.Status != "" {
+		where += fmt.Sprintf(` AND js.status = $%d`, argCount)
+		args = append(args, req.Status)
+		argCount++
+	}
+
+	if req.Skill != "" {
+		// Skills are stored under their canonical names, a category also finds its skills
+		taxonomy, err := getUserSkillTaxonomy(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		where += fmt.Sprintf(` AND EXISTS (SELECT 1 FROM unnest(js.skills) s WHERE LOWER(s) = ANY($%d))`, argCount)
+		args = append(args, pq.Array(taxonomy.Expand(req.Skill)))
+		argCount++
+	}
+
+	if req.MinScore != nil {
+		where += fmt.Sprintf(` AND js.ats_score >= $%d`, argCount)
+		args = append(args, *req.MinScore)
+		argCount++
+	}
+
+	if req.MaxScore != nil {
+		where += fmt.Sprintf(` AND js.ats_score <= $%d`, argCount)
+		args = append(args, *req.MaxScore)
+		argCount++
+	}
+
+	if req.FromDate != "" {
+		where += fmt.Sprintf(` AND js.created_at >= $%d::date`, argCount)
+		args = append(args, req.FromDate)
+		argCount++
+	}
+
+	if req.ToDate != "" {
+		where += fmt.Sprintf(` AND js.created_at < $%d::date + 1`, argCount)
+		args = append(args, req.ToDate)
+		argCount++
+	}
+
+	var total int
+	if err := db.GetContext(ctx, &total, `SELECT COUNT(*) `+where, args...); err != nil {
+		return nil, err
+	}
+
+	rank, highlight, orderBy := "0", "''", "js.created_at DESC, js.id DESC"
+	if tsQuery != "" {
+		rank = fmt.Sprintf(`ts_rank_cd(js.search_vector, %s)`, tsQuery)
+		highlight = fmt.Sprintf(`ts_headline('english', js.resume_text, %s, 'MaxFragments=2, MaxWords=20, MinWords=5')`, tsQuery)
+		orderBy = "rank DESC, js.ats_score DESC, js.created_at DESC, js.id DESC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT js.id, j.job_id, j.job_title, js.username, js.email, js.skills, js.resume_key, js.ats_score, js.status, js.created_at, js.candidate_id,
+			%s AS rank, %s AS highlight
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, rank, highlight, where, orderBy, argCount, argCount+1)
+	args = append(args, req.PageSize, (req.Page-1)*req.PageSize)
+
+	results := []models.CandidateSearchHit{}
+	if err := db.SelectContext(ctx, &results, query, args...); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		url, err := SignedResumeURL(ctx, results[i].ResumeKey)
+		if err != nil {
+			return nil, err
+		}
+		results[i].ResumeURL = url
+	}
+
+	return &models.CandidateSearchResult{
+		Results: results,
+		Pagination: models.Pagination{
+			Page:       req.Page,
+			PageSize:   req.PageSize,
+			Total:      total,
+			TotalPages: (total + req.PageSize - 1) / req.PageSize,
+		},
+	}, nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"backend/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	assert.Equal(t, "5551234567", normalizePhone("+1 (555) 123-4567"))
+	assert.Equal(t, "5551234567", normalizePhone("555.123.4567"))
+	// Too short to identify someone
+	assert.Equal(t, "", normalizePhone("12-34"))
+	assert.Equal(t, "", normalizePhone(""))
+}
+
+func TestSimilarNames(t *testing.T) {
+	assert.True(t, similarNames("Jane Doe", "jane doe"))
+	assert.True(t, similarNames("Doe, Jane", "Jane Doe"))
+	assert.True(t, similarNames("Jonathan Smith", "Jonathon Smith"))
+	assert.False(t, similarNames("Jane Doe", "John Smith"))
+	assert.False(t, similarNames("Jane Doe", "Jane"))
+	assert.False(t, similarNames("", "Jane Doe"))
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("jane", "jane"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 4, levenshtein("", "jane"))
+}
+
+func TestApplicationPhone(t *testing.T) {
+	resume := &models.ParsedResume{Phone: models.ParsedField{Value: "555 987 6543"}}
+
+	// A phone question of the form comes first
+	formData := map[string]interface{}{"Q_Name": "Jane", "Q_Phone_Number": "+1 555 123 4567"}
+	assert.Equal(t, "5551234567", applicationPhone(formData, resume))
+
+	// Otherwise the phone number of the resume is used
+	assert.Equal(t, "5559876543", applicationPhone(map[string]interface{}{"Q_Phone": "n/a"}, resume))
+	assert.Equal(t, "", applicationPhone(map[string]interface{}{}, nil))
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"backend/internal/database"
+	"context"
+)
+
+// getUserCompanyName returns the company the given user belongs to.
+// Company-wide resources (shared templates, ...) are scoped by users.company_name.
+func getUserCompanyName(ctx context.Context, userID interface{}) (string, error) {
+	db := database.GetDB()
+
+	var companyName string
+	err := db.GetContext(ctx, &companyName, "SELECT company_name FROM users WHERE id = $1", userID)
+	return companyName, err
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// Statuses of queued emails
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSending = "sending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+// maxEmailAttempts is how often sending a queued email is tried before it is marked failed
+const maxEmailAttempts = 5
+
+// emailClaimTimeout is after how long an email claimed by a worker that never recorded
+// the result, e.g. because it was stopped while sending, is sent again
+const emailClaimTimeout = 10 * time.Minute
+
+// queueEmail adds an email to the outgoing queue, within the caller's transaction so it is
+// only sent if the change it reports is committed
+func queueEmail(ctx context.Context, q rowQueryer, submissionID *int, msg EmailMessage) (int, error) {
+	var id int
+	err := q.QueryRowContext(ctx, `
+		INSERT INTO email_queue (job_submission_id, to_email, subject, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`, submissionID, msg.To, msg.Subject, msg.Body).Scan(&id)
+	return id, err
+}
+
+// renderEmailTemplate fills in the placeholders of a candidate email template
+func renderEmailTemplate(template string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for key, value := range values {
+		pairs = append(pairs, "{{"+key+"}}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// DeliverQueuedEmails sends up to limit pending emails and returns how many were sent.
+// The emails are claimed before sending so concurrent workers skip them, no transaction
+// is kept open while talking to the mail server.
+func DeliverQueuedEmails(ctx context.Context, limit int) (int, error) {
+	db := database.GetDB()
+
+	emails := []models.QueuedEmail{}
+	err := db.SelectContext(ctx, &emails, `
+		UPDATE email_queue SET status = $1, claimed_at = NOW()
+		WHERE id IN (
+			SELECT id FROM email_queue
+			WHERE status = $2 OR (status = $1 AND claimed_at < NOW() - $3::int * INTERVAL '1 second')
+			ORDER BY created_at, id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, job_submission_id, to_email, subject, body, status, attempts, last_error, created_at, sent_at`,
+		EmailStatusSending, EmailStatusPending, int(emailClaimTimeout.Seconds()), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, email := range emails {
+		sendErr := GetMailer().Send(EmailMessage{To: email.ToEmail, Subject: email.Subject, Body: email.Body})
+		if sendErr == nil {
+			_, err = db.ExecContext(ctx, `
+				UPDATE email_queue SET status = $1, attempts = attempts + 1, last_error = '', sent_at = NOW()
+				WHERE id = $2`, EmailStatusSent, email.ID)
+			sent++
+		} else {
+			log.Printf("Failed to send queued email %d to %s: %v", email.ID, email.ToEmail, sendErr)
+			status := EmailStatusPending
+			if email.Attempts+1 >= maxEmailAttempts {
+				status = EmailStatusFailed
+			}
+			_, err = db.ExecContext(ctx, `
+				UPDATE email_queue SET status = $1, attempts = attempts + 1, last_error = $2
+				WHERE id = $3`, status, sendErr.Error(), email.ID)
+		}
+		if err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+// deliverQueuedEmailsAsync sends newly queued emails without delaying the response
+func deliverQueuedEmailsAsync() {
+	go func() {
+		if _, err := DeliverQueuedEmails(context.Background(), 500); err != nil {
+			log.Printf("Failed to deliver queued emails: %v", err)
+		}
+	}()
+}
+
+// RunEmailQueueWorker retries pending emails every interval until the context is done
+func RunEmailQueueWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := DeliverQueuedEmails(ctx, 100); err != nil {
+				log.Printf("Failed to deliver queued emails: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"backend/internal/config"
+	"backend/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidFileSignature = errors.New("this download link is invalid or has expired")
+
+var (
+	fileStorage   storage.Storage
+	fileStorageMu sync.Mutex
+)
+
+// GetStorage returns the file storage selected by the configuration
+func GetStorage() (storage.Storage, error) {
+	fileStorageMu.Lock()
+	defer fileStorageMu.Unlock()
+
+	if fileStorage != nil {
+		return fileStorage, nil
+	}
+	store, err := storage.New(config.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	fileStorage = store
+	return fileStorage, nil
+}
+
+// SetStorage overrides the file storage, e.g. with the in-memory backend in tests
+func SetStorage(s storage.Storage) {
+	fileStorageMu.Lock()
+	defer fileStorageMu.Unlock()
+	fileStorage = s
+}
+
+// UploadResume stores the resume under a new unique key and returns the key, resumes are only downloaded through signed URLs.
+// The name the file was uploaded with is kept as metadata.
+func UploadResume(ctx context.Context, file *multipart.FileHeader, companyName string, jobID string) (string, error) {
+	return uploadFile(ctx, file, newResumeKey(companyName, jobID, file.Filename))
+}
+
+// resumeKeyPrefix is the folder of the resumes submitted to a job of a company
+func resumeKeyPrefix(companyName string, jobID string) string {
+	return fmt.Sprintf("resumes/%s/%s/", sanitizeKeySegment(companyName), sanitizeKeySegment(jobID))
+}
+
+// newResumeKey returns a key no other resume uses, e.g. resumes/acme/job-1/<uuid>.pdf.
+// Every upload gets its own key so applicants with the same name never overwrite each other.
+func newResumeKey(companyName string, jobID string, fileName string) string {
+	return resumeKeyPrefix(companyName, jobID) + uuid.NewString() + keyExtension(fileName)
+}
+
+// keyExtension returns the lowercased extension of a file name, or "" when it is not safe in a key
+func keyExtension(fileName string) string {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if sanitizeFilename(strings.TrimPrefix(ext, ".")) != strings.TrimPrefix(ext, ".") {
+		return ""
+	}
+	return ext
+}
+
+// sanitizeKeySegment turns a name into a single non-empty key segment
+func sanitizeKeySegment(name string) string {
+	segment := strings.Trim(sanitizeFilename(strings.ToLower(strings.TrimSpace(name))), "_")
+	if segment == "" {
+		return "_"
+	}
+	return segment
+}
+
+// UploadAttachment stores an additional application file (cover letter, portfolio, ...) and returns its key,
+// attachments are only downloaded through signed URLs like resumes.
+// Keys are unique, so removing the files of a failed submission never touches those of a saved one.
+func UploadAttachment(ctx context.Context, file *multipart.FileHeader, companyName string, jobID string) (string, error) {
+	return uploadFile(ctx, file, newAttachmentKey(companyName, jobID, file.Filename))
+}
+
+// newAttachmentKey returns a key no other attachment uses, e.g. attachments/acme/job-1/<uuid>.pdf.
+// Like resume keys it does not contain the applicant's name, the original file name is kept with the attachment.
+func newAttachmentKey(companyName string, jobID string, fileName string) string {
+	return fmt.Sprintf("attachments/%s/%s/%s%s", sanitizeKeySegment(companyName), sanitizeKeySegment(jobID), uuid.NewString(), keyExtension(fileName))
+}
+
+// storedFiles tracks the files stored for a submission that is not saved yet
+type storedFiles struct {
+	keys []string
+}
+
+func (f *storedFiles) add(key string) {
+	f.keys = append(f.keys, key)
+}
+
+// remove deletes the tracked files after saving the submission failed.
+// Files that cannot be deleted are left to the orphaned file sweeper.
+func (f *storedFiles) remove(ctx context.Context) {
+	if len(f.keys) == 0 {
+		return
+	}
+	store, err := GetStorage()
+	if err != nil {
+		log.Printf("Failed to remove stored files %v: %v", f.keys, err)
+		return
+	}
+
+	// Clean up even when the request was cancelled
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range f.keys {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("Failed to remove stored file %s: %v", key, err)
+		}
+	}
+	f.keys = nil
+}
+
+// uploadFile stores the file under the given key with its original name as metadata and returns the key
+func uploadFile(ctx context.Context, file *multipart.FileHeader, key string) (string, error) {
+	store, err := GetStorage()
+	if err != nil {
+		return "", err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", fmt.Errorf("file open error: %w", err)
+	}
+	defer src.Close()
+
+	contentType, err := detectContentType(file)
+	if err != nil {
+		return "", fmt.Errorf("file read error: %w", err)
+	}
+
+	err = store.Put(ctx, key, src, storage.PutOptions{
+		ContentType: contentType,
+		Metadata:    map[string]string{storage.MetadataOriginalFilename: file.Filename},
+	})
+	if err != nil {
+		log.Printf("Failed to store %s: %v", key, err)
+		return "", err
+	}
+	return key, nil
+}
+
+// signingStorage is implemented by backends whose signed URLs are served by this API
+type signingStorage interface {
+	Signer() *storage.URLSigner
+}
+
+// OpenSignedFile opens a file requested through a signed URL of the local or in-memory backend
+func OpenSignedFile(ctx context.Context, key string, expires string, signature string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	store, err := GetStorage()
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err = storage.CleanKey(key)
+	if err != nil {
+		return nil, nil, ErrInvalidFileSignature
+	}
+
+	signing, ok := store.(signingStorage)
+	if !ok || !signing.Signer().Verify(key, expires, signature, time.Now()) {
+		return nil, nil, ErrInvalidFileSignature
+	}
+	return store.Get(ctx, key)
+}
+
+// sanitizeFilename ensures filenames are safe for S3 storage
+func sanitizeFilename(name string) string {
+	re := regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+	return re.ReplaceAllString(name, "_")
+}
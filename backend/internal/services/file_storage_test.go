@@ -0,0 +1,72 @@
+package services
+
+import (
+	"backend/internal/storage"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResumeKey(t *testing.T) {
+	first := newResumeKey("Acme Corp", "J_123", "John Smith.PDF")
+	second := newResumeKey("Acme Corp", "J_123", "John Smith.PDF")
+
+	// Applicants with the same name never share a key
+	assert.NotEqual(t, first, second)
+	assert.True(t, strings.HasPrefix(first, "resumes/acme_corp/j_123/"), first)
+	assert.True(t, strings.HasSuffix(first, ".pdf"), first)
+	assert.True(t, strings.HasPrefix(first, resumeKeyPrefix("Acme Corp", "J_123")))
+
+	// Names that cannot be used as a key segment
+	key := newResumeKey("../..", "", "resume.p/df")
+	assert.True(t, strings.HasPrefix(key, "resumes/_/_/"), key)
+	assert.Equal(t, 4, len(strings.Split(key, "/")), key)
+	assert.NotContains(t, key, "..")
+}
+
+func TestNewAttachmentKey(t *testing.T) {
+	first := newAttachmentKey("Acme Corp", "J_123", "Cover Letter.PDF")
+	second := newAttachmentKey("Acme Corp", "J_123", "Cover Letter.PDF")
+
+	// Attachments never share a key and the key does not name the applicant or the file
+	assert.NotEqual(t, first, second)
+	assert.True(t, strings.HasPrefix(first, "attachments/acme_corp/j_123/"), first)
+	assert.True(t, strings.HasSuffix(first, ".pdf"), first)
+	assert.NotContains(t, strings.ToLower(first), "cover")
+	assert.Equal(t, 4, len(strings.Split(first, "/")), first)
+}
+
+func TestStoredResumeDownloadName(t *testing.T) {
+	resume := storedResume{Key: "resumes/acme/j_1/0c9e.pdf", FileName: "Jane Doe CV.pdf", Username: "Jane Doe"}
+	assert.Equal(t, "Jane_Doe_CV.pdf", resume.downloadName())
+
+	// Header injection through the original name
+	resume.FileName = `cv".pdf`
+	assert.Equal(t, "cv.pdf", resume.downloadName())
+
+	// Resumes uploaded before the name was kept
+	resume.FileName = ""
+	assert.Equal(t, "Jane_Doe_resume.pdf", resume.downloadName())
+}
+
+func TestStoredFilesRemove(t *testing.T) {
+	store := storage.NewMemoryStorage(&storage.URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("test")})
+	SetStorage(store)
+	defer SetStorage(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	store.Put(ctx, "resumes/acme/j_1/new.pdf", strings.NewReader("new"), storage.PutOptions{})
+	store.Put(ctx, "attachments/j_1/cover.pdf", strings.NewReader("cover"), storage.PutOptions{})
+	store.Put(ctx, "resumes/acme/j_1/saved.pdf", strings.NewReader("saved"), storage.PutOptions{})
+
+	stored := &storedFiles{}
+	stored.add("resumes/acme/j_1/new.pdf")
+	stored.add("attachments/j_1/cover.pdf")
+
+	// Files are removed even when the request was cancelled
+	cancel()
+	stored.remove(ctx)
+	assert.Equal(t, []string{"resumes/acme/j_1/saved.pdf"}, store.Keys())
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"backend/internal/database"
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// sweptPrefixes are the folders of submission files and resume previews, quarantined files are kept for inspection
+var sweptPrefixes = []string{"resumes/", "attachments/", previewKeyPrefix}
+
+// FileSweepResult reports what SweepOrphanedFiles found
+type FileSweepResult struct {
+	Checked int      // stored files in the swept folders
+	Deleted []string // keys of the removed files
+}
+
+// SweepOrphanedFiles removes stored resumes, attachments and previews that no submission, current or archived, refers to.
+// Files younger than gracePeriod are kept, they may belong to an application that is still being saved.
+func SweepOrphanedFiles(ctx context.Context, gracePeriod time.Duration) (*FileSweepResult, error) {
+	store, err := GetStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	// List before loading the references, a file saved in between is then always seen as referenced
+	cutoff := time.Now().Add(-gracePeriod)
+	var candidates []string
+	result := &FileSweepResult{}
+	for _, prefix := range sweptPrefixes {
+		objects, err := store.List(ctx, prefix)
+		if err != nil {
+			return nil, err
+		}
+		result.Checked += len(objects)
+		for _, object := range objects {
+			if object.LastModified.Before(cutoff) {
+				candidates = append(candidates, object.Key)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return result, nil
+	}
+
+	referenced, err := referencedFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range candidates {
+		// Resumes and attachments are referenced by key, previews through their resume. Keys do not
+		// depend on the storage backend or its URLs, so a moved bucket or base URL never orphans a file.
+		if referenced[key] {
+			continue
+		}
+		if strings.HasPrefix(key, previewKeyPrefix) && referenced[previewResumeKey(key)] {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete orphaned file %s: %v", key, err)
+			continue
+		}
+		result.Deleted = append(result.Deleted, key)
+	}
+	return result, nil
+}
+
+// referencedFiles returns the resume and attachment keys of all submissions and their earlier versions
+func referencedFiles(ctx context.Context) (map[string]bool, error) {
+	rows, err := database.GetDB().QueryContext(ctx, `
+		SELECT resume_key FROM job_submissions
+		UNION SELECT resume_key FROM job_submission_history
+		UNION SELECT file_key FROM submission_attachments
+		UNION SELECT attachment->>'file_key'
+			FROM job_submission_history, jsonb_array_elements(attachments) AS attachment
+			WHERE attachment->>'file_key' IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	referenced := map[string]bool{}
+	for rows.Next() {
+		var ref string
+		if err := rows.Scan(&ref); err != nil {
+			return nil, err
+		}
+		referenced[ref] = true
+	}
+	return referenced, rows.Err()
+}
+
+// RunOrphanSweeper removes unreferenced files every interval until the context is done
+func RunOrphanSweeper(ctx context.Context, interval time.Duration, gracePeriod time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := SweepOrphanedFiles(ctx, gracePeriod)
+			if err != nil {
+				log.Printf("Failed to sweep orphaned files: %v", err)
+				continue
+			}
+			if len(result.Deleted) > 0 {
+				log.Printf("Removed %d orphaned files", len(result.Deleted))
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// defaultFormSource is used when a form link carries no source or utm_source parameter
+const defaultFormSource = "direct"
+
+// resolveFormSource picks the attribution source for an event: utm_source wins over source
+func resolveFormSource(tracking models.FormTracking) string {
+	source := strings.TrimSpace(tracking.UTMSource)
+	if source == "" {
+		source = strings.TrimSpace(tracking.Source)
+	}
+	if source == "" {
+		return defaultFormSource
+	}
+	return strings.ToLower(source)
+}
+
+// RecordFormEvent stores a view/start/submit event for the given application form
+func RecordFormEvent(ctx context.Context, formUUID string, eventType string, tracking models.FormTracking) error {
+	db := database.GetDB()
+
+	var exists bool
+	err := db.GetContext(ctx, &exists, "SELECT EXISTS(SELECT 1 FROM application_form WHERE form_uuid = $1)", formUUID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrFormNotFound
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO form_events (form_uuid, event_type, source, utm_source, utm_medium, utm_campaign)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		formUUID,
+		eventType,
+		resolveFormSource(tracking),
+		tracking.UTMSource,
+		tracking.UTMMedium,
+		tracking.UTMCampaign)
+	return err
+}
+
+// GetFormFunnel builds the view -> start -> submit funnel for a form owned by the logged in HR user.
+// fromDate/toDate (YYYY-MM-DD) and source are optional filters.
+func GetFormFunnel(ctx context.Context, formUUID string, fromDate string, toDate string, source string) (*models.FormFunnelResponse, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID")
+
+	// Only the HR user who owns the job can see the form analytics
+	var formExists bool
+	err := db.GetContext(ctx, &formExists, `
+		SELECT EXISTS(
+			SELECT 1 FROM application_form af
+			JOIN jobs j ON af.job_id = j.id
+			WHERE af.form_uuid = $1 AND j.user_id = $2)`,
+		formUUID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !formExists {
+		return nil, ErrFormNotFound
+	}
+
+	query := `
+		SELECT to_char(date_trunc('day', created_at), 'YYYY-MM-DD') AS day,
+			   source,
+			   COUNT(*) FILTER (WHERE event_type = 'view') AS views,
+			   COUNT(*) FILTER (WHERE event_type = 'start') AS starts,
+			   COUNT(*) FILTER (WHERE event_type = 'submit') AS submissions
+		FROM form_events
+		WHERE form_uuid = $1`
+
+	args := []interface{}{formUUID}
+	argCount := 2
+
+	if fromDate != "" {
+		query += fmt.Sprintf(" AND created_at >= $%d::date", argCount)
+		args = append(args, fromDate)
+		argCount++
+	}
+
+	if toDate != "" {
+		query += fmt.Sprintf(" AND created_at < $%d::date + INTERVAL '1 day'", argCount)
+		args = append(args, toDate)
+		argCount++
+	}
+
+	if source != "" {
+		query += fmt.Sprintf(" AND source = $%d", argCount)
+		args = append(args, strings.ToLower(source))
+	}
+
+	query += " GROUP BY day, source ORDER BY day, source"
+
+	var rows []models.DailyFunnel
+	if err := db.SelectContext(ctx, &rows, query, args...); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	response := &models.FormFunnelResponse{
+		FormUUID:       formUUID,
+		FromDate:       fromDate,
+		ToDate:         toDate,
+		BySource:       []models.SourceFunnel{},
+		ByDay:          []models.DailyFunnel{},
+		BySourceAndDay: []models.DailyFunnel{},
+	}
+
+	bySource := map[string]*models.FunnelStats{}
+	byDay := map[string]*models.FunnelStats{}
+
+	for _, row := range rows {
+		addFunnelCounts(&response.Totals, row.FunnelStats)
+
+		if bySource[row.Source] == nil {
+			bySource[row.Source] = &models.FunnelStats{}
+		}
+		addFunnelCounts(bySource[row.Source], row.FunnelStats)
+
+		if byDay[row.Day] == nil {
+			byDay[row.Day] = &models.FunnelStats{}
+		}
+		addFunnelCounts(byDay[row.Day], row.FunnelStats)
+
+		finalizeFunnel(&row.FunnelStats)
+		response.BySourceAndDay = append(response.BySourceAndDay, row)
+	}
+	finalizeFunnel(&response.Totals)
+
+	for src, stats := range bySource {
+		finalizeFunnel(stats)
+		response.BySource = append(response.BySource, models.SourceFunnel{Source: src, FunnelStats: *stats})
+	}
+	sort.Slice(response.BySource, func(i, j int) bool {
+		if response.BySource[i].Views != response.BySource[j].Views {
+			return response.BySource[i].Views > response.BySource[j].Views
+		}
+		return response.BySource[i].Source < response.BySource[j].Source
+	})
+
+	for day, stats := range byDay {
+		finalizeFunnel(stats)
+		response.ByDay = append(response.ByDay, models.DailyFunnel{Day: day, FunnelStats: *stats})
+	}
+	sort.Slice(response.ByDay, func(i, j int) bool {
+		return response.ByDay[i].Day < response.ByDay[j].Day
+	})
+
+	return response, nil
+}
+
+func addFunnelCounts(total *models.FunnelStats, stats models.FunnelStats) {
+	total.Views += stats.Views
+	total.Starts += stats.Starts
+	total.Submissions += stats.Submissions
+}
+
+// finalizeFunnel computes the rates and drop-off once all counts are summed up
+func finalizeFunnel(stats *models.FunnelStats) {
+	stats.ConversionRate = percentage(stats.Submissions, stats.Views)
+	stats.StartRate = percentage(stats.Starts, stats.Views)
+
+	stats.DropOff = stats.Starts - stats.Submissions
+	if stats.DropOff < 0 {
+		stats.DropOff = 0
+	}
+}
+
+func percentage(part int, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 100
+}
@@ -4,23 +4,70 @@ import (
     "backend/internal/database"
     "backend/internal/models"
     "context"
+    "database/sql"
     "encoding/json"
     "errors"
 )
 
 var (
     ErrFormTemplateIdDoesNotExists = errors.New("form template does not exists for this user")
-    ErrFormTemplateIdExists = errors.New("form template id already exists for this user")
+    ErrFormTemplateIdExists = errors.New("form template id already exists in your company")
 )
 
+// formTemplateColumns is the column list scanned by scanFormTemplate
+const formTemplateColumns = `ft.id, ft.form_template_id, ft.user_id, ft.company_name, ft.shared, ft.copied_from_id,
+    u.username, ft.fields, ft.created_at, ft.updated_at`
+
+type rowScanner interface {
+    Scan(dest ...interface{}) error
+}
+
+func scanFormTemplate(row rowScanner) (*models.FormTemplate, error) {
+    var template models.FormTemplate
+    var copiedFromID sql.NullInt64
+    var fieldsJSON []byte
+
+    err := row.Scan(
+        &template.ID,
+        &template.FormTemplateID,
+        &template.UserID,
+        &template.CompanyName,
+        &template.Shared,
+        &copiedFromID,
+        &template.OwnerUsername,
+        &fieldsJSON,
+        &template.CreatedAt,
+        &template.UpdatedAt,
+    )
+    if err != nil {
+        return nil, err
+    }
+
+    if copiedFromID.Valid {
+        id := int(copiedFromID.Int64)
+        template.CopiedFromID = &id
+    }
+
+    // Unmarshal attributes JSON
+    if err := json.Unmarshal(fieldsJSON, &template.Fields); err != nil {
+        return nil, err
+    }
+
+    return &template, nil
+}
 
 func CreateFormTemplate(ctx context.Context, req *models.FormTemplate) error {
     db := database.GetDB()
     userID := ctx.Value("userID")
 
-    // Check if form template already exists for this user
+    companyName, err := getUserCompanyName(ctx, userID)
+    if err != nil {
+        return err
+    }
+
+    // Template ids are unique per company, so teammates can link each other's shared templates by id
     var count int
-    err := db.GetContext(ctx, &count, "SELECT COUNT(*) FROM form_templates WHERE form_template_id = $1 AND user_id = $2", req.FormTemplateID, userID)
+    err = db.GetContext(ctx, &count, "SELECT COUNT(*) FROM form_templates WHERE form_template_id = $1 AND company_name = $2", req.FormTemplateID, companyName)
     if err != nil {
         return err
     }
@@ -37,90 +84,148 @@ func CreateFormTemplate(ctx context.Context, req *models.FormTemplate) error {
 
 
     query := `INSERT INTO form_templates (
-        form_template_id, 
-        user_id, 
+        form_template_id,
+        user_id,
+        company_name,
+        shared,
+        copied_from_id,
         fields
-    ) VALUES ($1, $2, $3)`
-    _, err = db.ExecContext(ctx, query, 
-        req.FormTemplateID, 
-        userID, 
-        fieldsJSON)
+    ) VALUES ($1, $2, $3, $4, $5, $6)
+    RETURNING id, created_at, updated_at`
+    err = db.QueryRowContext(ctx, query,
+        req.FormTemplateID,
+        userID,
+        companyName,
+        req.Shared,
+        req.CopiedFromID,
+        fieldsJSON).Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+    if err != nil {
+        // Two teammates racing on the same id
+        if isUniqueViolation(err) {
+            return ErrFormTemplateIdExists
+        }
+        return err
+    }
 
-    return err
+    req.UserID = userID.(int)
+    req.CompanyName = companyName
+    return nil
 }
 
 
+// GetFormTemplateById returns one of the user's own templates or a template shared within their company
 func GetFormTemplateById(ctx context.Context, templateID string) (*models.FormTemplate, error) {
     db := database.GetDB()
     userID := ctx.Value("userID")
 
-    var template models.FormTemplate
-	var fieldsJSON []byte
-
-    query := `SELECT id, form_template_id, user_id, fields, created_at, updated_at 
-              FROM form_templates WHERE form_template_id = $1 AND user_id = $2`
-    
-	err := db.QueryRowContext(ctx, query, templateID, userID).Scan(
-			&template.ID,
-			&template.FormTemplateID,
-			&template.UserID,
-			&fieldsJSON,
-			&template.CreatedAt,
-			&template.UpdatedAt,
-	)
-
-	if err != nil {
-		return nil, err
-	}
-
-	// Unmarshal attributes JSON
-	if err := json.Unmarshal(fieldsJSON, &template.Fields); err != nil {
-        return nil, err
-    }
+    query := `SELECT ` + formTemplateColumns + `
+              FROM form_templates ft
+              JOIN users u ON ft.user_id = u.id
+              WHERE ft.form_template_id = $1
+              AND ft.company_name = (SELECT company_name FROM users WHERE id = $2)
+              AND (ft.user_id = $2 OR ft.shared)`
 
-	return &template, nil
+    return scanFormTemplate(db.QueryRowContext(ctx, query, templateID, userID))
 }
 
 
 func GetFormTemplatesByUserId(ctx context.Context) ([]*models.FormTemplate, error) {
-    db := database.GetDB()
     userID := ctx.Value("userID")
 
-    var formTemplates []*models.FormTemplate
-    query := `SELECT id, form_template_id, user_id, fields, created_at, updated_at
-              FROM form_templates WHERE user_id = $1`
+    query := `SELECT ` + formTemplateColumns + `
+              FROM form_templates ft
+              JOIN users u ON ft.user_id = u.id
+              WHERE ft.user_id = $1`
+
+    return queryFormTemplates(ctx, query, userID)
+}
+
+
+// GetSharedFormTemplates lists the company template library: every template shared by the user's teammates (and the user)
+func GetSharedFormTemplates(ctx context.Context) ([]*models.FormTemplate, error) {
+    userID := ctx.Value("userID")
 
-    rows, err := db.QueryContext(ctx, query, userID)
+    companyName, err := getUserCompanyName(ctx, userID)
+    if err != nil {
+        return nil, err
+    }
+
+    query := `SELECT ` + formTemplateColumns + `
+              FROM form_templates ft
+              JOIN users u ON ft.user_id = u.id
+              WHERE ft.company_name = $1 AND ft.shared
+              ORDER BY ft.form_template_id`
+
+    return queryFormTemplates(ctx, query, companyName)
+}
+
+func queryFormTemplates(ctx context.Context, query string, args ...interface{}) ([]*models.FormTemplate, error) {
+    db := database.GetDB()
+
+    rows, err := db.QueryContext(ctx, query, args...)
     if err != nil {
         return nil, err
     }
     defer rows.Close()
 
+    formTemplates := []*models.FormTemplate{}
     for rows.Next() {
-        var formTemplate models.FormTemplate
-        var fieldsJSON []byte
-
-        err := rows.Scan(
-            &formTemplate.ID,
-            &formTemplate.FormTemplateID,
-            &formTemplate.UserID,
-            &fieldsJSON,
-            &formTemplate.CreatedAt,
-            &formTemplate.UpdatedAt,
-        )
+        formTemplate, err := scanFormTemplate(rows)
         if err != nil {
             return nil, err
         }
+        formTemplates = append(formTemplates, formTemplate)
+    }
 
-        // Unmarshal the fields JSON into the map
-        if err := json.Unmarshal(fieldsJSON, &formTemplate.Fields); err != nil {
-            return nil, err
+    return formTemplates, rows.Err()
+}
+
+
+// UpdateFormTemplateSharing adds or removes one of the user's own templates from the company library
+func UpdateFormTemplateSharing(ctx context.Context, formTemplateID string, shared bool) (*models.FormTemplate, error) {
+    db := database.GetDB()
+    userID := ctx.Value("userID")
+
+    result, err := db.ExecContext(ctx, `
+        UPDATE form_templates SET shared = $1, updated_at = NOW()
+        WHERE form_template_id = $2 AND user_id = $3`,
+        shared, formTemplateID, userID)
+    if err != nil {
+        return nil, err
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return nil, err
+    }
+    if rowsAffected == 0 {
+        return nil, ErrFormTemplateIdDoesNotExists
+    }
+
+    return GetFormTemplateById(ctx, formTemplateID)
+}
+
+
+// CopyFormTemplate copies an own or company-shared template into a new private template of the user
+func CopyFormTemplate(ctx context.Context, sourceTemplateID string, newTemplateID string) (*models.FormTemplate, error) {
+    source, err := GetFormTemplateById(ctx, sourceTemplateID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return nil, ErrFormTemplateNotFound
         }
+        return nil, err
+    }
 
-        formTemplates = append(formTemplates, &formTemplate)
+    copied := &models.FormTemplate{
+        FormTemplateID: newTemplateID,
+        Fields:         source.Fields,
+        Shared:         false,
+        CopiedFromID:   &source.ID,
+    }
+    if err := CreateFormTemplate(ctx, copied); err != nil {
+        return nil, err
     }
 
-    return formTemplates, nil
+    return copied, nil
 }
 
 
@@ -10,81 +10,217 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 var (
 	ErrInterviewNotFound    = errors.New("invalid interview id")
 	ErrUnauthorizedFeedback = errors.New("unauthorized: only interviewers can submit feedback")
+	ErrDuplicatePanelist    = errors.New("an interviewer can only be on the panel once")
+	ErrSlotNotOwned         = errors.New("availability slot does not belong to the interviewer")
+	ErrSlotInPast           = errors.New("availability slot has already started")
+	ErrSubmissionNotForJob  = errors.New("job submission does not belong to the job")
+	ErrInterviewCancelled   = errors.New("feedback cannot be submitted for a cancelled interview")
 )
 
+// CreateInterview books an interview with one interviewer, or a panel when further interviewers are given.
+// Every interviewer brings one of their own free availability slots that has not started yet, and the
+// submission must be an application to the current user's job.
 func CreateInterview(ctx context.Context, req *models.CreateInterviewRequest) (*models.Interview, error) {
 	db := database.GetDB()
 	hrUserID := ctx.Value("userID").(int)
 
-	// Check if availability is already used in an interview
-	var count int
-	err := db.GetContext(ctx, &count, `
-		SELECT COUNT(*) FROM interviews 
-		WHERE availability_id = $1`,
-		req.AvailabilityID)
+	interviewers := map[int]bool{req.InterviewerID: true}
+	for _, panelist := range req.Panelists {
+		if interviewers[panelist.InterviewerID] {
+			return nil, ErrDuplicatePanelist
+		}
+		interviewers[panelist.InterviewerID] = true
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
-	if count > 0 {
-		return nil, ErrOverlappingSlot
+	defer tx.Rollback()
+
+	var jobDBID int
+	err = tx.GetContext(ctx, &jobDBID, "SELECT id FROM jobs WHERE job_id = $1 AND user_id = $2", req.JobID, hrUserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
 	}
 
-	// Insert new interview
-	_, err = db.ExecContext(ctx, `
+	// Job ids are only unique per user, the submission must be made through a form of this very job
+	var submissionJobDBID int
+	err = tx.GetContext(ctx, &submissionJobDBID, `
+		SELECT af.job_id
+		FROM job_submissions js
+		JOIN application_form af ON js.form_uuid = af.form_uuid
+		WHERE js.id = $1`, req.JobSubmissionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	if submissionJobDBID != jobDBID {
+		return nil, ErrSubmissionNotForJob
+	}
+
+	if req.RoundID != nil {
+		if _, err := getJobInterviewRound(ctx, tx, req.JobID, *req.RoundID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if _, err := checkInterviewSlot(ctx, tx, req.AvailabilityID, req.InterviewerID, now); err != nil {
+		return nil, err
+	}
+	for _, panelist := range req.Panelists {
+		if _, err := checkInterviewSlot(ctx, tx, panelist.AvailabilityID, panelist.InterviewerID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	var interviewID int
+	err = tx.QueryRowContext(ctx, `
 		INSERT INTO interviews (
 			job_id, 
+			round_id,
 			hr_user_id, 
 			job_submission_id, 
 			interviewer_user_id, 
 			availability_id,
 			status
-		) VALUES ($1, $2, $3, $4, $5, 'scheduled')`,
+		) VALUES ($1, $2, $3, $4, $5, $6, 'scheduled')
+		RETURNING id`,
 		req.JobID,
+		req.RoundID,
 		hrUserID,
 		req.JobSubmissionID,
 		req.InterviewerID,
-		req.AvailabilityID)
-
-	interview := &models.Interview{
-		JobID:           req.JobID,
-		HRUserID:        hrUserID,
-		JobSubmissionID: req.JobSubmissionID,
-		InterviewerID:   req.InterviewerID,
-		AvailabilityID:  req.AvailabilityID,
-		Status:          "scheduled",
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
-	}
-	return interview, err
+		req.AvailabilityID).Scan(&interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, panelist := range req.Panelists {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO interview_panelists (interview_id, interviewer_user_id, availability_id)
+			VALUES ($1, $2, $3)`, interviewID, panelist.InterviewerID, panelist.AvailabilityID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return getInterview(ctx, interviewID, false, 0)
 }
 
-func DeleteInterview(ctx context.Context, interviewID int) error {
+// interviewSlot is an availability slot booked for an interview
+type interviewSlot struct {
+	UserID   int    `db:"user_id"`
+	Date     string `db:"date"`
+	FromTime string `db:"from_time"`
+	ToTime   string `db:"to_time"`
+}
+
+// label describes the slot in the interview changes, e.g. 2030-01-15 10:00-11:00
+func (s *interviewSlot) label() string {
+	return s.Date + " " + s.FromTime + "-" + s.ToTime
+}
+
+// start is the beginning of the slot in the server's time zone, like the interview status updates
+func (s *interviewSlot) start() (time.Time, error) {
+	return time.ParseInLocation("2006-01-02 15:04", s.Date+" "+s.FromTime, time.Local)
+}
+
+// checkInterviewSlot verifies that a slot belongs to the interviewer, has not started and is not booked yet.
+// The slot stays locked until the transaction ends, so it cannot be booked twice concurrently.
+func checkInterviewSlot(ctx context.Context, tx *sqlx.Tx, availabilityID int, interviewerID int, now time.Time) (*interviewSlot, error) {
+	var slot interviewSlot
+	err := tx.GetContext(ctx, &slot, `
+		SELECT user_id, TO_CHAR(date, 'YYYY-MM-DD') AS date, TO_CHAR(from_time, 'HH24:MI') AS from_time, TO_CHAR(to_time, 'HH24:MI') AS to_time
+		FROM availabilities
+		WHERE id = $1
+		FOR UPDATE`, availabilityID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNoAvailability
+		}
+		return nil, err
+	}
+	if slot.UserID != interviewerID {
+		return nil, ErrSlotNotOwned
+	}
+	start, err := slot.start()
+	if err != nil {
+		return nil, err
+	}
+	if !start.After(now) {
+		return nil, ErrSlotInPast
+	}
+
+	// Slots of cancelled interviews can be booked again
+	var booked bool
+	err = tx.GetContext(ctx, &booked, `
+		SELECT EXISTS (SELECT 1 FROM interviews WHERE availability_id = $1 AND status <> $2)
+			OR EXISTS (SELECT 1 FROM interview_panelists WHERE availability_id = $1 AND cancelled_at IS NULL)`,
+		availabilityID, models.InterviewStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if booked {
+		return nil, ErrOverlappingSlot
+	}
+	return &slot, nil
+}
+
+// getInterview returns a persisted interview with the time of its slot, its panel and its changes.
+// Interviewers only see their own feedback.
+func getInterview(ctx context.Context, interviewID int, hideOthers bool, userID int) (*models.Interview, error) {
 	db := database.GetDB()
-	userID := ctx.Value("userID").(int)
 
-	// Check if interview exists for this HR user
-	var count int
-	err := db.GetContext(ctx, &count, `
-		SELECT COUNT(*) FROM interviews 
-		WHERE id = $1 AND hr_user_id = $2`,
-		interviewID, userID)
+	var interview models.Interview
+	err := db.GetContext(ctx, &interview, `
+		SELECT i.id, i.job_id, i.round_id, i.hr_user_id, i.job_submission_id, i.interviewer_user_id,
+			COALESCE(i.availability_id, 0) AS availability_id, COALESCE(i.feedback, '') AS feedback, i.verdict, i.status,
+			i.created_at, i.updated_at, COALESCE(TO_CHAR(a.date, 'YYYY-MM-DD'), '') AS date,
+			COALESCE(TO_CHAR(a.from_time, 'HH24:MI'), '') AS from_time, COALESCE(TO_CHAR(a.to_time, 'HH24:MI'), '') AS to_time
+		FROM interviews i
+		LEFT JOIN availabilities a ON i.availability_id = a.id
+		WHERE i.id = $1`, interviewID)
 	if err != nil {
-		return err
+		if err == sql.ErrNoRows {
+			return nil, ErrInterviewNotFound
+		}
+		return nil, err
 	}
-	if count == 0 {
-		return ErrInterviewNotFound
+	if err := loadPanelists(ctx, []*models.Interview{&interview}, hideOthers, userID); err != nil {
+		return nil, err
 	}
 
-	// Delete interview
-	_, err = db.ExecContext(ctx, `
-		DELETE FROM interviews 
-		WHERE id = $1 AND hr_user_id = $2`,
-		interviewID, userID)
+	err = db.SelectContext(ctx, &interview.Changes, `
+		SELECT id, user_id, action, reason, previous_slot, new_slot, created_at
+		FROM interview_changes
+		WHERE interview_id = $1
+		ORDER BY created_at, id`, interviewID)
+	if err != nil {
+		return nil, err
+	}
+	return &interview, nil
+}
+
+// DeleteInterview cancels an interview without a reason, the interview is kept with the cancelled status
+func DeleteInterview(ctx context.Context, interviewID int) error {
+	_, err := CancelInterview(ctx, interviewID, "")
 	return err
 }
 
@@ -114,7 +250,7 @@ func ListAllInterviews(ctx *gin.Context) ([]*models.Interview, error) {
 
 	// base query
 	query := `
-		SELECT i.id, i.job_id, i.hr_user_id, i.job_submission_id, 
+		SELECT i.id, i.job_id, i.round_id, i.hr_user_id, i.job_submission_id, 
 			   i.interviewer_user_id, i.availability_id, i.status, 
 			   i.created_at, i.updated_at, a.date, a.to_time,
 			   i.feedback, i.verdict
@@ -131,7 +267,8 @@ func ListAllInterviews(ctx *gin.Context) ([]*models.Interview, error) {
 		args = append(args, userID)
 		argCount++
 	} else {
-		query += fmt.Sprintf(" AND i.interviewer_user_id = $%d", argCount)
+		query += fmt.Sprintf(` AND (i.interviewer_user_id = $%d
+			OR EXISTS (SELECT 1 FROM interview_panelists p WHERE p.interview_id = i.id AND p.interviewer_user_id = $%d))`, argCount, argCount)
 		args = append(args, userID)
 		argCount++
 	}
@@ -167,6 +304,7 @@ func ListAllInterviews(ctx *gin.Context) ([]*models.Interview, error) {
 		err := rows.Scan(
 			&interview.ID,
 			&interview.JobID,
+			&interview.RoundID,
 			&interview.HRUserID,
 			&interview.JobSubmissionID,
 			&interview.InterviewerID,
@@ -216,10 +354,60 @@ func ListAllInterviews(ctx *gin.Context) ([]*models.Interview, error) {
 
 		interviews = append(interviews, interview)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	if err := loadPanelists(ctx.Request.Context(), interviews, userRole != "HR", userID); err != nil {
+		return nil, err
+	}
 	return interviews, nil
 }
 
+// loadPanelists adds the further interviewers of panel interviews. Interviewers do not see
+// the feedback of the rest of the panel, so every verdict is given independently.
+func loadPanelists(ctx context.Context, interviews []*models.Interview, hideOthers bool, userID int) error {
+	if len(interviews) == 0 {
+		return nil
+	}
+	byID := map[int]*models.Interview{}
+	ids := make([]int, 0, len(interviews))
+	for _, interview := range interviews {
+		byID[interview.ID] = interview
+		ids = append(ids, interview.ID)
+	}
+
+	var panelists []struct {
+		InterviewID int `db:"interview_id"`
+		models.InterviewPanelist
+	}
+	err := database.GetDB().SelectContext(ctx, &panelists, `
+		SELECT interview_id, interviewer_user_id, COALESCE(availability_id, 0) AS availability_id, COALESCE(feedback, '') AS feedback, verdict
+		FROM interview_panelists
+		WHERE interview_id = ANY($1)
+		ORDER BY id`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	for _, panelist := range panelists {
+		if hideOthers && panelist.InterviewerID != userID {
+			panelist.Feedback, panelist.Verdict = "", ""
+		}
+		interview := byID[panelist.InterviewID]
+		interview.Panelists = append(interview.Panelists, panelist.InterviewPanelist)
+	}
+
+	if hideOthers {
+		for _, interview := range interviews {
+			if interview.InterviewerID != userID {
+				interview.Feedback, interview.Verdict = "", ""
+			}
+		}
+	}
+	return nil
+}
+
+// SubmitFeedback saves the verdict of an interviewer with their ratings of the job's scorecard
 func SubmitFeedback(ctx context.Context, req *models.FeedbackRequest, interviewID int) error {
 
 	db := database.GetDB()
@@ -236,25 +424,75 @@ func SubmitFeedback(ctx context.Context, req *models.FeedbackRequest, interviewI
 		return ErrUnauthorizedFeedback
 	}
 
-	// Check if interview exists and user is the assigned interviewer
-	var count int
-	err = db.GetContext(ctx, &count, `
-		SELECT COUNT(*) FROM interviews 
-		WHERE id = $1 AND interviewer_user_id = $2`,
-		interviewID, userID)
+	tx, err := db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	if count == 0 {
-		return ErrInterviewNotFound
+	defer tx.Rollback()
+
+	var interview struct {
+		Status  string `db:"status"`
+		JobDBID int    `db:"job_db_id"`
+	}
+	err = tx.GetContext(ctx, &interview, `
+		SELECT i.status, COALESCE(j.id, 0) AS job_db_id
+		FROM interviews i
+		LEFT JOIN jobs j ON j.job_id = i.job_id AND j.user_id = i.hr_user_id
+		WHERE i.id = $1
+		FOR UPDATE OF i`, interviewID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrInterviewNotFound
+		}
+		return err
+	}
+	if interview.Status == models.InterviewStatusCancelled {
+		return ErrInterviewCancelled
 	}
 
-	// Update interview with feedback and status
-	_, err = db.ExecContext(ctx, `
+	// Jobs with a scorecard are rated competency by competency
+	competencies, err := jobCompetencies(ctx, tx, interview.JobDBID)
+	if err != nil {
+		return err
+	}
+	if err := checkScores(competencies, req); err != nil {
+		return err
+	}
+
+	// The user is the first interviewer of the interview or on its panel
+	result, err := tx.ExecContext(ctx, `
 		UPDATE interviews 
-		SET verdict = $1, feedback = $2, status = 'completed', updated_at = NOW()
-		WHERE id = $3`,
-		req.Verdict, req.Feedback, interviewID)
+		SET verdict = $1, feedback = $2, updated_at = NOW()
+		WHERE id = $3 AND interviewer_user_id = $4`,
+		req.Verdict, req.Feedback, interviewID, userID)
+	if err != nil {
+		return err
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		result, err = tx.ExecContext(ctx, `
+			UPDATE interview_panelists
+			SET verdict = $1, feedback = $2, submitted_at = NOW()
+			WHERE interview_id = $3 AND interviewer_user_id = $4`,
+			req.Verdict, req.Feedback, interviewID, userID)
+		if err != nil {
+			return err
+		}
+		if updated, _ := result.RowsAffected(); updated == 0 {
+			return ErrInterviewNotFound
+		}
+	}
+	if err := saveScores(ctx, tx, interviewID, userID, competencies, req.Scores); err != nil {
+		return err
+	}
 
-	return err
+	// The interview is completed once every interviewer gave feedback
+	_, err = tx.ExecContext(ctx, `
+		UPDATE interviews SET status = 'completed', updated_at = NOW()
+		WHERE id = $1 AND feedback IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM interview_panelists WHERE interview_id = $1 AND feedback IS NULL)`,
+		interviewID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
\ No newline at end of file
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrInterviewClosed = errors.New("completed or cancelled interviews cannot be changed")
+	ErrNotOnPanel      = errors.New("interviewer is not on the panel of the interview")
+)
+
+// lockedInterview is an interview being changed, locked until the transaction ends
+type lockedInterview struct {
+	ID            int    `db:"id"`
+	InterviewerID int    `db:"interviewer_user_id"`
+	Status        string `db:"status"`
+	Slot          string `db:"slot"` // label of the current slot, empty once the slot was removed
+}
+
+// lockInterview returns an interview the current user booked as HR or leads as interviewer
+func lockInterview(ctx context.Context, tx *sqlx.Tx, interviewID int, userID int) (*lockedInterview, error) {
+	var interview lockedInterview
+	err := tx.GetContext(ctx, &interview, `
+		SELECT i.id, i.interviewer_user_id, i.status,
+			COALESCE(TO_CHAR(a.date, 'YYYY-MM-DD') || ' ' || TO_CHAR(a.from_time, 'HH24:MI') || '-' || TO_CHAR(a.to_time, 'HH24:MI'), '') AS slot
+		FROM interviews i
+		LEFT JOIN availabilities a ON i.availability_id = a.id
+		WHERE i.id = $1 AND (i.hr_user_id = $2 OR i.interviewer_user_id = $2)
+		FOR UPDATE OF i`, interviewID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInterviewNotFound
+		}
+		return nil, err
+	}
+	if interview.Status == models.InterviewStatusCompleted || interview.Status == models.InterviewStatusCancelled {
+		return nil, ErrInterviewClosed
+	}
+	return &interview, nil
+}
+
+// recordInterviewChange adds a reschedule or cancellation to the history of an interview
+func recordInterviewChange(ctx context.Context, tx *sqlx.Tx, interviewID int, userID int, action string, reason string, previousSlot string, newSlot string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO interview_changes (interview_id, user_id, action, reason, previous_slot, new_slot)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		interviewID, userID, action, strings.TrimSpace(reason), previousSlot, newSlot)
+	return err
+}
+
+// GetInterview returns an interview to the HR user who booked it or to one of its interviewers,
+// with its panel and changes. Interviewers only see their own feedback.
+func GetInterview(ctx context.Context, interviewID int) (*models.Interview, error) {
+	userID := ctx.Value("userID").(int)
+
+	var hrUserID sql.NullInt64
+	err := database.GetDB().GetContext(ctx, &hrUserID, `
+		SELECT i.hr_user_id
+		FROM interviews i
+		WHERE i.id = $1 AND (i.hr_user_id = $2 OR i.interviewer_user_id = $2
+			OR EXISTS (SELECT 1 FROM interview_panelists p WHERE p.interview_id = i.id AND p.interviewer_user_id = $2))`,
+		interviewID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInterviewNotFound
+		}
+		return nil, err
+	}
+	isHR := hrUserID.Valid && int(hrUserID.Int64) == userID
+	return getInterview(ctx, interviewID, !isHR, userID)
+}
+
+// CancelInterview cancels an interview for the HR user who booked it or its interviewer. The interview is kept
+// with its feedback, its slots and those of its panel can be booked again and the reason is recorded.
+func CancelInterview(ctx context.Context, interviewID int, reason string) (*models.Interview, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	interview, err := lockInterview(ctx, tx, interviewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE interviews SET status = $1, updated_at = NOW() WHERE id = $2",
+		models.InterviewStatusCancelled, interviewID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE interview_panelists SET cancelled_at = NOW() WHERE interview_id = $1 AND cancelled_at IS NULL",
+		interviewID)
+	if err != nil {
+		return nil, err
+	}
+	err = recordInterviewChange(ctx, tx, interviewID, userID, models.InterviewChangeCancelled, reason, interview.Slot, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return getInterview(ctx, interviewID, interview.InterviewerID == userID, userID)
+}
+
+// RescheduleInterview moves an interview to another free slot of its interviewer for the HR user who booked it
+// or its interviewer. Panelists given in the request move to other slots of their own, the rest of the panel
+// keeps theirs. The old slots are released in the same transaction and the reason is recorded.
+func RescheduleInterview(ctx context.Context, interviewID int, req *models.RescheduleInterviewRequest) (*models.Interview, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	moved := map[int]bool{}
+	for _, panelist := range req.Panelists {
+		if moved[panelist.InterviewerID] {
+			return nil, ErrDuplicatePanelist
+		}
+		moved[panelist.InterviewerID] = true
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	interview, err := lockInterview(ctx, tx, interviewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	slot, err := checkInterviewSlot(ctx, tx, req.AvailabilityID, interview.InterviewerID, now)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE interviews SET availability_id = $1, status = $2, updated_at = NOW()
+		WHERE id = $3`, req.AvailabilityID, models.InterviewStatusScheduled, interviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, panelist := range req.Panelists {
+		if _, err := checkInterviewSlot(ctx, tx, panelist.AvailabilityID, panelist.InterviewerID, now); err != nil {
+			return nil, err
+		}
+		result, err := tx.ExecContext(ctx, `
+			UPDATE interview_panelists SET availability_id = $1
+			WHERE interview_id = $2 AND interviewer_user_id = $3`,
+			panelist.AvailabilityID, interviewID, panelist.InterviewerID)
+		if err != nil {
+			return nil, err
+		}
+		if updated, _ := result.RowsAffected(); updated == 0 {
+			return nil, ErrNotOnPanel
+		}
+	}
+
+	err = recordInterviewChange(ctx, tx, interviewID, userID, models.InterviewChangeRescheduled, req.Reason, interview.Slot, slot.label())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return getInterview(ctx, interviewID, interview.InterviewerID == userID, userID)
+}
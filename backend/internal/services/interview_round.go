@@ -0,0 +1,207 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrInterviewRoundNotFound = errors.New("interview round not found for this job")
+	ErrInterviewRoundExists   = errors.New("the job already has an interview round with this name")
+)
+
+// getJobInterviewRound returns a round of the job with the given job_id
+func getJobInterviewRound(ctx context.Context, q sqlx.QueryerContext, jobID string, roundID int) (*models.InterviewRound, error) {
+	var round models.InterviewRound
+	err := sqlx.GetContext(ctx, q, &round, `
+		SELECT r.id, r.name, r.position, r.created_at
+		FROM interview_rounds r
+		JOIN jobs j ON r.job_id = j.id
+		WHERE r.id = $1 AND j.job_id = $2`, roundID, jobID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInterviewRoundNotFound
+		}
+		return nil, err
+	}
+	round.JobID = jobID
+	return &round, nil
+}
+
+// ListInterviewRounds returns the ordered interview rounds of a job of the current user
+func ListInterviewRounds(ctx context.Context, jobID string) ([]models.InterviewRound, error) {
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	rounds := []models.InterviewRound{}
+	err = database.GetDB().SelectContext(ctx, &rounds, `
+		SELECT id, name, position, created_at
+		FROM interview_rounds
+		WHERE job_id = $1
+		ORDER BY position`, jobDBID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rounds {
+		rounds[i].JobID = jobID
+	}
+	return rounds, nil
+}
+
+// CreateInterviewRound adds a round after the existing rounds of a job of the current user
+func CreateInterviewRound(ctx context.Context, jobID string, req models.CreateInterviewRoundRequest) (*models.InterviewRound, error) {
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	round := models.InterviewRound{JobID: jobID, Name: strings.TrimSpace(req.Name)}
+	err = database.GetDB().QueryRowContext(ctx, `
+		INSERT INTO interview_rounds (job_id, name, position)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM interview_rounds WHERE job_id = $1))
+		RETURNING id, position, created_at`, jobDBID, round.Name).Scan(&round.ID, &round.Position, &round.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrInterviewRoundExists
+		}
+		return nil, err
+	}
+	return &round, nil
+}
+
+// DeleteInterviewRound removes a round of a job of the current user.
+// Its interviews are kept, each becomes a round of its own.
+func DeleteInterviewRound(ctx context.Context, jobID string, roundID int) error {
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return err
+	}
+
+	result, err := database.GetDB().ExecContext(ctx,
+		"DELETE FROM interview_rounds WHERE id = $1 AND job_id = $2", roundID, jobDBID)
+	if err != nil {
+		return err
+	}
+	if deleted, _ := result.RowsAffected(); deleted == 0 {
+		return ErrInterviewRoundNotFound
+	}
+	return nil
+}
+
+// roundFeedback is the verdict of one interviewer with the round of the interview
+type roundFeedback struct {
+	models.InterviewerFeedback
+	RoundID   *int   `db:"round_id"`
+	RoundName string `db:"round_name"`
+}
+
+// GetSubmissionInterviewRounds returns the interview rounds of a submission to the HR user owning the job,
+// with the feedback of every interviewer and the aggregate verdict of each round. Interviewers of the
+// company only see their own feedback through their interviews.
+// Interviews scheduled without a round are rounds of their own, cancelled interviews are left out.
+func GetSubmissionInterviewRounds(ctx context.Context, submissionID int) ([]models.InterviewRoundResult, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	if err := checkSubmissionOwner(ctx, db, userID, submissionID); err != nil {
+		return nil, err
+	}
+
+	feedback := []roundFeedback{}
+	err := db.SelectContext(ctx, &feedback, `
+		SELECT f.interview_id, f.interviewer_user_id, u.username AS interviewer_name, f.status, f.verdict, f.feedback,
+			i.round_id, COALESCE(r.name, '') AS round_name
+		FROM (
+			SELECT id AS interview_id, interviewer_user_id, status, verdict, COALESCE(feedback, '') AS feedback, 0 AS panel_position
+			FROM interviews
+			WHERE job_submission_id = $1 AND status <> $2
+			UNION ALL
+			SELECT p.interview_id, p.interviewer_user_id, i.status, p.verdict, COALESCE(p.feedback, ''), p.id
+			FROM interview_panelists p
+			JOIN interviews i ON p.interview_id = i.id
+			WHERE i.job_submission_id = $1 AND i.status <> $2
+		) f
+		JOIN interviews i ON f.interview_id = i.id
+		JOIN users u ON f.interviewer_user_id = u.id
+		LEFT JOIN interview_rounds r ON i.round_id = r.id
+		ORDER BY r.position NULLS LAST, i.id, f.panel_position`, submissionID, models.InterviewStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	return groupRoundFeedback(feedback), nil
+}
+
+// groupRoundFeedback groups ordered feedback into rounds, interviews without a round are grouped by interview
+func groupRoundFeedback(feedback []roundFeedback) []models.InterviewRoundResult {
+	type groupKey struct {
+		round bool
+		id    int
+	}
+	results := []models.InterviewRoundResult{}
+	index := map[groupKey]int{}
+	for _, f := range feedback {
+		key := groupKey{id: f.InterviewID}
+		if f.RoundID != nil {
+			key = groupKey{round: true, id: *f.RoundID}
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(results)
+			index[key] = i
+			results = append(results, models.InterviewRoundResult{RoundID: f.RoundID, Name: f.RoundName})
+		}
+		results[i].Feedback = append(results[i].Feedback, f.InterviewerFeedback)
+	}
+
+	for i := range results {
+		verdicts := make([]string, len(results[i].Feedback))
+		for j, f := range results[i].Feedback {
+			verdicts[j] = f.Verdict
+		}
+		results[i].Verdict = aggregateVerdict(verdicts)
+	}
+	return results
+}
+
+// verdictOutcome maps the verdict of an interviewer to passed, failed or pending
+func verdictOutcome(verdict string) string {
+	switch verdict {
+	case models.InterviewVerdictPassed, models.InterviewVerdictYes, models.InterviewVerdictStrongYes:
+		return models.InterviewVerdictPassed
+	case models.InterviewVerdictFailed, models.InterviewVerdictNo, models.InterviewVerdictStrongNo:
+		return models.InterviewVerdictFailed
+	}
+	return models.InterviewVerdictPending
+}
+
+// aggregateVerdict combines the verdicts of the interviewers of a round. The round is pending until
+// every interviewer decided, then passed when more interviewers said yes than no.
+func aggregateVerdict(verdicts []string) string {
+	passed, failed := 0, 0
+	for _, verdict := range verdicts {
+		switch verdictOutcome(verdict) {
+		case models.InterviewVerdictPassed:
+			passed++
+		case models.InterviewVerdictFailed:
+			failed++
+		default:
+			return models.InterviewVerdictPending
+		}
+	}
+	switch {
+	case passed == 0 && failed == 0:
+		return models.InterviewVerdictPending
+	case passed > failed:
+		return models.InterviewVerdictPassed
+	default:
+		return models.InterviewVerdictFailed
+	}
+}
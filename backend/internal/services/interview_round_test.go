@@ -0,0 +1,46 @@
+package services
+
+import (
+	"backend/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateVerdict(t *testing.T) {
+	assert.Equal(t, models.InterviewVerdictPending, aggregateVerdict(nil))
+	assert.Equal(t, models.InterviewVerdictPending, aggregateVerdict([]string{"passed", "pending"}))
+	assert.Equal(t, models.InterviewVerdictPassed, aggregateVerdict([]string{"passed"}))
+	assert.Equal(t, models.InterviewVerdictPassed, aggregateVerdict([]string{"passed", "failed", "passed"}))
+	// Ties fail the round
+	assert.Equal(t, models.InterviewVerdictFailed, aggregateVerdict([]string{"passed", "failed"}))
+	assert.Equal(t, models.InterviewVerdictFailed, aggregateVerdict([]string{"failed"}))
+	// Scorecard verdicts count as passed or failed, like earlier feedback
+	assert.Equal(t, models.InterviewVerdictPassed, aggregateVerdict([]string{"strong_yes", "no", "passed"}))
+	assert.Equal(t, models.InterviewVerdictFailed, aggregateVerdict([]string{"yes", "strong_no"}))
+	assert.Equal(t, models.InterviewVerdictPending, aggregateVerdict([]string{"yes", "pending"}))
+}
+
+func TestGroupRoundFeedback(t *testing.T) {
+	technical := 7
+	feedback := []roundFeedback{
+		{InterviewerFeedback: models.InterviewerFeedback{InterviewID: 1, InterviewerID: 10, Verdict: "passed"}, RoundID: &technical, RoundName: "Technical"},
+		{InterviewerFeedback: models.InterviewerFeedback{InterviewID: 1, InterviewerID: 11, Verdict: "passed"}, RoundID: &technical, RoundName: "Technical"},
+		{InterviewerFeedback: models.InterviewerFeedback{InterviewID: 2, InterviewerID: 12, Verdict: "failed"}, RoundID: &technical, RoundName: "Technical"},
+		// Interviews without a round are rounds of their own
+		{InterviewerFeedback: models.InterviewerFeedback{InterviewID: 3, InterviewerID: 10, Verdict: "pending"}},
+		{InterviewerFeedback: models.InterviewerFeedback{InterviewID: 4, InterviewerID: 11, Verdict: "failed"}},
+	}
+
+	rounds := groupRoundFeedback(feedback)
+
+	assert.Len(t, rounds, 3)
+	assert.Equal(t, &technical, rounds[0].RoundID)
+	assert.Equal(t, "Technical", rounds[0].Name)
+	assert.Len(t, rounds[0].Feedback, 3)
+	assert.Equal(t, models.InterviewVerdictPassed, rounds[0].Verdict)
+	assert.Nil(t, rounds[1].RoundID)
+	assert.Equal(t, models.InterviewVerdictPending, rounds[1].Verdict)
+	assert.Equal(t, 4, rounds[2].Feedback[0].InterviewID)
+	assert.Equal(t, models.InterviewVerdictFailed, rounds[2].Verdict)
+}
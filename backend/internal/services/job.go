@@ -37,6 +37,15 @@ func CreateJob(ctx context.Context, req *models.Job) error {
         return err
     }
 
+    if req.ResubmissionPolicy == "" {
+        req.ResubmissionPolicy = models.ResubmissionPolicyReject
+    }
+
+    // Store skills under their canonical names of the company's skills taxonomy
+    if err := normalizeJobSkills(ctx, req); err != nil {
+        return err
+    }
+
     query := `INSERT INTO jobs (
         job_id, 
         user_id, 
@@ -44,8 +53,12 @@ func CreateJob(ctx context.Context, req *models.Job) error {
         job_description, 
         job_status, 
         skills_required, 
-        attributes
-    ) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+        attributes,
+        resubmission_policy,
+        resubmission_window_days,
+        skills_nice_to_have,
+        min_years_experience
+    ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
     _, err = db.ExecContext(ctx, query, 
         req.JobID, 
         userID, 
@@ -53,11 +66,26 @@ func CreateJob(ctx context.Context, req *models.Job) error {
         req.JobDescription, 
         req.JobStatus, 
         pq.Array(req.SkillsRequired), 
-        attributesJSON)
+        attributesJSON,
+        req.ResubmissionPolicy,
+        req.ResubmissionWindowDays,
+        pq.Array(req.SkillsNiceToHave),
+        req.MinYearsExperience)
 
     return err
 }
 
+// normalizeJobSkills normalizes the required and nice-to-have skills of a job against the company's skills taxonomy
+func normalizeJobSkills(ctx context.Context, req *models.Job) error {
+    taxonomy, err := getUserSkillTaxonomy(ctx, ctx.Value("userID"))
+    if err != nil {
+        return err
+    }
+    req.SkillsRequired = taxonomy.NormalizeAll(req.SkillsRequired)
+    req.SkillsNiceToHave = taxonomy.NormalizeAll(req.SkillsNiceToHave)
+    return nil
+}
+
 func UpdateJob(ctx context.Context, req *models.Job) error {
     db := database.GetDB()
     userID := ctx.Value("userID")
@@ -78,13 +106,22 @@ func UpdateJob(ctx context.Context, req *models.Job) error {
         return err
     }
 
+    if err := normalizeJobSkills(ctx, req); err != nil {
+        return err
+    }
+
+    // Clients that do not send a resubmission policy keep the stored policy and window
     query := `UPDATE jobs SET 
         job_title = $1,
         job_description = $2,
         job_status = $3,
         skills_required = $4,
-        attributes = $5
-        WHERE job_id = $6 AND user_id = $7`
+        attributes = $5,
+        resubmission_policy = COALESCE(NULLIF($6, ''), resubmission_policy),
+        resubmission_window_days = CASE WHEN $6 = '' THEN resubmission_window_days ELSE $7 END,
+        skills_nice_to_have = $8,
+        min_years_experience = $9
+        WHERE job_id = $10 AND user_id = $11`
 
     _, err = db.ExecContext(ctx, query,
         req.JobTitle,
@@ -92,6 +129,10 @@ func UpdateJob(ctx context.Context, req *models.Job) error {
         req.JobStatus,
         pq.Array(req.SkillsRequired),
         attributesJSON,
+        req.ResubmissionPolicy,
+        req.ResubmissionWindowDays,
+        pq.Array(req.SkillsNiceToHave),
+        req.MinYearsExperience,
         req.JobID,
         userID)
 
@@ -103,10 +144,10 @@ func GetJobById(ctx context.Context, jobID string) (*models.Job, error) {
     userID := ctx.Value("userID")
 
     var job models.Job
-    var skillsRequired pq.StringArray
+    var skillsRequired, skillsNiceToHave pq.StringArray
     var attributesJSON []byte
 
-    query := `SELECT job_id, job_title, job_description, job_status, skills_required, attributes 
+    query := `SELECT job_id, job_title, job_description, job_status, skills_required, attributes, resubmission_policy, resubmission_window_days, skills_nice_to_have, min_years_experience 
              FROM jobs WHERE job_id = $1 AND user_id = $2`
     
     err := db.QueryRowContext(ctx, query, jobID, userID).Scan(
@@ -116,12 +157,17 @@ func GetJobById(ctx context.Context, jobID string) (*models.Job, error) {
         &job.JobStatus,
         &skillsRequired,
         &attributesJSON,
+        &job.ResubmissionPolicy,
+        &job.ResubmissionWindowDays,
+        &skillsNiceToHave,
+        &job.MinYearsExperience,
     )
     if err != nil {
         return nil, err
     }
 
     job.SkillsRequired = []string(skillsRequired)
+    job.SkillsNiceToHave = []string(skillsNiceToHave)
 
     // Unmarshal attributes JSON
     if err := json.Unmarshal(attributesJSON, &job.Attributes); err != nil {
@@ -136,7 +182,7 @@ func GetJobsByTitle(ctx context.Context, jobTitle string) ([]*models.Job, error)
     userID := ctx.Value("userID")
 
     var jobs []*models.Job
-    query := `SELECT job_id, job_title, job_description, job_status, skills_required, attributes 
+    query := `SELECT job_id, job_title, job_description, job_status, skills_required, attributes, resubmission_policy, resubmission_window_days, skills_nice_to_have, min_years_experience 
              FROM jobs WHERE job_title ILIKE $1 AND user_id = $2`
     
     rows, err := db.QueryContext(ctx, query, "%"+jobTitle+"%", userID)
@@ -147,7 +193,7 @@ func GetJobsByTitle(ctx context.Context, jobTitle string) ([]*models.Job, error)
 
     for rows.Next() {
         var job models.Job
-        var skillsRequired pq.StringArray
+        var skillsRequired, skillsNiceToHave pq.StringArray
         var attributesJSON []byte
 
         err := rows.Scan(
@@ -157,12 +203,17 @@ func GetJobsByTitle(ctx context.Context, jobTitle string) ([]*models.Job, error)
             &job.JobStatus,
             &skillsRequired,
             &attributesJSON,
+            &job.ResubmissionPolicy,
+            &job.ResubmissionWindowDays,
+            &skillsNiceToHave,
+            &job.MinYearsExperience,
         )
         if err != nil {
             return nil, err
         }
 
         job.SkillsRequired = []string(skillsRequired)
+        job.SkillsNiceToHave = []string(skillsNiceToHave)
         if err := json.Unmarshal(attributesJSON, &job.Attributes); err != nil {
             return nil, err
         }
@@ -178,7 +229,7 @@ func GetJobsByStatus(ctx context.Context, status string) ([]*models.Job, error)
     userID := ctx.Value("userID")
 
     var jobs []*models.Job
-    query := `SELECT job_id, job_title, job_description, job_status, skills_required, attributes 
+    query := `SELECT job_id, job_title, job_description, job_status, skills_required, attributes, resubmission_policy, resubmission_window_days, skills_nice_to_have, min_years_experience 
              FROM jobs WHERE job_status = $1 AND user_id = $2`
     
     rows, err := db.QueryContext(ctx, query, status, userID)
@@ -189,7 +240,7 @@ func GetJobsByStatus(ctx context.Context, status string) ([]*models.Job, error)
 
     for rows.Next() {
         var job models.Job
-        var skillsRequired pq.StringArray
+        var skillsRequired, skillsNiceToHave pq.StringArray
         var attributesJSON []byte
 
         err := rows.Scan(
@@ -199,12 +250,17 @@ func GetJobsByStatus(ctx context.Context, status string) ([]*models.Job, error)
             &job.JobStatus,
             &skillsRequired,
             &attributesJSON,
+            &job.ResubmissionPolicy,
+            &job.ResubmissionWindowDays,
+            &skillsNiceToHave,
+            &job.MinYearsExperience,
         )
         if err != nil {
             return nil, err
         }
 
         job.SkillsRequired = []string(skillsRequired)
+        job.SkillsNiceToHave = []string(skillsNiceToHave)
         if err := json.Unmarshal(attributesJSON, &job.Attributes); err != nil {
             return nil, err
         }
@@ -220,7 +276,7 @@ func GetJobsByUserId(ctx context.Context) ([]*models.Job, error) {
     userID := ctx.Value("userID")
 
     var jobs []*models.Job
-    query := `SELECT job_id, job_title, job_description, job_status, skills_required, attributes 
+    query := `SELECT job_id, job_title, job_description, job_status, skills_required, attributes, resubmission_policy, resubmission_window_days, skills_nice_to_have, min_years_experience 
              FROM jobs WHERE user_id = $1`
     
     rows, err := db.QueryContext(ctx, query, userID)
@@ -231,7 +287,7 @@ func GetJobsByUserId(ctx context.Context) ([]*models.Job, error) {
 
     for rows.Next() {
         var job models.Job
-        var skillsRequired pq.StringArray
+        var skillsRequired, skillsNiceToHave pq.StringArray
         var attributesJSON []byte
 
         err := rows.Scan(
@@ -241,12 +297,17 @@ func GetJobsByUserId(ctx context.Context) ([]*models.Job, error) {
             &job.JobStatus,
             &skillsRequired,
             &attributesJSON,
+            &job.ResubmissionPolicy,
+            &job.ResubmissionWindowDays,
+            &skillsNiceToHave,
+            &job.MinYearsExperience,
         )
         if err != nil {
             return nil, err
         }
 
         job.SkillsRequired = []string(skillsRequired)
+        job.SkillsNiceToHave = []string(skillsNiceToHave)
         if err := json.Unmarshal(attributesJSON, &job.Attributes); err != nil {
             return nil, err
         }
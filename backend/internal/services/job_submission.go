@@ -1,12 +1,13 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"mime/multipart"
 	"time"
 
-	"backend/internal/config"
 	"backend/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -23,7 +24,9 @@ func NewFormSubmissionService(db *sqlx.DB) *FormSubmissionService {
 	return &FormSubmissionService{db: db}
 }
 
-func (s *FormSubmissionService) HandleFormSubmission(c *gin.Context) (*models.JobSubmission, error) {
+// HandleFormSubmission validates, stores and saves an application. Either the submission is saved with
+// all its files or nothing is kept: files stored before saving fails are removed again.
+func (s *FormSubmissionService) HandleFormSubmission(c *gin.Context) (_ *models.JobSubmission, err error) {
 	// Check table structure first (for debugging)
 	if err := s.checkJobSubmissionsTable(); err != nil {
 		log.Printf("Warning: Failed to check job_submissions table structure: %v", err)
@@ -71,30 +74,117 @@ func (s *FormSubmissionService) HandleFormSubmission(c *gin.Context) (*models.Jo
 		return nil, fmt.Errorf("skills are required")
 	}
 
-	// Check if we're in test mode
-	testMode := c.GetHeader("X-Test-Mode") == "true" || config.GetConfig().TestMode
+	// Validate additional files against the `file` fields declared in the form template
+	templateFields, err := getFormTemplateFields(c.Request.Context(), submission.FormUUID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid application form: %v", err)
+	}
+	multipartForm, _ := c.MultipartForm()
+	pendingAttachments, err := collectAttachments(multipartForm, templateFileFields(templateFields))
+	if err != nil {
+		return nil, err
+	}
 
-	// Upload resume to S3
-	var resumeURL string
-	var err error
+	// Normalize the candidate's skills against the company's skills taxonomy
+	taxonomy, err := getFormSkillTaxonomy(c.Request.Context(), submission.FormUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load skills taxonomy: %v", err)
+	}
+	skills = taxonomy.NormalizeAll(skills)
 
-	if !testMode {
-		if submission.Resume == nil {
-			return nil, fmt.Errorf("resume file is required")
+	// New applications start in the first stage of the job's pipeline
+	initialStage, err := getInitialStage(c.Request.Context(), submission.FormUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pipeline stages: %v", err)
+	}
+
+	// Detect repeated applications before anything is uploaded
+	existingSubmission, err := findExistingSubmission(c.Request.Context(), submission.JobID, submission.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing applications: %v", err)
+	}
+	if existingSubmission != nil {
+		policy, err := getResubmissionPolicy(c.Request.Context(), submission.FormUUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check resubmission policy: %v", err)
 		}
-		resumeURL, err = UploadResumeToS3(submission.Resume, 0, submission.Username)
+		if err := checkResubmissionAllowed(existingSubmission, policy, submission.Token, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check the uploads before anything is stored, infected files are quarantined
+	if submission.Resume == nil {
+		return nil, fmt.Errorf("resume file is required")
+	}
+	if err := validateResume(submission.Resume); err != nil {
+		return nil, err
+	}
+	companyName, err := getFormCompanyName(c.Request.Context(), submission.FormUUID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid application form: %v", err)
+	}
+	uploads := []*multipart.FileHeader{submission.Resume}
+	for _, pending := range pendingAttachments {
+		uploads = append(uploads, pending.File)
+	}
+	if err := scanUploads(c.Request.Context(), companyName, submission.JobID, uploads...); err != nil {
+		return nil, err
+	}
+
+	// Remove the stored files again when the submission is not saved
+	stored := &storedFiles{}
+	defer func() {
 		if err != nil {
-			log.Printf("Failed to upload resume: %v", err)
-			return nil, fmt.Errorf("failed to upload resume: %v", err)
+			stored.remove(c.Request.Context())
 		}
-	} else {
-		// In test mode, use a mock URL
-		resumeURL = fmt.Sprintf("https://test-bucket.s3.amazonaws.com/resumes/%s_test_user.pdf", submission.Username)
-		log.Println("Test mode: Using mock resume URL:", resumeURL)
+	}()
+
+	// Store the resume under a key of its own in the folder of the company's job
+	resumeKey, err := UploadResume(c.Request.Context(), submission.Resume, companyName, submission.JobID)
+	if err != nil {
+		log.Printf("Failed to upload resume: %v", err)
+		return nil, fmt.Errorf("failed to upload resume: %v", err)
 	}
+	stored.add(resumeKey)
 
-	// Calculate ATS score
-	atsScore := calculateATSScore(skills)
+	// Read the resume for scoring and search
+	resumeText, resumeData := readResume(submission.Resume)
+	if resumeData != nil {
+		resumeData.Skills = taxonomy.NormalizeAll(resumeData.Skills)
+	}
+
+	// Store the additional attachments
+	attachments := []models.SubmissionAttachment{}
+	for _, pending := range pendingAttachments {
+		fileKey, err := UploadAttachment(c.Request.Context(), pending.File, companyName, submission.JobID)
+		if err != nil {
+			log.Printf("Failed to upload attachment %s: %v", pending.FieldName, err)
+			return nil, fmt.Errorf("failed to upload %s: %v", pending.FieldName, err)
+		}
+		stored.add(fileKey)
+
+		attachments = append(attachments, models.SubmissionAttachment{
+			FieldName:   pending.FieldName,
+			FileName:    pending.File.Filename,
+			ContentType: pending.File.Header.Get("Content-Type"),
+			SizeBytes:   pending.File.Size,
+			FileKey:     fileKey,
+		})
+	}
+
+	// Score the application against the job
+	atsBreakdown, err := scoreApplication(c.Request.Context(), submission.FormUUID, ATSScoreInput{
+		CandidateSkills: skills,
+		FormData:        formDataMap,
+		ResumeText:      resumeText,
+		ParsedResume:    resumeData,
+		Taxonomy:        taxonomy,
+	})
+	if err != nil {
+		log.Printf("Failed to calculate ATS score: %v", err)
+		return nil, fmt.Errorf("failed to calculate ats score: %v", err)
+	}
 
 	// Create job submission
 	jobSubmission := &models.JobSubmission{
@@ -104,24 +194,75 @@ func (s *FormSubmissionService) HandleFormSubmission(c *gin.Context) (*models.Jo
 		FormData:  []byte(submission.FormData),
 		FormUUID:  submission.FormUUID,
 		Skills:    pq.StringArray(skills),
-		ResumeURL: resumeURL,
-		ATSScore:  int(atsScore),
-		Status:    "applied",
+		ResumeKey: resumeKey,
+		ResumeFileName: submission.Resume.Filename,
+		ResumeText: resumeText,
+		ResumeData: resumeData,
+		ATSScore:  atsBreakdown.Score,
+		Status:    initialStage,
+		Revision:  1,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+
+		ATSBreakdown: atsBreakdown,
+
+		Attachments: attachments,
+	}
+
+	// Save the submission with its candidate link, attachments and initial stage in one transaction
+	tx, err := s.db.BeginTxx(c.Request.Context(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save submission: %v", err)
 	}
+	defer tx.Rollback()
 
-	// Insert into database
-	err = s.insertJobSubmission(jobSubmission)
+	// Link the application to the company's candidate record
+	candidateID, candidateMatch, err := linkCandidate(c.Request.Context(), tx, companyName, submission.Email, submission.Username, applicationPhone(formDataMap, resumeData))
 	if err != nil {
+		log.Printf("Failed to link candidate: %v", err)
+		return nil, fmt.Errorf("failed to link candidate: %v", err)
+	}
+	jobSubmission.CandidateID = &candidateID
+	jobSubmission.CandidateMatch = candidateMatch
+
+	if existingSubmission != nil {
+		// The candidate is updating an earlier application, keep the previous version in the history
+		if err := replaceJobSubmission(c.Request.Context(), tx, existingSubmission, jobSubmission); err != nil {
+			log.Printf("Failed to update job submission: %v", err)
+			return nil, fmt.Errorf("failed to save submission: %v", err)
+		}
+	} else if err := s.insertJobSubmission(c.Request.Context(), tx, jobSubmission); err != nil {
 		log.Printf("Failed to insert job submission: %v", err)
+		// Another request for the same email won the race
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateApplication
+		}
+		return nil, fmt.Errorf("failed to save submission: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Failed to commit job submission: %v", err)
 		return nil, fmt.Errorf("failed to save submission: %v", err)
 	}
 
+	if existingSubmission != nil {
+		sendApplicationStatusLinkAsync(jobSubmission)
+		return jobSubmission, nil
+	}
+
+	// Record the completed application for form analytics
+	if err := RecordFormEvent(c.Request.Context(), submission.FormUUID, models.FormEventSubmit, submission.FormTracking); err != nil {
+		log.Printf("Failed to record form submit event: %v", err)
+	}
+
+	// Email the candidate a magic link to follow their application
+	sendApplicationStatusLinkAsync(jobSubmission)
+
 	return jobSubmission, nil
 }
 
-func (s *FormSubmissionService) insertJobSubmission(submission *models.JobSubmission) error {
+// insertJobSubmission saves a new application with its attachments, initial stage and search document
+func (s *FormSubmissionService) insertJobSubmission(ctx context.Context, tx *sqlx.Tx, submission *models.JobSubmission) error {
 	// First let's try to understand the structure of the job_submissions table
 	tableInfo, err := s.db.Query(`
 		SELECT column_name, data_type, is_nullable 
@@ -146,8 +287,8 @@ func (s *FormSubmissionService) insertJobSubmission(submission *models.JobSubmis
 
 	insertQuery := `
 		INSERT INTO job_submissions (
-			form_uuid, job_id, username, email, form_data, skills, resume_url, ats_score, status, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			form_uuid, job_id, username, email, form_data, skills, resume_key, resume_file_name, resume_text, resume_data, ats_score, ats_breakdown, status, created_at, updated_at, candidate_id, candidate_match
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
 		RETURNING id`
 
 	args := []interface{}{
@@ -157,33 +298,37 @@ func (s *FormSubmissionService) insertJobSubmission(submission *models.JobSubmis
 		submission.Email,
 		submission.FormData,
 		submission.Skills,
-		submission.ResumeURL,
+		submission.ResumeKey,
+		submission.ResumeFileName,
+		submission.ResumeText,
+		submission.ResumeData,
 		submission.ATSScore,
+		submission.ATSBreakdown,
 		submission.Status,
 		submission.CreatedAt,
 		submission.UpdatedAt,
+		submission.CandidateID,
+		submission.CandidateMatch,
 	}
 
 	log.Printf("Using query: %s", insertQuery)
-	err = s.db.QueryRow(insertQuery, args...).Scan(&submission.ID)
+	err = tx.QueryRowContext(ctx, insertQuery, args...).Scan(&submission.ID)
 
 	if err != nil {
-		return fmt.Errorf("failed to insert job submission: %v", err)
+		return fmt.Errorf("failed to insert job submission: %w", err)
 	}
 
-	return nil
-}
-
-func calculateATSScore(skills []string) int {
-	// Simple scoring logic based on number of skills
-	baseScore := 70
-	skillPoints := len(skills) * 5
-
-	score := baseScore + skillPoints
-	if score > 100 {
-		score = 100
+	if err := insertSubmissionAttachments(ctx, tx, submission); err != nil {
+		return fmt.Errorf("failed to save attachments: %w", err)
 	}
-	return score
+	if err := recordStageChange(ctx, tx, &models.StageChange{JobSubmissionID: submission.ID, ToStage: submission.Status}); err != nil {
+		return fmt.Errorf("failed to record initial stage: %w", err)
+	}
+	// Index the application for the candidate search
+	if err := refreshSubmissionSearchVector(ctx, tx, submission.ID); err != nil {
+		return fmt.Errorf("failed to index job submission for search: %w", err)
+	}
+	return nil
 }
 
 func (s *FormSubmissionService) checkJobSubmissionsTable() error {
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"backend/internal/config"
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+// EmailMessage is a plain text email sent to a candidate or user
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(msg EmailMessage) error
+}
+
+// smtpMailer sends emails through the configured SMTP server
+type smtpMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func (m *smtpMailer) Send(msg EmailMessage) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	if err := smtp.SendMail(m.host+":"+m.port, auth, m.from, []string{msg.To}, m.message(msg)); err != nil {
+		return fmt.Errorf("smtp send error: %w", err)
+	}
+	return nil
+}
+
+// message builds the email with its headers. The subject is encoded as an RFC 2047 word when it is not plain ASCII.
+func (m *smtpMailer) message(msg EmailMessage) []byte {
+	return []byte(strings.Join([]string{
+		"From: " + m.from,
+		"To: " + headerValue(msg.To),
+		"Subject: " + mime.QEncoding.Encode("utf-8", headerValue(msg.Subject)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+		"",
+		msg.Body,
+	}, "\r\n"))
+}
+
+// headerValue removes line breaks, so values such as job titles cannot add headers to an email
+func headerValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// logMailer only logs the emails, used for development when no SMTP server is configured.
+// Bodies are not logged, they may contain magic links.
+type logMailer struct{}
+
+func (m *logMailer) Send(msg EmailMessage) error {
+	log.Printf("📧 Email to %s | Subject: %s | %d bytes body not logged", msg.To, headerValue(msg.Subject), len(msg.Body))
+	return nil
+}
+
+var mailer Mailer
+
+// GetMailer returns the mailer for the loaded configuration
+func GetMailer() Mailer {
+	if mailer != nil {
+		return mailer
+	}
+
+	cfg := config.GetConfig().Mail
+	if cfg.Host == "" || config.GetConfig().TestMode {
+		return &logMailer{}
+	}
+	return &smtpMailer{
+		host:     cfg.Host,
+		port:     cfg.Port,
+		username: cfg.Username,
+		password: cfg.Password,
+		from:     cfg.From,
+	}
+}
+
+// SetMailer overrides the mailer, e.g. with a fake in tests
+func SetMailer(m Mailer) {
+	mailer = m
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPMessageHeaders(t *testing.T) {
+	m := &smtpMailer{from: "jobs@example.com"}
+
+	// A job title cannot add headers
+	message := string(m.message(EmailMessage{
+		To:      "jane@example.com",
+		Subject: "Your application for Engineer\r\nBcc: attacker@example.com",
+		Body:    "Hi Jane",
+	}))
+	headers, body, _ := strings.Cut(message, "\r\n\r\n")
+	assert.Equal(t, []string{
+		"From: jobs@example.com",
+		"To: jane@example.com",
+		"Subject: Your application for Engineer Bcc: attacker@example.com",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+	}, strings.Split(headers, "\r\n"))
+	assert.Equal(t, "Hi Jane", body)
+
+	// Non-ASCII subjects are encoded
+	message = string(m.message(EmailMessage{To: "jane@example.com", Subject: "Your application for Développeur"}))
+	assert.Contains(t, message, "Subject: =?utf-8?q?Your_application_for_D=C3=A9veloppeur?=\r\n")
+}
+
+func TestLogMailerHidesBody(t *testing.T) {
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	err := (&logMailer{}).Send(EmailMessage{To: "jane@example.com", Subject: "Status link", Body: "https://example.com/status?token=secret"})
+	assert.NoError(t, err)
+	assert.Contains(t, output.String(), "jane@example.com")
+	assert.NotContains(t, output.String(), "secret")
+}
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNoteNotFound         = errors.New("note not found")
+	ErrNoteNotAuthor        = errors.New("only the author can edit or delete a note")
+	ErrNoteParentNotFound   = errors.New("the note you reply to does not belong to this submission")
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// mentionPattern matches @username mentions, an email address is not a mention
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w][\w.\-]*)`)
+
+// parseMentions returns the lowercase usernames mentioned in a note
+func parseMentions(body string) []string {
+	mentions := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// A mention at the end of a sentence does not include the punctuation
+		username := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if username != "" {
+			mentions = appendUnique(mentions, username)
+		}
+	}
+	return mentions
+}
+
+// noteSubmission is the submission a note is attached to
+type noteSubmission struct {
+	CandidateName string `db:"candidate_name"`
+	JobTitle      string `db:"job_title"`
+}
+
+// getCompanySubmission returns a submission to a job of the user's company. Notes and tags are
+// shared with teammates, so unlike status changes they are not limited to the job owner.
+func getCompanySubmission(ctx context.Context, q sqlx.QueryerContext, userID int, submissionID int) (*noteSubmission, error) {
+	var submission noteSubmission
+	err := sqlx.GetContext(ctx, q, &submission, `
+		SELECT js.username AS candidate_name, j.job_title
+		FROM job_submissions js
+		JOIN application_form af ON js.form_uuid = af.form_uuid
+		JOIN jobs j ON af.job_id = j.id
+		JOIN users owner ON j.user_id = owner.id
+		WHERE js.id = $1 AND owner.company_name = (SELECT company_name FROM users WHERE id = $2)`,
+		submissionID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	return &submission, nil
+}
+
+type mentionedUser struct {
+	ID       int    `db:"id"`
+	Username string `db:"username"`
+}
+
+// resolveMentions returns the mentioned users of the author's company, other usernames are ignored
+func resolveMentions(ctx context.Context, tx *sqlx.Tx, authorID int, usernames []string) ([]mentionedUser, error) {
+	users := []mentionedUser{}
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	err := tx.SelectContext(ctx, &users, `
+		SELECT id, username FROM users
+		WHERE LOWER(username) = ANY($1) AND company_name = (SELECT company_name FROM users WHERE id = $2)
+		ORDER BY username`, pq.Array(usernames), authorID)
+	return users, err
+}
+
+// notifyMentions records the mentions of a note and notifies the mentioned users who were not mentioned before
+func notifyMentions(ctx context.Context, tx *sqlx.Tx, authorID int, noteID int, submissionID int, submission *noteSubmission, body string) ([]string, error) {
+	users, err := resolveMentions(ctx, tx, authorID, parseMentions(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var authorName string
+	if err := tx.GetContext(ctx, &authorName, "SELECT username FROM users WHERE id = $1", authorID); err != nil {
+		return nil, err
+	}
+
+	mentioned := []string{}
+	userIDs := []int64{}
+	for _, user := range users {
+		mentioned = append(mentioned, user.Username)
+		userIDs = append(userIDs, int64(user.ID))
+
+		var isNew bool
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO note_mentions (note_id, user_id) VALUES ($1, $2)
+			ON CONFLICT (note_id, user_id) DO NOTHING
+			RETURNING TRUE`, noteID, user.ID).Scan(&isNew)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if user.ID == authorID {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO notifications (user_id, type, actor_id, job_submission_id, note_id, message)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			user.ID, models.NotificationTypeMention, authorID, submissionID, noteID,
+			fmt.Sprintf("%s mentioned you in a note on %s's application for %s", authorName, submission.CandidateName, submission.JobTitle))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Users no longer mentioned after an edit are removed from the note
+	_, err = tx.ExecContext(ctx, "DELETE FROM note_mentions WHERE note_id = $1 AND NOT (user_id = ANY($2))", noteID, pq.Array(userIDs))
+	return mentioned, err
+}
+
+// CreateSubmissionNote adds a note or a reply to a submission of the user's company
+func CreateSubmissionNote(ctx context.Context, submissionID int, req models.CreateNoteRequest) (*models.SubmissionNote, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	submission, err := getCompanySubmission(ctx, tx, userID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		var parentExists bool
+		err := tx.GetContext(ctx, &parentExists, `
+			SELECT EXISTS(SELECT 1 FROM submission_notes WHERE id = $1 AND job_submission_id = $2)`,
+			*req.ParentID, submissionID)
+		if err != nil {
+			return nil, err
+		}
+		if !parentExists {
+			return nil, ErrNoteParentNotFound
+		}
+	}
+
+	note := &models.SubmissionNote{
+		JobSubmissionID: submissionID,
+		ParentID:        req.ParentID,
+		AuthorID:        &userID,
+		Body:            strings.TrimSpace(req.Body),
+		Replies:         []models.SubmissionNote{},
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO submission_notes (job_submission_id, parent_id, author_id, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at, (SELECT username FROM users WHERE id = $3)`,
+		submissionID, req.ParentID, userID, note.Body).Scan(&note.ID, &note.CreatedAt, &note.UpdatedAt, &note.AuthorName)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Mentions, err = notifyMentions(ctx, tx, userID, note.ID, submissionID, submission, note.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// lockAuthoredNote locks a note of a submission for a change by its author
+func lockAuthoredNote(ctx context.Context, tx *sqlx.Tx, userID int, submissionID int, noteID int) (*models.SubmissionNote, error) {
+	var note models.SubmissionNote
+	err := tx.GetContext(ctx, &note, `
+		SELECT id, job_submission_id, parent_id, author_id, body, deleted, created_at, updated_at
+		FROM submission_notes
+		WHERE id = $1 AND job_submission_id = $2 AND NOT deleted
+		FOR UPDATE`, noteID, submissionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNoteNotFound
+		}
+		return nil, err
+	}
+	if note.AuthorID == nil || *note.AuthorID != userID {
+		return nil, ErrNoteNotAuthor
+	}
+	return &note, nil
+}
+
+// UpdateSubmissionNote changes the text of a note, only by its author. Newly mentioned teammates are notified.
+func UpdateSubmissionNote(ctx context.Context, submissionID int, noteID int, req models.UpdateNoteRequest) (*models.SubmissionNote, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	submission, err := getCompanySubmission(ctx, tx, userID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	note, err := lockAuthoredNote(ctx, tx, userID, submissionID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Body = strings.TrimSpace(req.Body)
+	err = tx.QueryRowContext(ctx, `
+		UPDATE submission_notes SET body = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING updated_at, (SELECT username FROM users WHERE id = $3)`,
+		note.Body, noteID, userID).Scan(&note.UpdatedAt, &note.AuthorName)
+	if err != nil {
+		return nil, err
+	}
+
+	note.Mentions, err = notifyMentions(ctx, tx, userID, note.ID, submissionID, submission, note.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	note.Replies = []models.SubmissionNote{}
+	return note, nil
+}
+
+// DeleteSubmissionNote deletes a note, only by its author. Replies of teammates are kept under a placeholder.
+func DeleteSubmissionNote(ctx context.Context, submissionID int, noteID int) error {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := getCompanySubmission(ctx, tx, userID, submissionID); err != nil {
+		return err
+	}
+	if _, err := lockAuthoredNote(ctx, tx, userID, submissionID, noteID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE submission_notes SET deleted = TRUE, body = '', updated_at = NOW() WHERE id = $1", noteID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM note_mentions WHERE note_id = $1", noteID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM notifications WHERE note_id = $1", noteID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// buildNoteThreads nests replies under the notes they answer, oldest first.
+// Deleted notes are only kept as placeholders for their remaining replies.
+func buildNoteThreads(notes []models.SubmissionNote) []models.SubmissionNote {
+	children := map[int][]models.SubmissionNote{}
+	roots := []models.SubmissionNote{}
+	for _, note := range notes {
+		if note.ParentID == nil {
+			roots = append(roots, note)
+		} else {
+			children[*note.ParentID] = append(children[*note.ParentID], note)
+		}
+	}
+
+	var attach func(list []models.SubmissionNote) []models.SubmissionNote
+	attach = func(list []models.SubmissionNote) []models.SubmissionNote {
+		threads := []models.SubmissionNote{}
+		for _, note := range list {
+			note.Replies = attach(children[note.ID])
+			if note.Deleted && len(note.Replies) == 0 {
+				continue
+			}
+			threads = append(threads, note)
+		}
+		return threads
+	}
+	return attach(roots)
+}
+
+// ListSubmissionNotes returns the note threads of a submission of the user's company
+func ListSubmissionNotes(ctx context.Context, submissionID int) ([]models.SubmissionNote, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	if _, err := getCompanySubmission(ctx, db, userID, submissionID); err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		models.SubmissionNote
+		Mentions pq.StringArray `db:"mentions"`
+	}
+	err := db.SelectContext(ctx, &rows, `
+		SELECT n.id, n.job_submission_id, n.parent_id, n.author_id, COALESCE(u.username, '') AS author_name,
+			n.body, n.deleted, n.created_at, n.updated_at,
+			ARRAY(SELECT mu.username FROM note_mentions m JOIN users mu ON m.user_id = mu.id
+				WHERE m.note_id = n.id ORDER BY mu.username) AS mentions
+		FROM submission_notes n
+		LEFT JOIN users u ON n.author_id = u.id
+		WHERE n.job_submission_id = $1
+		ORDER BY n.created_at, n.id`, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]models.SubmissionNote, len(rows))
+	for i, row := range rows {
+		notes[i] = row.SubmissionNote
+		notes[i].Mentions = []string(row.Mentions)
+	}
+	return buildNoteThreads(notes), nil
+}
+
+// GetSubmissionTags returns the tags of a submission of the user's company
+func GetSubmissionTags(ctx context.Context, submissionID int) ([]string, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	if _, err := getCompanySubmission(ctx, db, userID, submissionID); err != nil {
+		return nil, err
+	}
+	tags, err := GetTagsForSubmissions(ctx, []int{submissionID})
+	if err != nil {
+		return nil, err
+	}
+	if tags[submissionID] == nil {
+		return []string{}, nil
+	}
+	return tags[submissionID], nil
+}
+
+// AddSubmissionTags adds free-form tags to a submission of the user's company and returns all its tags
+func AddSubmissionTags(ctx context.Context, submissionID int, tags []string) ([]string, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	tags = NormalizeTags(tags)
+	if len(tags) == 0 {
+		return nil, ErrBulkTagsRequired
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := getCompanySubmission(ctx, tx, userID, submissionID); err != nil {
+		return nil, err
+	}
+	if err := addSubmissionTags(ctx, tx, userID, submissionID, tags); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return GetSubmissionTags(ctx, submissionID)
+}
+
+// RemoveSubmissionTag removes a tag from a submission of the user's company
+func RemoveSubmissionTag(ctx context.Context, submissionID int, tag string) error {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	if _, err := getCompanySubmission(ctx, db, userID, submissionID); err != nil {
+		return err
+	}
+	_, err := db.ExecContext(ctx, "DELETE FROM submission_tags WHERE job_submission_id = $1 AND tag = $2",
+		submissionID, strings.ToLower(cleanSkillName(tag)))
+	return err
+}
+
+// GetTagsForSubmissions returns the tags of the given submissions, keyed by submission id
+func GetTagsForSubmissions(ctx context.Context, submissionIDs []int) (map[int][]string, error) {
+	db := database.GetDB()
+	tags := map[int][]string{}
+
+	if len(submissionIDs) == 0 {
+		return tags, nil
+	}
+
+	var rows []struct {
+		JobSubmissionID int    `db:"job_submission_id"`
+		Tag             string `db:"tag"`
+	}
+	err := db.SelectContext(ctx, &rows, `
+		SELECT job_submission_id, tag FROM submission_tags
+		WHERE job_submission_id = ANY($1)
+		ORDER BY tag`, pq.Array(submissionIDs))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		tags[row.JobSubmissionID] = append(tags[row.JobSubmissionID], row.Tag)
+	}
+	return tags, nil
+}
+
+// ListNotifications returns the notifications of the current user, newest first
+func ListNotifications(ctx context.Context, unreadOnly bool) ([]models.Notification, error) {
+	db := database.GetDB()
+
+	notifications := []models.Notification{}
+	err := db.SelectContext(ctx, &notifications, `
+		SELECT n.id, n.type, n.actor_id, COALESCE(u.username, '') AS actor_name, n.job_submission_id, n.note_id,
+			n.message, n.read_at, n.created_at
+		FROM notifications n
+		LEFT JOIN users u ON n.actor_id = u.id
+		WHERE n.user_id = $1 AND (NOT $2 OR n.read_at IS NULL)
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT 200`, ctx.Value("userID"), unreadOnly)
+	return notifications, err
+}
+
+// MarkNotificationRead marks a notification of the current user as read
+func MarkNotificationRead(ctx context.Context, notificationID int) error {
+	db := database.GetDB()
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, $1)
+		WHERE id = $2 AND user_id = $3`, time.Now(), notificationID, ctx.Value("userID"))
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllNotificationsRead marks every unread notification of the current user as read
+func MarkAllNotificationsRead(ctx context.Context) error {
+	db := database.GetDB()
+	_, err := db.ExecContext(ctx, "UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL", ctx.Value("userID"))
+	return err
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"backend/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	assert.Equal(t, []string{"alice", "bob.smith"},
+		parseMentions("@Alice great culture fit, ask about visa. cc @bob.smith. Thanks @alice!"))
+	// Email addresses are not mentions
+	assert.Empty(t, parseMentions("Candidate wrote to jobs@example.com"))
+}
+
+func TestBuildNoteThreads(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	threads := buildNoteThreads([]models.SubmissionNote{
+		{ID: 1, Body: "first"},
+		{ID: 2, Deleted: true},
+		{ID: 3, ParentID: intPtr(1), Body: "reply"},
+		{ID: 4, ParentID: intPtr(3), Body: "nested reply"},
+		{ID: 5, Deleted: true},
+		{ID: 6, ParentID: intPtr(2), Body: "reply to deleted"},
+	})
+
+	assert.Len(t, threads, 2)
+	assert.Equal(t, 1, threads[0].ID)
+	assert.Equal(t, 3, threads[0].Replies[0].ID)
+	assert.Equal(t, 4, threads[0].Replies[0].Replies[0].ID)
+	// A deleted note stays as a placeholder while it has replies
+	assert.Equal(t, 2, threads[1].ID)
+	assert.Equal(t, 6, threads[1].Replies[0].ID)
+}
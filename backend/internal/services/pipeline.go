@@ -0,0 +1,430 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrPipelineStageKeyExists   = errors.New("pipeline stage keys must be unique")
+	ErrPipelineStageKeyReserved = errors.New("the withdrawn stage is reserved for candidates withdrawing their application")
+	ErrPipelineStageKeyTooLong  = errors.New("pipeline stage keys must be at most 50 characters, set a shorter key for long stage names")
+	ErrPipelineFirstStage       = errors.New("the first pipeline stage must be an active stage, new applications start there")
+	ErrPipelineTransition       = errors.New("pipeline stage transitions must refer to other stages of the pipeline")
+	ErrPipelineStageInUse       = errors.New("cannot remove a pipeline stage that still has candidates")
+	ErrUnknownStage             = errors.New("stage is not part of the job's pipeline")
+	ErrInvalidStageTransition   = errors.New("the submission cannot be moved from its current stage to this stage")
+	ErrSubmissionAlreadyInStage = errors.New("the submission is already in this stage")
+	ErrSubmissionWithdrawn      = errors.New("the candidate has withdrawn this application")
+)
+
+// defaultPipeline is used by jobs that did not configure their own stages
+var defaultPipeline = []models.PipelineStage{
+	{Key: "applied", Name: "Applied", Type: models.StageTypeActive},
+	{Key: "under_review", Name: "Under review", Type: models.StageTypeActive},
+	{Key: "shortlisted", Name: "Shortlisted", Type: models.StageTypeActive},
+	{Key: "selected", Name: "Selected", Type: models.StageTypeHired},
+	{Key: "rejected", Name: "Rejected", Type: models.StageTypeRejected},
+}
+
+var nonKeyChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// maxStageKeyLength is the length of job_submissions.status and pipeline_stages.stage_key
+const maxStageKeyLength = 50
+
+// stageKey derives a stage key such as phone_screen from a stage name
+func stageKey(name string) string {
+	return strings.Trim(nonKeyChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
+
+func copyStages(stages []models.PipelineStage) []models.PipelineStage {
+	copied := make([]models.PipelineStage, len(stages))
+	for i, stage := range stages {
+		stage.Position = i + 1
+		if stage.Transitions == nil {
+			stage.Transitions = pq.StringArray{}
+		}
+		copied[i] = stage
+	}
+	return copied
+}
+
+// getJobStages returns the stages of a job by its numeric id, or the default pipeline
+func getJobStages(ctx context.Context, q sqlx.QueryerContext, jobDBID int) ([]models.PipelineStage, bool, error) {
+	stages := []models.PipelineStage{}
+	err := sqlx.SelectContext(ctx, q, &stages, `
+		SELECT id, stage_key, name, position, stage_type, transitions
+		FROM pipeline_stages
+		WHERE job_id = $1
+		ORDER BY position`, jobDBID)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(stages) == 0 {
+		return copyStages(defaultPipeline), true, nil
+	}
+	return stages, false, nil
+}
+
+func findStage(stages []models.PipelineStage, key string) *models.PipelineStage {
+	for i := range stages {
+		if stages[i].Key == key {
+			return &stages[i]
+		}
+	}
+	return nil
+}
+
+// getOwnedJobDBID returns the numeric id of a job of the current user
+func getOwnedJobDBID(ctx context.Context, jobID string) (int, error) {
+	db := database.GetDB()
+
+	var jobDBID int
+	err := db.GetContext(ctx, &jobDBID, "SELECT id FROM jobs WHERE job_id = $1 AND user_id = $2", jobID, ctx.Value("userID"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrJobNotFound
+		}
+		return 0, err
+	}
+	return jobDBID, nil
+}
+
+// GetJobPipeline returns the ordered stages of a job of the current user
+func GetJobPipeline(ctx context.Context, jobID string) (*models.Pipeline, error) {
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	stages, isDefault, err := getJobStages(ctx, database.GetDB(), jobDBID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Pipeline{JobID: jobID, IsDefault: isDefault, Stages: stages}, nil
+}
+
+// validatePipeline fills in keys, types and positions and checks the stages are consistent
+func validatePipeline(stages []models.PipelineStage) ([]models.PipelineStage, error) {
+	stages = copyStages(stages)
+
+	keys := map[string]bool{}
+	for i := range stages {
+		stage := &stages[i]
+		stage.Name = strings.TrimSpace(stage.Name)
+		if stage.Key == "" {
+			stage.Key = stage.Name
+		}
+		stage.Key = stageKey(stage.Key)
+		if stage.Type == "" {
+			stage.Type = models.StageTypeActive
+		}
+
+		if stage.Key == "" || keys[stage.Key] {
+			return nil, ErrPipelineStageKeyExists
+		}
+		if len(stage.Key) > maxStageKeyLength {
+			return nil, ErrPipelineStageKeyTooLong
+		}
+		if stage.Key == models.SubmissionStatusWithdrawn {
+			return nil, ErrPipelineStageKeyReserved
+		}
+		keys[stage.Key] = true
+	}
+
+	if stages[0].Type != models.StageTypeActive {
+		return nil, ErrPipelineFirstStage
+	}
+
+	for i := range stages {
+		transitions := pq.StringArray{}
+		for _, key := range stages[i].Transitions {
+			key = stageKey(key)
+			if !keys[key] || key == stages[i].Key {
+				return nil, ErrPipelineTransition
+			}
+			transitions = append(transitions, key)
+		}
+		stages[i].Transitions = transitions
+	}
+
+	return stages, nil
+}
+
+// UpdateJobPipeline replaces the stages of a job of the current user.
+// Stages that still have candidates cannot be removed.
+func UpdateJobPipeline(ctx context.Context, jobID string, req models.UpdatePipelineRequest) (*models.Pipeline, error) {
+	db := database.GetDB()
+
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	stages, err := validatePipeline(req.Stages)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, _, err := getJobStages(ctx, tx, jobDBID)
+	if err != nil {
+		return nil, err
+	}
+
+	var used []string
+	err = tx.SelectContext(ctx, &used, `
+		SELECT DISTINCT js.status
+		FROM job_submissions js
+		JOIN application_form af ON js.form_uuid = af.form_uuid
+		WHERE af.job_id = $1`, jobDBID)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range used {
+		if findStage(current, status) != nil && findStage(stages, status) == nil {
+			return nil, ErrPipelineStageInUse
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM pipeline_stages WHERE job_id = $1", jobDBID); err != nil {
+		return nil, err
+	}
+	for i := range stages {
+		stage := &stages[i]
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO pipeline_stages (job_id, stage_key, name, position, stage_type, transitions)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			jobDBID, stage.Key, stage.Name, stage.Position, stage.Type, stage.Transitions).Scan(&stage.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &models.Pipeline{JobID: jobID, Stages: stages}, nil
+}
+
+// getInitialStage returns the stage new applications to the job of an application form start in
+func getInitialStage(ctx context.Context, formUUID string) (string, error) {
+	db := database.GetDB()
+
+	var jobDBID int
+	err := db.GetContext(ctx, &jobDBID, "SELECT job_id FROM application_form WHERE form_uuid = $1", formUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrFormNotFound
+		}
+		return "", err
+	}
+
+	stages, _, err := getJobStages(ctx, db, jobDBID)
+	if err != nil {
+		return "", err
+	}
+	return stages[0].Key, nil
+}
+
+// getSubmissionStage returns the pipeline stage a submission is in, nil for withdrawn or legacy statuses
+func getSubmissionStage(ctx context.Context, submission *models.JobSubmission) (*models.PipelineStage, error) {
+	db := database.GetDB()
+
+	var jobDBID int
+	err := db.GetContext(ctx, &jobDBID, "SELECT job_id FROM application_form WHERE form_uuid = $1", submission.FormUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	stages, _, err := getJobStages(ctx, db, jobDBID)
+	if err != nil {
+		return nil, err
+	}
+	return findStage(stages, submission.Status), nil
+}
+
+// canMoveToStage checks a transition between two stages of a pipeline
+func canMoveToStage(from *models.PipelineStage, to *models.PipelineStage) bool {
+	// Statuses that are no longer part of the pipeline can move anywhere
+	if from == nil {
+		return true
+	}
+
+	for _, key := range from.Transitions {
+		if key == to.Key {
+			return true
+		}
+	}
+	// Hired and rejected candidates can only be reopened through explicit transitions
+	if from.Type != models.StageTypeActive {
+		return false
+	}
+	if to.Type == models.StageTypeRejected {
+		return true
+	}
+	return len(from.Transitions) == 0 && to.Position > from.Position
+}
+
+// recordStageChange adds an entry to the stage history of a submission
+func recordStageChange(ctx context.Context, q rowQueryer, change *models.StageChange) error {
+	var fromStage *string
+	if change.FromStage != "" {
+		fromStage = &change.FromStage
+	}
+	return q.QueryRowContext(ctx, `
+		INSERT INTO submission_stage_history (job_submission_id, from_stage, to_stage, changed_by, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		change.JobSubmissionID, fromStage, change.ToStage, change.ChangedBy, change.Reason).Scan(&change.ID, &change.CreatedAt)
+}
+
+// ownedSubmission is a submission locked for an update by the owner of its job
+type ownedSubmission struct {
+	ID          int    `db:"id"`
+	Username    string `db:"username"`
+	Email       string `db:"email"`
+	Status      string `db:"status"`
+	JobDBID     int    `db:"job_db_id"`
+	JobTitle    string `db:"job_title"`
+	CompanyName string `db:"company_name"`
+}
+
+// lockOwnedSubmission locks a submission within a transaction, it must belong to a job of the given user
+func lockOwnedSubmission(ctx context.Context, tx *sqlx.Tx, userID int, submissionID int) (*ownedSubmission, error) {
+	var submission ownedSubmission
+	err := tx.GetContext(ctx, &submission, `
+		SELECT js.id, js.username, js.email, js.status, af.job_id AS job_db_id, j.job_title, u.company_name
+		FROM job_submissions js
+		JOIN application_form af ON js.form_uuid = af.form_uuid
+		JOIN jobs j ON af.job_id = j.id
+		JOIN users u ON j.user_id = u.id
+		WHERE js.id = $1 AND j.user_id = $2
+		FOR UPDATE OF js`, submissionID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// changeSubmissionStage validates and records the move of a locked submission to another stage of its pipeline
+func changeSubmissionStage(ctx context.Context, tx *sqlx.Tx, userID int, submission *ownedSubmission, stages []models.PipelineStage, to string, reason string) (*models.StageChange, error) {
+	if submission.Status == models.SubmissionStatusWithdrawn {
+		return nil, ErrSubmissionWithdrawn
+	}
+	if submission.Status == to {
+		return nil, ErrSubmissionAlreadyInStage
+	}
+
+	target := findStage(stages, to)
+	if target == nil {
+		return nil, ErrUnknownStage
+	}
+	if !canMoveToStage(findStage(stages, submission.Status), target) {
+		return nil, ErrInvalidStageTransition
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE job_submissions SET status = $1, updated_at = NOW() WHERE id = $2", to, submission.ID); err != nil {
+		return nil, err
+	}
+
+	change := &models.StageChange{
+		JobSubmissionID: submission.ID,
+		FromStage:       submission.Status,
+		ToStage:         to,
+		ChangedBy:       &userID,
+		Reason:          strings.TrimSpace(reason),
+	}
+	if err := recordStageChange(ctx, tx, change); err != nil {
+		return nil, err
+	}
+	submission.Status = to
+	return change, nil
+}
+
+// moveSubmissionStage moves a submission to another stage of its job's pipeline within a transaction.
+// The submission must belong to a job of the given user.
+func moveSubmissionStage(ctx context.Context, tx *sqlx.Tx, userID int, submissionID int, to string, reason string) (*models.StageChange, error) {
+	submission, err := lockOwnedSubmission(ctx, tx, userID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	stages, _, err := getJobStages(ctx, tx, submission.JobDBID)
+	if err != nil {
+		return nil, err
+	}
+	return changeSubmissionStage(ctx, tx, userID, submission, stages, to, reason)
+}
+
+// MoveSubmission moves a submission of one of the current user's jobs to another pipeline stage
+func MoveSubmission(ctx context.Context, submissionID int, req models.MoveSubmissionRequest) (*models.StageChange, error) {
+	db := database.GetDB()
+
+	userID := ctx.Value("userID").(int)
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	change, err := moveSubmissionStage(ctx, tx, userID, submissionID, req.Status, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// GetSubmissionStageHistory returns the stage changes of a submission of one of the current user's jobs, oldest first
+func GetSubmissionStageHistory(ctx context.Context, submissionID int) ([]models.StageChange, error) {
+	db := database.GetDB()
+
+	var owned bool
+	err := db.GetContext(ctx, &owned, `
+		SELECT EXISTS(
+			SELECT 1 FROM job_submissions js
+			JOIN application_form af ON js.form_uuid = af.form_uuid
+			JOIN jobs j ON af.job_id = j.id
+			WHERE js.id = $1 AND j.user_id = $2)`, submissionID, ctx.Value("userID"))
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, ErrSubmissionNotFound
+	}
+
+	history := []models.StageChange{}
+	err = db.SelectContext(ctx, &history, `
+		SELECT h.id, h.job_submission_id, COALESCE(h.from_stage, '') AS from_stage, h.to_stage, h.changed_by,
+			COALESCE(u.username, '') AS changed_by_name, h.reason, h.created_at
+		FROM submission_stage_history h
+		LEFT JOIN users u ON h.changed_by = u.id
+		WHERE h.job_submission_id = $1
+		ORDER BY h.created_at, h.id`, submissionID)
+	return history, err
+}
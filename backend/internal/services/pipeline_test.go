@@ -0,0 +1,72 @@
+package services
+
+import (
+	"backend/internal/models"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePipeline(t *testing.T) {
+	stages, err := validatePipeline([]models.PipelineStage{
+		{Name: "Phone Screen"},
+		{Name: "On-site", Transitions: pq.StringArray{"Offer", "not_a_fit"}},
+		{Name: "Offer", Type: models.StageTypeHired},
+		{Key: "not_a_fit", Name: "Not a fit", Type: models.StageTypeRejected},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "phone_screen", stages[0].Key)
+	assert.Equal(t, models.StageTypeActive, stages[0].Type)
+	assert.Equal(t, 1, stages[0].Position)
+	assert.Equal(t, "on_site", stages[1].Key)
+	assert.Equal(t, pq.StringArray{"offer", "not_a_fit"}, stages[1].Transitions)
+	assert.Equal(t, 4, stages[3].Position)
+
+	_, err = validatePipeline([]models.PipelineStage{{Name: "Screen"}, {Name: "screen"}})
+	assert.Equal(t, ErrPipelineStageKeyExists, err)
+
+	_, err = validatePipeline([]models.PipelineStage{{Name: "Rejected", Type: models.StageTypeRejected}})
+	assert.Equal(t, ErrPipelineFirstStage, err)
+
+	_, err = validatePipeline([]models.PipelineStage{{Name: "Withdrawn"}})
+	assert.Equal(t, ErrPipelineStageKeyReserved, err)
+
+	_, err = validatePipeline([]models.PipelineStage{{Name: "Screen", Transitions: pq.StringArray{"offer"}}})
+	assert.Equal(t, ErrPipelineTransition, err)
+
+	// Names up to 255 characters would derive keys too long for the status column
+	_, err = validatePipeline([]models.PipelineStage{{Name: strings.Repeat("Technical interview ", 3)}})
+	assert.Equal(t, ErrPipelineStageKeyTooLong, err)
+	stages, err = validatePipeline([]models.PipelineStage{{Key: "technical", Name: strings.Repeat("Technical interview ", 3)}})
+	assert.NoError(t, err)
+	assert.Equal(t, "technical", stages[0].Key)
+}
+
+func TestCanMoveToStage(t *testing.T) {
+	stages, err := validatePipeline([]models.PipelineStage{
+		{Name: "Applied"},
+		{Name: "Phone screen"},
+		{Name: "Onsite", Transitions: pq.StringArray{"phone_screen", "offer"}},
+		{Name: "Offer", Type: models.StageTypeHired},
+		{Name: "Rejected", Type: models.StageTypeRejected, Transitions: pq.StringArray{"applied"}},
+	})
+	assert.NoError(t, err)
+
+	applied, screen, onsite, offer, rejected := &stages[0], &stages[1], &stages[2], &stages[3], &stages[4]
+
+	// Forward by default, not backwards
+	assert.True(t, canMoveToStage(applied, onsite))
+	assert.False(t, canMoveToStage(screen, applied))
+	// Explicit transitions replace the default rule, rejecting is always possible
+	assert.True(t, canMoveToStage(onsite, screen))
+	assert.True(t, canMoveToStage(onsite, rejected))
+	assert.False(t, canMoveToStage(onsite, applied))
+	// Hired and rejected candidates only move through explicit transitions
+	assert.False(t, canMoveToStage(offer, rejected))
+	assert.True(t, canMoveToStage(rejected, applied))
+	assert.False(t, canMoveToStage(rejected, screen))
+	// Statuses that are no longer in the pipeline can move anywhere
+	assert.True(t, canMoveToStage(nil, applied))
+}
@@ -33,10 +33,10 @@ func CreateProfile(ctx context.Context, req *models.Profile) error {
 		return ErrProfileExists
 	}
 
-	// Convert all strings in Areas_of_expertise to lowercase
-	lowerCaseAOE := make([]string, len(req.Areas_of_expertise))
-	for i, area := range req.Areas_of_expertise {
-		lowerCaseAOE[i] = strings.ToLower(area)
+	// Normalize Areas_of_expertise against the company's skills taxonomy, stored lowercase
+	lowerCaseAOE, err := normalizeAreasOfExpertise(ctx, userID, req.Areas_of_expertise)
+	if err != nil {
+		return err
 	}
 
 	// If no profile exists, create new one
@@ -115,17 +115,32 @@ func UpdateMyProfile(ctx context.Context, req *models.Profile) error {
 
 	userID := ctx.Value("userID")
 	
-	// Convert all strings in Areas_of_expertise to lowercase
-	lowerCaseAOE := make([]string, len(req.Areas_of_expertise))
-	for i, area := range req.Areas_of_expertise {
-		lowerCaseAOE[i] = strings.ToLower(area)
+	// Normalize Areas_of_expertise against the company's skills taxonomy, stored lowercase
+	lowerCaseAOE, err := normalizeAreasOfExpertise(ctx, userID, req.Areas_of_expertise)
+	if err != nil {
+		return err
 	}
 
-	_, err := db.ExecContext(ctx,
+	_, err = db.ExecContext(ctx,
 		`UPDATE profiles 
 		 SET job_title = $2, years_of_experience = $3, areas_of_expertise = $4, phone_number = $5
 		 WHERE user_id = $1`,
 		userID, req.JobTitle, req.YearsOfExperience, pq.Array(lowerCaseAOE), req.PhoneNumber)
 	
 	return err
-}
\ No newline at end of file
+}
+
+// normalizeAreasOfExpertise maps areas of expertise to their canonical skill names in lowercase,
+// so interviewers can be matched by any spelling of a skill
+func normalizeAreasOfExpertise(ctx context.Context, userID interface{}, areas []string) ([]string, error) {
+	taxonomy, err := getUserSkillTaxonomy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := taxonomy.NormalizeAll(areas)
+	for i, area := range normalized {
+		normalized[i] = strings.ToLower(area)
+	}
+	return normalized, nil
+}
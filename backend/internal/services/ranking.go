@@ -0,0 +1,333 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var ErrRankingWeightsZero = errors.New("at least one ranking weight must be greater than 0")
+
+const (
+	defaultShortlistSize = 10
+	defaultRecencyDays   = 30
+	// neutralInterviewScore is used until an interviewer gave a verdict, so candidates
+	// without interviews are neither favoured nor penalised
+	neutralInterviewScore = 50
+)
+
+var defaultRankingWeights = models.RankingWeights{
+	Score:       50,
+	Knockout:    20,
+	Interviews:  20,
+	Recency:     10,
+	RecencyDays: defaultRecencyDays,
+}
+
+// getRankingWeights returns the ranking weights of a job by its numeric id, or the default weights
+func getRankingWeights(ctx context.Context, jobDBID int) (models.RankingWeights, bool, error) {
+	db := database.GetDB()
+
+	var weights models.RankingWeights
+	err := db.GetContext(ctx, &weights, `
+		SELECT score_weight, knockout_weight, interview_weight, recency_weight, recency_days, exclude_knocked_out
+		FROM job_ranking_weights
+		WHERE job_id = $1`, jobDBID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return defaultRankingWeights, true, nil
+		}
+		return weights, false, err
+	}
+	return weights, false, nil
+}
+
+// GetJobRankingWeights returns the ranking weights of a job of the current user
+func GetJobRankingWeights(ctx context.Context, jobID string) (*models.JobRankingWeights, error) {
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	weights, isDefault, err := getRankingWeights(ctx, jobDBID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.JobRankingWeights{JobID: jobID, IsDefault: isDefault, RankingWeights: weights}, nil
+}
+
+// UpdateJobRankingWeights stores the ranking weights of a job of the current user
+func UpdateJobRankingWeights(ctx context.Context, jobID string, weights models.RankingWeights) (*models.JobRankingWeights, error) {
+	db := database.GetDB()
+
+	if weights.Score+weights.Knockout+weights.Interviews+weights.Recency == 0 {
+		return nil, ErrRankingWeightsZero
+	}
+	if weights.RecencyDays == 0 {
+		weights.RecencyDays = defaultRecencyDays
+	}
+
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO job_ranking_weights (job_id, score_weight, knockout_weight, interview_weight, recency_weight, recency_days, exclude_knocked_out)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (job_id) DO UPDATE SET
+			score_weight = EXCLUDED.score_weight,
+			knockout_weight = EXCLUDED.knockout_weight,
+			interview_weight = EXCLUDED.interview_weight,
+			recency_weight = EXCLUDED.recency_weight,
+			recency_days = EXCLUDED.recency_days,
+			exclude_knocked_out = EXCLUDED.exclude_knocked_out,
+			updated_at = NOW()`,
+		jobDBID, weights.Score, weights.Knockout, weights.Interviews, weights.Recency, weights.RecencyDays, weights.ExcludeKnockedOut)
+	if err != nil {
+		return nil, err
+	}
+	return &models.JobRankingWeights{JobID: jobID, RankingWeights: weights}, nil
+}
+
+// rankingInput is a submission with everything the ranking looks at
+type rankingInput struct {
+	ID           int                       `db:"id"`
+	CandidateID  *int                      `db:"candidate_id"`
+	Username     string                    `db:"username"`
+	Email        string                    `db:"email"`
+	Status       string                    `db:"status"`
+	ATSScore     int                       `db:"ats_score"`
+	ATSBreakdown *models.ATSScoreBreakdown `db:"ats_breakdown"`
+	Verdicts     pq.StringArray            `db:"verdicts"`
+	CreatedAt    time.Time                 `db:"created_at"`
+}
+
+// knockoutFailures returns the knockout criteria of the ATS breakdown the candidate fails:
+// missing required skills and less experience than the job's minimum
+func knockoutFailures(breakdown *models.ATSScoreBreakdown) (int, []string) {
+	checks, failures := 0, []string{}
+	if breakdown == nil {
+		return checks, failures
+	}
+	for _, criterion := range breakdown.Criteria {
+		switch criterion.Criterion {
+		case models.ATSCriterionRequiredSkills:
+			checks++
+			if len(criterion.Missing) > 0 {
+				failures = append(failures, "missing required skills: "+strings.Join(criterion.Missing, ", "))
+			}
+		case models.ATSCriterionExperience:
+			checks++
+			if criterion.Score < 100 {
+				failures = append(failures, criterion.Detail)
+			}
+		}
+	}
+	return checks, failures
+}
+
+// scoreKnockout rates the share of knockout criteria the candidate passes
+func scoreKnockout(breakdown *models.ATSScoreBreakdown) (models.RankingComponent, bool) {
+	component := models.RankingComponent{Factor: models.RankingFactorKnockout, Score: 100}
+	checks, failures := knockoutFailures(breakdown)
+	switch {
+	case checks == 0:
+		component.Detail = "no knockout criteria"
+	case len(failures) == 0:
+		component.Detail = "passes all knockout criteria"
+	default:
+		component.Score = (checks - len(failures)) * 100 / checks
+		component.Detail = "knocked out, " + strings.Join(failures, "; ")
+	}
+	return component, len(failures) > 0
+}
+
+// scoreInterviews rates the share of yes verdicts, pending interviews are not counted
+func scoreInterviews(verdicts []string) models.RankingComponent {
+	component := models.RankingComponent{Factor: models.RankingFactorInterviews, Score: neutralInterviewScore}
+	passed, decided := 0, 0
+	for _, verdict := range verdicts {
+		switch verdictOutcome(verdict) {
+		case models.InterviewVerdictPassed:
+			passed++
+			decided++
+		case models.InterviewVerdictFailed:
+			decided++
+		}
+	}
+	if decided == 0 {
+		component.Detail = "no interview verdicts yet"
+		return component
+	}
+	component.Score = passed * 100 / decided
+	component.Detail = fmt.Sprintf("passed %d of %d interviews", passed, decided)
+	return component
+}
+
+// scoreRecency rates how recently the candidate applied, decreasing linearly to 0 after recencyDays
+func scoreRecency(appliedAt time.Time, now time.Time, recencyDays int) models.RankingComponent {
+	component := models.RankingComponent{Factor: models.RankingFactorRecency}
+	days := int(now.Sub(appliedAt).Hours() / 24)
+	if days < 0 {
+		days = 0
+	}
+	if days < recencyDays {
+		component.Score = int(math.Round(float64(recencyDays-days) * 100 / float64(recencyDays)))
+	}
+	switch days {
+	case 0:
+		component.Detail = "applied today"
+	case 1:
+		component.Detail = "applied yesterday"
+	default:
+		component.Detail = fmt.Sprintf("applied %d days ago", days)
+	}
+	return component
+}
+
+// rankCandidate combines the ranking factors of a submission into its ranking score
+func rankCandidate(input rankingInput, weights models.RankingWeights, now time.Time) models.RankedCandidate {
+	recencyDays := weights.RecencyDays
+	if recencyDays <= 0 {
+		recencyDays = defaultRecencyDays
+	}
+
+	knockout, knockedOut := scoreKnockout(input.ATSBreakdown)
+	components := []models.RankingComponent{
+		{Factor: models.RankingFactorScore, Weight: weights.Score, Score: input.ATSScore, Detail: fmt.Sprintf("ATS score %d", input.ATSScore)},
+		knockout,
+		scoreInterviews(input.Verdicts),
+		scoreRecency(input.CreatedAt, now, recencyDays),
+	}
+	components[1].Weight = weights.Knockout
+	components[2].Weight = weights.Interviews
+	components[3].Weight = weights.Recency
+
+	totalWeight := weights.Score + weights.Knockout + weights.Interviews + weights.Recency
+	if totalWeight == 0 {
+		totalWeight = 1
+	}
+
+	// Scale the weights so they add up to 100, factors without weight are left out
+	total := 0.0
+	explained := []string{}
+	weighted := []models.RankingComponent{}
+	for _, component := range components {
+		if component.Weight == 0 {
+			continue
+		}
+		weight := float64(component.Weight) * 100 / float64(totalWeight)
+		component.Weight = int(math.Round(weight))
+		component.Points = math.Round(float64(component.Score)*weight) / 100
+		total += float64(component.Score) * weight / 100
+		weighted = append(weighted, component)
+		explained = append(explained, component.Detail)
+	}
+
+	return models.RankedCandidate{
+		SubmissionID: input.ID,
+		CandidateID:  input.CandidateID,
+		Username:     input.Username,
+		Email:        input.Email,
+		Status:       input.Status,
+		ATSScore:     input.ATSScore,
+		RankingScore: math.Round(total*100) / 100,
+		KnockedOut:   knockedOut,
+		Explanation:  strings.Join(explained, "; "),
+		Components:   weighted,
+		AppliedAt:    input.CreatedAt,
+	}
+}
+
+// sortRankedCandidates orders candidates by ranking score, then ATS score, then who applied first
+func sortRankedCandidates(candidates []models.RankedCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.RankingScore != b.RankingScore {
+			return a.RankingScore > b.RankingScore
+		}
+		if a.ATSScore != b.ATSScore {
+			return a.ATSScore > b.ATSScore
+		}
+		if !a.AppliedAt.Equal(b.AppliedAt) {
+			return a.AppliedAt.Before(b.AppliedAt)
+		}
+		return a.SubmissionID < b.SubmissionID
+	})
+}
+
+// GetJobRanking ranks the open applications of a job of the current user and returns the top candidates.
+// Withdrawn applications and applications in a rejected or hired stage are not ranked.
+func GetJobRanking(ctx context.Context, jobID string, req models.RankingRequest) (*models.JobRanking, error) {
+	db := database.GetDB()
+
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	weights, _, err := getRankingWeights(ctx, jobDBID)
+	if err != nil {
+		return nil, err
+	}
+	stages, _, err := getJobStages(ctx, db, jobDBID)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultShortlistSize
+	}
+
+	inputs := []rankingInput{}
+	err = db.SelectContext(ctx, &inputs, `
+		SELECT js.id, js.candidate_id, js.username, js.email, js.status, js.ats_score, js.ats_breakdown, js.created_at,
+			ARRAY(SELECT i.verdict FROM interviews i WHERE i.job_submission_id = js.id AND i.status <> $3
+				UNION ALL
+				SELECT p.verdict FROM interview_panelists p JOIN interviews i ON p.interview_id = i.id
+				WHERE i.job_submission_id = js.id AND i.status <> $3) AS verdicts
+		FROM job_submissions js
+		JOIN application_form af ON js.form_uuid = af.form_uuid
+		WHERE af.job_id = $1 AND js.status <> $2`, jobDBID, models.SubmissionStatusWithdrawn, models.InterviewStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ranked := []models.RankedCandidate{}
+	for _, input := range inputs {
+		if stage := findStage(stages, input.Status); stage != nil && stage.Type != models.StageTypeActive {
+			continue
+		}
+		candidate := rankCandidate(input, weights, now)
+		if candidate.KnockedOut && weights.ExcludeKnockedOut {
+			continue
+		}
+		ranked = append(ranked, candidate)
+	}
+	sortRankedCandidates(ranked)
+
+	shortlist := ranked
+	if len(shortlist) > limit {
+		shortlist = shortlist[:limit]
+	}
+	for i := range shortlist {
+		shortlist[i].Rank = i + 1
+	}
+
+	return &models.JobRanking{
+		JobID:      jobID,
+		Weights:    weights,
+		Candidates: len(ranked),
+		Shortlist:  shortlist,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"backend/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreKnockout(t *testing.T) {
+	component, knockedOut := scoreKnockout(nil)
+	assert.False(t, knockedOut)
+	assert.Equal(t, 100, component.Score)
+
+	component, knockedOut = scoreKnockout(&models.ATSScoreBreakdown{Criteria: []models.ATSCriterionScore{
+		{Criterion: models.ATSCriterionRequiredSkills, Score: 50, Missing: []string{"Kubernetes"}},
+		{Criterion: models.ATSCriterionExperience, Score: 100, Detail: "5 years of experience, 3 required"},
+		{Criterion: models.ATSCriterionNiceToHaveSkills, Score: 0, Missing: []string{"Rust"}},
+	}})
+	assert.True(t, knockedOut)
+	assert.Equal(t, 50, component.Score)
+	assert.Contains(t, component.Detail, "missing required skills: Kubernetes")
+	// Nice-to-have skills are no knockout criterion
+	assert.NotContains(t, component.Detail, "Rust")
+}
+
+func TestScoreInterviews(t *testing.T) {
+	assert.Equal(t, neutralInterviewScore, scoreInterviews(nil).Score)
+	assert.Equal(t, neutralInterviewScore, scoreInterviews([]string{"pending"}).Score)
+
+	component := scoreInterviews([]string{"passed", "failed", "pending"})
+	assert.Equal(t, 50, component.Score)
+	assert.Equal(t, "passed 1 of 2 interviews", component.Detail)
+
+	component = scoreInterviews([]string{"strong_yes", "yes", "no", "strong_no"})
+	assert.Equal(t, 50, component.Score)
+}
+
+func TestScoreRecency(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 100, scoreRecency(now.Add(-time.Hour), now, 30).Score)
+	assert.Equal(t, 50, scoreRecency(now.AddDate(0, 0, -15), now, 30).Score)
+	assert.Equal(t, 0, scoreRecency(now.AddDate(0, 0, -45), now, 30).Score)
+	assert.Equal(t, "applied 15 days ago", scoreRecency(now.AddDate(0, 0, -15), now, 30).Detail)
+}
+
+func TestRankCandidate(t *testing.T) {
+	now := time.Now()
+	input := rankingInput{ID: 1, ATSScore: 80, Verdicts: []string{"passed"}, CreatedAt: now}
+
+	ranked := rankCandidate(input, models.RankingWeights{Score: 50, Knockout: 20, Interviews: 20, Recency: 10, RecencyDays: 30}, now)
+	// 80*0.5 + 100*0.2 + 100*0.2 + 100*0.1
+	assert.Equal(t, 90.0, ranked.RankingScore)
+	assert.Len(t, ranked.Components, 4)
+	assert.Equal(t, "ATS score 80; no knockout criteria; passed 1 of 1 interviews; applied today", ranked.Explanation)
+
+	// Factors without weight are left out and the others scaled up
+	ranked = rankCandidate(input, models.RankingWeights{Score: 1}, now)
+	assert.Equal(t, 80.0, ranked.RankingScore)
+	assert.Len(t, ranked.Components, 1)
+	assert.Equal(t, 100, ranked.Components[0].Weight)
+}
+
+func TestSortRankedCandidates(t *testing.T) {
+	now := time.Now()
+	candidates := []models.RankedCandidate{
+		{SubmissionID: 1, RankingScore: 70, ATSScore: 60, AppliedAt: now},
+		{SubmissionID: 2, RankingScore: 90, ATSScore: 80, AppliedAt: now},
+		{SubmissionID: 3, RankingScore: 70, ATSScore: 75, AppliedAt: now},
+		{SubmissionID: 4, RankingScore: 70, ATSScore: 60, AppliedAt: now.Add(-time.Hour)},
+	}
+	sortRankedCandidates(candidates)
+
+	ids := []int{}
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.SubmissionID)
+	}
+	assert.Equal(t, []int{2, 3, 4, 1}, ids)
+}
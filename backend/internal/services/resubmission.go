@@ -0,0 +1,259 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrDuplicateApplication     = errors.New("you have already applied for this job with this email")
+	ErrResubmissionWindowClosed = errors.New("the window to update your application for this job has closed")
+	ErrResubmissionNotVerified  = errors.New("open the link sent to your email to update your application")
+	ErrSubmissionNotFound       = errors.New("submission not found")
+)
+
+// resubmissionPolicy is the per-job rule applied when a candidate applies again with the same email
+type resubmissionPolicy struct {
+	Policy     string `db:"resubmission_policy"`
+	WindowDays int    `db:"resubmission_window_days"`
+}
+
+// getResubmissionPolicy loads the policy of the job the application form belongs to
+func getResubmissionPolicy(ctx context.Context, formUUID string) (resubmissionPolicy, error) {
+	db := database.GetDB()
+
+	policy := resubmissionPolicy{Policy: models.ResubmissionPolicyReject}
+	err := db.GetContext(ctx, &policy, `
+		SELECT j.resubmission_policy, j.resubmission_window_days
+		FROM application_form af
+		JOIN jobs j ON af.job_id = j.id
+		WHERE af.form_uuid = $1`, formUUID)
+	if err != nil && err != sql.ErrNoRows {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// findExistingSubmission returns the candidate's current application for the job, or nil if there is none
+func findExistingSubmission(ctx context.Context, jobID string, email string) (*models.JobSubmission, error) {
+	db := database.GetDB()
+
+	var submission models.JobSubmission
+	err := db.GetContext(ctx, &submission, `
+		SELECT id, job_id, username, email, form_data, form_uuid, skills, resume_key, resume_file_name, resume_text, resume_data, ats_score, ats_breakdown, status, revision, created_at, updated_at
+		FROM job_submissions
+		WHERE job_id = $1 AND LOWER(email) = LOWER($2)`, jobID, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// archivedAttachment is an attachment as kept in the history of a submission, with the key of its file
+type archivedAttachment struct {
+	models.SubmissionAttachment
+	FileKey string `json:"file_key"`
+}
+
+// checkResubmissionAllowed decides whether an existing application may be replaced by a new one.
+// Only the candidate may update it, proven by the magic link token sent to the application's email.
+func checkResubmissionAllowed(existing *models.JobSubmission, policy resubmissionPolicy, token string, now time.Time) error {
+	if policy.Policy != models.ResubmissionPolicyAllowUpdate {
+		return ErrDuplicateApplication
+	}
+
+	if token == "" {
+		return ErrResubmissionNotVerified
+	}
+	submissionID, email, err := parseCandidateToken(token)
+	if err != nil || submissionID != existing.ID || email != strings.ToLower(existing.Email) {
+		return ErrResubmissionNotVerified
+	}
+
+	deadline := existing.CreatedAt.AddDate(0, 0, policy.WindowDays)
+	if now.After(deadline) {
+		return ErrResubmissionWindowClosed
+	}
+	return nil
+}
+
+// updateJobSubmission archives the current version of an application and replaces it with the resubmitted one
+func updateJobSubmission(ctx context.Context, existing *models.JobSubmission, updated *models.JobSubmission) error {
+	db := database.GetDB()
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceJobSubmission(ctx, tx, existing, updated); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// replaceJobSubmission is updateJobSubmission within the caller's transaction
+func replaceJobSubmission(ctx context.Context, tx *sqlx.Tx, existing *models.JobSubmission, updated *models.JobSubmission) error {
+	// Keep the previous version, including its attachments, in the history table
+	existingAttachments, err := GetAttachmentsForSubmissions(ctx, []int{existing.ID})
+	if err != nil {
+		return err
+	}
+	archivedAttachments := []archivedAttachment{}
+	for _, attachment := range existingAttachments[existing.ID] {
+		fileKey := attachment.FileKey
+		attachment.FileURL = ""
+		archivedAttachments = append(archivedAttachments, archivedAttachment{SubmissionAttachment: attachment, FileKey: fileKey})
+	}
+	attachmentsJSON, err := json.Marshal(archivedAttachments)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO job_submission_history (job_submission_id, revision, username, form_data, skills, resume_key, resume_file_name, ats_score, ats_breakdown, attachments, submitted_at)
+		SELECT id, revision, username, form_data, skills, resume_key, resume_file_name, ats_score, ats_breakdown, $2, updated_at
+		FROM job_submissions WHERE id = $1`,
+		existing.ID, attachmentsJSON)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE job_submissions
+		SET form_uuid = $1, username = $2, form_data = $3, skills = $4, resume_key = $5, resume_text = $6,
+			resume_data = $7, ats_score = $8, ats_breakdown = $9, revision = revision + 1, updated_at = $10,
+			candidate_id = COALESCE($12, candidate_id), candidate_match = COALESCE(NULLIF($13, ''), candidate_match),
+			resume_file_name = $14
+		WHERE id = $11
+		RETURNING id, status, revision, created_at, candidate_id, candidate_match`,
+		updated.FormUUID,
+		updated.Username,
+		updated.FormData,
+		updated.Skills,
+		updated.ResumeKey,
+		updated.ResumeText,
+		updated.ResumeData,
+		updated.ATSScore,
+		updated.ATSBreakdown,
+		updated.UpdatedAt,
+		existing.ID,
+		updated.CandidateID,
+		updated.CandidateMatch,
+		updated.ResumeFileName).Scan(&updated.ID, &updated.Status, &updated.Revision, &updated.CreatedAt, &updated.CandidateID, &updated.CandidateMatch)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM submission_attachments WHERE job_submission_id = $1", existing.ID); err != nil {
+		return err
+	}
+	if err := insertSubmissionAttachments(ctx, tx, updated); err != nil {
+		return err
+	}
+	if err := refreshSubmissionSearchVector(ctx, tx, existing.ID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkSubmissionOwner returns ErrSubmissionNotFound unless the submission was made to a job of the user.
+// Candidate data such as resumes and previous versions is only shown to the HR user owning the job.
+func checkSubmissionOwner(ctx context.Context, q sqlx.QueryerContext, userID int, submissionID int) error {
+	var isJobOwner bool
+	err := sqlx.GetContext(ctx, q, &isJobOwner, `
+		SELECT EXISTS(
+			SELECT 1 FROM job_submissions js
+			JOIN application_form af ON js.form_uuid = af.form_uuid
+			JOIN jobs j ON af.job_id = j.id
+			WHERE js.id = $1 AND j.user_id = $2)`,
+		submissionID, userID)
+	if err != nil {
+		return err
+	}
+	if !isJobOwner {
+		return ErrSubmissionNotFound
+	}
+	return nil
+}
+
+// GetSubmissionHistory returns the previous versions of a submission to the HR user owning the job
+func GetSubmissionHistory(ctx context.Context, submissionID int) ([]models.SubmissionRevision, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	if err := checkSubmissionOwner(ctx, db, userID, submissionID); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, job_submission_id, revision, username, form_data, skills, resume_key, resume_file_name, ats_score, ats_breakdown, attachments, submitted_at, archived_at
+		FROM job_submission_history
+		WHERE job_submission_id = $1
+		ORDER BY revision DESC`, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.SubmissionRevision{}
+	for rows.Next() {
+		var revision models.SubmissionRevision
+		var formData []byte
+		var attachmentsJSON []byte
+		err := rows.Scan(
+			&revision.ID,
+			&revision.JobSubmissionID,
+			&revision.Revision,
+			&revision.Username,
+			&formData,
+			&revision.Skills,
+			&revision.ResumeKey,
+			&revision.ResumeFileName,
+			&revision.ATSScore,
+			&revision.ATSBreakdown,
+			&attachmentsJSON,
+			&revision.SubmittedAt,
+			&revision.ArchivedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		revision.FormData = formData
+		revision.ResumeURL, err = SignedResumeURL(ctx, revision.ResumeKey)
+		if err != nil {
+			return nil, err
+		}
+		revision.Attachments = []models.SubmissionAttachment{}
+		if len(attachmentsJSON) > 0 {
+			var archived []archivedAttachment
+			if err := json.Unmarshal(attachmentsJSON, &archived); err != nil {
+				return nil, err
+			}
+			for _, attachment := range archived {
+				attachment.SubmissionAttachment.FileKey = attachment.FileKey
+				revision.Attachments = append(revision.Attachments, attachment.SubmissionAttachment)
+			}
+			if err := signAttachmentURLs(ctx, revision.Attachments); err != nil {
+				return nil, err
+			}
+		}
+		history = append(history, revision)
+	}
+
+	return history, rows.Err()
+}
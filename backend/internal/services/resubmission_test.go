@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/config"
+	"backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckResubmissionAllowed(t *testing.T) {
+	assert.NoError(t, config.LoadConfig())
+	appliedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	existing := &models.JobSubmission{ID: 42, Email: "Jane@Example.com", CreatedAt: appliedAt}
+	token, err := GenerateCandidateToken(42, "jane@example.com")
+	assert.NoError(t, err)
+	otherToken, err := GenerateCandidateToken(43, "jane@example.com")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		policy      resubmissionPolicy
+		token       string
+		now         time.Time
+		expectedErr error
+	}{
+		{
+			name:        "Reject policy",
+			policy:      resubmissionPolicy{Policy: models.ResubmissionPolicyReject, WindowDays: 30},
+			token:       token,
+			now:         appliedAt.Add(time.Hour),
+			expectedErr: ErrDuplicateApplication,
+		},
+		{
+			name:        "Update within window",
+			policy:      resubmissionPolicy{Policy: models.ResubmissionPolicyAllowUpdate, WindowDays: 7},
+			token:       token,
+			now:         appliedAt.AddDate(0, 0, 6),
+			expectedErr: nil,
+		},
+		{
+			name:        "Update after window",
+			policy:      resubmissionPolicy{Policy: models.ResubmissionPolicyAllowUpdate, WindowDays: 7},
+			token:       token,
+			now:         appliedAt.AddDate(0, 0, 8),
+			expectedErr: ErrResubmissionWindowClosed,
+		},
+		{
+			name:        "Update without magic link",
+			policy:      resubmissionPolicy{Policy: models.ResubmissionPolicyAllowUpdate, WindowDays: 7},
+			now:         appliedAt.AddDate(0, 0, 6),
+			expectedErr: ErrResubmissionNotVerified,
+		},
+		{
+			name:        "Update with the magic link of another application",
+			policy:      resubmissionPolicy{Policy: models.ResubmissionPolicyAllowUpdate, WindowDays: 7},
+			token:       otherToken,
+			now:         appliedAt.AddDate(0, 0, 6),
+			expectedErr: ErrResubmissionNotVerified,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedErr, checkResubmissionAllowed(existing, tc.policy, tc.token, tc.now))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"backend/internal/config"
+	"backend/internal/database"
+	"backend/internal/storage"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	ErrResumeNotFound   = errors.New("resume not found")
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+// SignedResumeURL returns an expiring URL of a stored resume, or "" when there is no resume
+func SignedResumeURL(ctx context.Context, key string) (string, error) {
+	if key == "" {
+		return "", nil
+	}
+	store, err := GetStorage()
+	if err != nil {
+		return "", err
+	}
+	return store.SignedURL(ctx, key, config.GetConfig().ResumeURLTTL)
+}
+
+// storedResume is the resume of a submission, or of a previous revision of it
+type storedResume struct {
+	Key      string `db:"resume_key"`
+	FileName string `db:"resume_file_name"`
+	Username string `db:"username"`
+}
+
+// getSubmissionResume returns the resume of a submission to the HR user owning the job, like the submission listings.
+// A revision of 0 is the current resume.
+func getSubmissionResume(ctx context.Context, submissionID int, revision int) (*storedResume, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	if err := checkSubmissionOwner(ctx, db, userID, submissionID); err != nil {
+		return nil, err
+	}
+
+	var resume storedResume
+	err := db.GetContext(ctx, &resume, `
+		SELECT resume_key, resume_file_name, username FROM job_submissions WHERE id = $1 AND ($2 = 0 OR revision = $2)
+		UNION ALL
+		SELECT resume_key, resume_file_name, username FROM job_submission_history WHERE job_submission_id = $1 AND revision = $2
+		LIMIT 1`, submissionID, revision)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+	if resume.Key == "" {
+		return nil, ErrResumeNotFound
+	}
+	return &resume, nil
+}
+
+// GetResumeDownloadURL returns a short-lived signed URL to the resume of a submission
+func GetResumeDownloadURL(ctx context.Context, submissionID int, revision int) (string, error) {
+	resume, err := getSubmissionResume(ctx, submissionID, revision)
+	if err != nil {
+		return "", err
+	}
+	return SignedResumeURL(ctx, resume.Key)
+}
+
+// OpenResume opens the resume of a submission for streaming and returns the file name to download it as
+func OpenResume(ctx context.Context, submissionID int, revision int) (io.ReadCloser, *storage.ObjectInfo, string, error) {
+	resume, err := getSubmissionResume(ctx, submissionID, revision)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	store, err := GetStorage()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	file, info, err := store.Get(ctx, resume.Key)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, nil, "", ErrResumeNotFound
+		}
+		return nil, nil, "", err
+	}
+	return file, info, resume.downloadName(), nil
+}
+
+// downloadName is the name the resume was uploaded with, made safe for a Content-Disposition header.
+// Resumes uploaded before the name was kept are named after the candidate.
+func (r *storedResume) downloadName() string {
+	if r.FileName != "" {
+		ext := path.Ext(r.FileName)
+		name := strings.Trim(sanitizeFilename(strings.TrimSuffix(r.FileName, ext)), "_")
+		if name != "" {
+			if ext != "" {
+				name += "." + sanitizeFilename(strings.TrimPrefix(ext, "."))
+			}
+			return name
+		}
+	}
+	return sanitizeFilename(r.Username) + "_resume" + path.Ext(r.Key)
+}
@@ -0,0 +1,494 @@
+package services
+
+import (
+	"backend/internal/models"
+	"fmt"
+	"log"
+	"math"
+	"mime/multipart"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Resume sections recognised by their heading
+const (
+	resumeSectionNone       = ""
+	resumeSectionExperience = "experience"
+	resumeSectionEducation  = "education"
+	resumeSectionSkills     = "skills"
+	resumeSectionOther      = "other"
+)
+
+var resumeSectionHeadings = map[string]string{
+	"experience":              resumeSectionExperience,
+	"work experience":         resumeSectionExperience,
+	"professional experience": resumeSectionExperience,
+	"employment":              resumeSectionExperience,
+	"employment history":      resumeSectionExperience,
+	"work history":            resumeSectionExperience,
+	"career history":          resumeSectionExperience,
+	"education":               resumeSectionEducation,
+	"academic background":     resumeSectionEducation,
+	"education and training":  resumeSectionEducation,
+	"skills":                  resumeSectionSkills,
+	"technical skills":        resumeSectionSkills,
+	"key skills":              resumeSectionSkills,
+	"core competencies":       resumeSectionSkills,
+	"technologies":            resumeSectionSkills,
+	"tech stack":              resumeSectionSkills,
+	"summary":                 resumeSectionOther,
+	"profile":                 resumeSectionOther,
+	"objective":               resumeSectionOther,
+	"projects":                resumeSectionOther,
+	"certifications":          resumeSectionOther,
+	"awards":                  resumeSectionOther,
+	"languages":               resumeSectionOther,
+	"interests":               resumeSectionOther,
+	"publications":            resumeSectionOther,
+	"references":              resumeSectionOther,
+	"volunteering":            resumeSectionOther,
+}
+
+// knownSkills are detected anywhere in the resume when it has no skills section.
+// Words that are ambiguous in prose, such as "go" or "rest", are left out.
+var knownSkills = []string{
+	"python", "java", "javascript", "typescript", "golang", "c++", "c#", "ruby", "rust", "kotlin", "swift", "php", "scala",
+	"sql", "postgresql", "mysql", "mongodb", "redis", "kafka", "elasticsearch",
+	"docker", "kubernetes", "terraform", "aws", "gcp", "azure", "linux", "git",
+	"react", "angular", "vue", "node.js", "graphql", "html", "css", "spark", "machine learning",
+}
+
+const resumeMonthPattern = `(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?`
+const resumeDatePattern = `(?:` + resumeMonthPattern + `\s*\d{4}|\d{1,2}/\d{4}|\d{4})`
+
+var (
+	resumeEmailPattern     = regexp.MustCompile(`(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`)
+	resumePhonePattern     = regexp.MustCompile(`\+?\(?\d[\d ().-]{7,}\d`)
+	resumeDateRangePattern = regexp.MustCompile(`(?i)(` + resumeDatePattern + `)\s*(?:-|–|—|to|until)\s*(` + resumeDatePattern + `|present|current|now|today)`)
+	resumeYearPattern      = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+	resumeDegreePattern    = regexp.MustCompile(`(?i)\b(?:bachelor|master|doctor|ph\.?\s?d|mba|b\.?\s?sc|m\.?\s?sc|b\.?\s?tech|m\.?\s?tech|b\.?\s?eng|m\.?\s?eng|b\.?s\.?|m\.?s\.?|b\.?a\.?|m\.?a\.?|b\.?e\.?|m\.?e\.?|associate|diploma)(?:[^a-z]|$)`)
+	resumeSchoolPattern    = regexp.MustCompile(`(?i)\b(?:university|college|institute|school|academy|polytechnic)\b`)
+	resumeTitlePattern     = regexp.MustCompile(`(?i)\b(?:engineer|developer|programmer|manager|lead|analyst|intern|designer|consultant|architect|scientist|director|specialist|administrator|officer|head|coordinator|assistant|recruiter|tester|devops|cto|ceo)\b`)
+	resumeSeparatorPattern = regexp.MustCompile(`\s*(?:\||,|\s-\s|\s–\s|\s—\s|\sat\s|\s@\s)\s*`)
+	resumeSkillSeparator   = regexp.MustCompile(`[,;|•·]`)
+)
+
+var resumeMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// ParseResume reads contact details, work history, education and skills from the text of a resume.
+// The parser is rule based and works offline, every field carries a confidence value.
+func ParseResume(text string) *models.ParsedResume {
+	return parseResume(text, time.Now())
+}
+
+// readResume extracts and parses an uploaded resume, an unreadable resume does not block the application
+func readResume(file *multipart.FileHeader) (string, *models.ParsedResume) {
+	text, err := ExtractResumeText(file)
+	if err != nil {
+		log.Printf("Failed to extract resume text: %v", err)
+		return "", nil
+	}
+	return text, ParseResume(text)
+}
+
+// ParseResumeFile extracts and parses an uploaded resume so the application form can be pre-filled
+func ParseResumeFile(file *multipart.FileHeader) (*models.ParsedResume, error) {
+	if err := validateResume(file); err != nil {
+		return nil, err
+	}
+
+	text, err := ExtractResumeText(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseResume(text), nil
+}
+
+// resumeLine is a non-empty line of the resume with the section it belongs to
+type resumeLine struct {
+	text    string
+	section string
+}
+
+func parseResume(text string, now time.Time) *models.ParsedResume {
+	parsed := &models.ParsedResume{
+		WorkHistory: []models.ParsedWorkExperience{},
+		Education:   []models.ParsedEducation{},
+		Skills:      []string{},
+	}
+	lines := splitResumeSections(text)
+
+	parsed.Email = parseResumeEmail(text)
+	parsed.Phone = parseResumePhone(text)
+	parsed.Name = parseResumeName(lines)
+
+	parsed.WorkHistory = parseWorkHistory(sectionLines(lines, resumeSectionExperience))
+	parsed.Education = parseEducation(sectionLines(lines, resumeSectionEducation))
+	parsed.Skills, parsed.SkillsConfidence = parseSkills(sectionLines(lines, resumeSectionSkills), text)
+
+	if years, ok := totalYearsOfExperience(parsed.WorkHistory, now); ok {
+		parsed.YearsOfExperience = years
+		parsed.YearsOfExperienceConfidence = 0.6
+	}
+	return parsed
+}
+
+// splitResumeSections assigns every line to the section of the closest heading above it
+func splitResumeSections(text string) []resumeLine {
+	lines := []resumeLine{}
+	section := resumeSectionNone
+
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+		heading := strings.ToLower(strings.Trim(line, " :-–—•*#"))
+		if found, ok := resumeSectionHeadings[heading]; ok {
+			section = found
+			continue
+		}
+		lines = append(lines, resumeLine{text: line, section: section})
+	}
+	return lines
+}
+
+func sectionLines(lines []resumeLine, section string) []string {
+	result := []string{}
+	for _, line := range lines {
+		if line.section == section {
+			result = append(result, line.text)
+		}
+	}
+	return result
+}
+
+func parseResumeEmail(text string) models.ParsedField {
+	email := resumeEmailPattern.FindString(text)
+	if email == "" {
+		return models.ParsedField{}
+	}
+	return models.ParsedField{Value: strings.ToLower(email), Confidence: 0.95}
+}
+
+func parseResumePhone(text string) models.ParsedField {
+	for _, match := range resumePhonePattern.FindAllString(text, -1) {
+		digits := 0
+		for _, r := range match {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		// Date ranges such as 2019 - 2021 also look like numbers
+		if digits < 10 || digits > 15 || resumeDateRangePattern.MatchString(match) {
+			continue
+		}
+		return models.ParsedField{Value: strings.TrimSpace(match), Confidence: 0.8}
+	}
+	return models.ParsedField{}
+}
+
+// parseResumeName takes the first line of the header that looks like a person's name
+func parseResumeName(lines []resumeLine) models.ParsedField {
+	for i, line := range lines {
+		if i >= 5 || line.section != resumeSectionNone {
+			break
+		}
+		if strings.ContainsAny(line.text, "@0123456789/:") {
+			continue
+		}
+
+		words := strings.Fields(line.text)
+		if len(words) < 2 || len(words) > 4 {
+			continue
+		}
+		isName := true
+		for _, word := range words {
+			first := []rune(word)[0]
+			if !unicode.IsUpper(first) || strings.IndexFunc(word, func(r rune) bool {
+				return !unicode.IsLetter(r) && r != '-' && r != '\'' && r != '.'
+			}) >= 0 {
+				isName = false
+				break
+			}
+		}
+		if isName {
+			return models.ParsedField{Value: line.text, Confidence: 0.7}
+		}
+	}
+	return models.ParsedField{}
+}
+
+// parseWorkHistory finds positions by their date range. The title and company are taken from
+// the rest of the line, or from the lines right above it when the date is on its own line.
+func parseWorkHistory(lines []string) []models.ParsedWorkExperience {
+	history := []models.ParsedWorkExperience{}
+	used := make([]bool, len(lines))
+
+	for i, line := range lines {
+		match := resumeDateRangePattern.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+		used[i] = true
+
+		experience := models.ParsedWorkExperience{}
+		experience.StartDate, _ = formatResumeDate(line[match[2]:match[3]])
+		end := strings.ToLower(line[match[4]:match[5]])
+		if end == "present" || end == "current" || end == "now" || end == "today" {
+			experience.Current = true
+		} else {
+			experience.EndDate, _ = formatResumeDate(end)
+		}
+
+		rest := strings.TrimSpace(line[:match[0]] + " " + line[match[1]:])
+		parts := splitResumeParts(rest)
+
+		// Title and company on the lines above the date
+		for j := i - 1; j >= 0 && j >= i-2 && len(parts) < 2; j-- {
+			if used[j] || !isResumeHeaderLine(lines[j]) {
+				break
+			}
+			used[j] = true
+			parts = append(splitResumeParts(lines[j]), parts...)
+		}
+
+		experience.Title, experience.Company = pickTitleAndCompany(parts)
+		switch {
+		case experience.Title != "" && experience.Company != "":
+			experience.Confidence = 0.8
+		case experience.Title != "" || experience.Company != "":
+			experience.Confidence = 0.5
+		default:
+			experience.Confidence = 0.3
+		}
+		history = append(history, experience)
+	}
+	return history
+}
+
+// isResumeHeaderLine reports whether a line may hold a title or company, as opposed to a bullet point
+func isResumeHeaderLine(line string) bool {
+	if strings.ContainsRune("-•*▪·–", []rune(line)[0]) {
+		return false
+	}
+	return len(strings.Fields(line)) <= 8 && !strings.HasSuffix(line, ".")
+}
+
+func splitResumeParts(text string) []string {
+	parts := []string{}
+	for _, part := range resumeSeparatorPattern.Split(text, -1) {
+		part = strings.Trim(part, " ()-–—|,")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// pickTitleAndCompany uses common job title words to tell the title from the company
+func pickTitleAndCompany(parts []string) (string, string) {
+	title, company := "", ""
+	for _, part := range parts {
+		if title == "" && resumeTitlePattern.MatchString(part) {
+			title = part
+		} else if company == "" {
+			company = part
+		}
+	}
+	if title == "" && len(parts) > 1 {
+		// No title word, assume the common "Title, Company" order
+		title, company = parts[0], parts[1]
+	}
+	return title, company
+}
+
+// formatResumeDate converts dates such as "Jan 2020", "01/2020" or "2020" to YYYY-MM or YYYY
+func formatResumeDate(value string) (string, bool) {
+	year, month, ok := parseResumeDate(value)
+	if !ok {
+		return "", false
+	}
+	if month == 0 {
+		return fmt.Sprintf("%04d", year), true
+	}
+	return fmt.Sprintf("%04d-%02d", year, month), true
+}
+
+func parseResumeDate(value string) (int, int, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	yearMatch := resumeYearPattern.FindString(value)
+	if yearMatch == "" {
+		return 0, 0, false
+	}
+	year, _ := strconv.Atoi(yearMatch)
+
+	month := 0
+	if len(value) == 7 && value[4] == '-' {
+		// Already formatted as YYYY-MM
+		month, _ = strconv.Atoi(value[5:])
+	} else if slash := strings.Index(value, "/"); slash > 0 {
+		month, _ = strconv.Atoi(value[:slash])
+	} else if len(value) >= 3 {
+		month = resumeMonths[value[:3]]
+	}
+	if month < 1 || month > 12 {
+		month = 0
+	}
+	return year, month, true
+}
+
+// totalYearsOfExperience adds up the work history, overlapping positions are counted once
+func totalYearsOfExperience(history []models.ParsedWorkExperience, now time.Time) (float64, bool) {
+	type interval struct{ start, end int }
+	intervals := []interval{}
+
+	for _, experience := range history {
+		startYear, startMonth, ok := parseResumeDate(experience.StartDate)
+		if !ok {
+			continue
+		}
+		if startMonth == 0 {
+			startMonth = 1
+		}
+
+		endYear, endMonth := now.Year(), int(now.Month())
+		if !experience.Current {
+			if endYear, endMonth, ok = parseResumeDate(experience.EndDate); !ok {
+				continue
+			}
+			if endMonth == 0 {
+				endMonth = 12
+			}
+		}
+
+		start, end := startYear*12+startMonth-1, endYear*12+endMonth
+		if end > start {
+			intervals = append(intervals, interval{start, end})
+		}
+	}
+	if len(intervals) == 0 {
+		return 0, false
+	}
+
+	// Merge overlapping intervals
+	for i := 1; i < len(intervals); i++ {
+		for j := i; j > 0 && intervals[j].start < intervals[j-1].start; j-- {
+			intervals[j], intervals[j-1] = intervals[j-1], intervals[j]
+		}
+	}
+	months := 0
+	current := intervals[0]
+	for _, next := range intervals[1:] {
+		if next.start <= current.end {
+			if next.end > current.end {
+				current.end = next.end
+			}
+			continue
+		}
+		months += current.end - current.start
+		current = next
+	}
+	months += current.end - current.start
+
+	return math.Round(float64(months)/12*10) / 10, true
+}
+
+// parseEducation groups degree, institution and graduation year lines into entries
+func parseEducation(lines []string) []models.ParsedEducation {
+	education := []models.ParsedEducation{}
+	var current *models.ParsedEducation
+
+	for _, line := range lines {
+		degree, institution := "", ""
+		for _, part := range splitResumeParts(resumeYearPattern.ReplaceAllString(line, "")) {
+			if institution == "" && resumeSchoolPattern.MatchString(part) {
+				institution = part
+			} else if degree == "" && resumeDegreePattern.MatchString(part) {
+				degree = part
+			}
+		}
+		year := 0
+		if years := resumeYearPattern.FindAllString(line, -1); len(years) > 0 {
+			year, _ = strconv.Atoi(years[len(years)-1])
+		}
+
+		if degree == "" && institution == "" {
+			if current != nil && current.GraduationYear == 0 {
+				current.GraduationYear = year
+			}
+			continue
+		}
+
+		if current == nil || (degree != "" && current.Degree != "") || (institution != "" && current.Institution != "") {
+			education = append(education, models.ParsedEducation{})
+			current = &education[len(education)-1]
+		}
+		if degree != "" {
+			current.Degree = degree
+		}
+		if institution != "" {
+			current.Institution = institution
+		}
+		if year != 0 {
+			current.GraduationYear = year
+		}
+	}
+
+	for i := range education {
+		if education[i].Degree != "" && education[i].Institution != "" {
+			education[i].Confidence = 0.85
+		} else {
+			education[i].Confidence = 0.5
+		}
+	}
+	return education
+}
+
+// parseSkills reads the skills section, or looks for well known skills in the whole text without one.
+// Skills are normalized against the built-in taxonomy, the company taxonomy is applied when the application is saved.
+func parseSkills(lines []string, text string) ([]string, float64) {
+	taxonomy := DefaultSkillTaxonomy()
+	skills := []string{}
+	seen := map[string]bool{}
+	add := func(skill string) {
+		key := taxonomy.Key(skill)
+		if key != "" && !seen[key] {
+			seen[key] = true
+			skills = append(skills, taxonomy.Normalize(skill))
+		}
+	}
+
+	for _, line := range lines {
+		line = strings.TrimLeft(line, "-•*▪· ")
+		if colon := strings.Index(line, ":"); colon >= 0 {
+			line = line[colon+1:]
+		}
+		for _, token := range resumeSkillSeparator.Split(line, -1) {
+			token = strings.Trim(token, " .")
+			if token != "" && len(token) <= 40 && len(strings.Fields(token)) <= 4 {
+				add(token)
+			}
+		}
+	}
+	if len(skills) > 0 {
+		return skills, 0.8
+	}
+
+	lower := strings.ToLower(text)
+	for _, skill := range knownSkills {
+		if containsTerm(lower, skill) {
+			add(skill)
+		}
+	}
+	if len(skills) > 0 {
+		return skills, 0.5
+	}
+	return skills, 0
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"backend/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleResume = `Jane Doe
+jane.doe@example.com | +1 (555) 123-4567
+Berlin, Germany
+
+Summary
+Backend engineer who enjoys distributed systems.
+
+Work Experience
+Senior Software Engineer, Acme Corp    Jan 2021 - Present
+- Built billing services in Go
+Globex
+Backend Developer
+03/2018 – 12/2020
+- Maintained the PostgreSQL cluster
+
+Education
+B.Sc. Computer Science, University of Springfield, 2017
+
+Skills
+Languages: Golang, Python
+Tools: Docker • K8S • Postgres
+`
+
+func TestParseResume(t *testing.T) {
+	now := time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC)
+	parsed := parseResume(sampleResume, now)
+
+	assert.Equal(t, models.ParsedField{Value: "Jane Doe", Confidence: 0.7}, parsed.Name)
+	assert.Equal(t, "jane.doe@example.com", parsed.Email.Value)
+	assert.Equal(t, "+1 (555) 123-4567", parsed.Phone.Value)
+
+	assert.Equal(t, []models.ParsedWorkExperience{
+		{Title: "Senior Software Engineer", Company: "Acme Corp", StartDate: "2021-01", Current: true, Confidence: 0.8},
+		{Title: "Backend Developer", Company: "Globex", StartDate: "2018-03", EndDate: "2020-12", Confidence: 0.8},
+	}, parsed.WorkHistory)
+
+	assert.Equal(t, []models.ParsedEducation{
+		{Degree: "B.Sc. Computer Science", Institution: "University of Springfield", GraduationYear: 2017, Confidence: 0.85},
+	}, parsed.Education)
+
+	assert.Equal(t, []string{"Go", "Python", "Docker", "Kubernetes", "PostgreSQL"}, parsed.Skills)
+	assert.Equal(t, 0.8, parsed.SkillsConfidence)
+
+	// Mar 2018 - Dec 2020 and Jan 2021 - Jan 2024, 71 months
+	assert.Equal(t, 5.9, parsed.YearsOfExperience)
+	assert.Equal(t, 0.6, parsed.YearsOfExperienceConfidence)
+}
+
+func TestParseResumeWithoutSections(t *testing.T) {
+	parsed := parseResume("Experienced developer working with Kubernetes and AWS every day.", time.Now())
+
+	assert.Equal(t, models.ParsedField{}, parsed.Name)
+	assert.Equal(t, models.ParsedField{}, parsed.Email)
+	assert.Empty(t, parsed.WorkHistory)
+	assert.Equal(t, []string{"Kubernetes", "AWS"}, parsed.Skills)
+	assert.Equal(t, 0.5, parsed.SkillsConfidence)
+	assert.Equal(t, 0.0, parsed.YearsOfExperienceConfidence)
+}
+
+func TestTotalYearsOfExperienceMergesOverlaps(t *testing.T) {
+	years, ok := totalYearsOfExperience([]models.ParsedWorkExperience{
+		{StartDate: "2015", EndDate: "2018"},
+		{StartDate: "2017-06", EndDate: "2019-12"},
+	}, time.Now())
+
+	assert.True(t, ok)
+	assert.Equal(t, 5.0, years)
+}
+
+func TestScoreExperienceFallsBackToResume(t *testing.T) {
+	parsed := &models.ParsedResume{YearsOfExperience: 6, YearsOfExperienceConfidence: 0.6}
+
+	criterion := scoreExperience(20, map[string]interface{}{}, parsed, 4)
+	assert.Equal(t, 100, criterion.Score)
+	assert.Equal(t, "6 years of experience (from resume work history), 4 required", criterion.Detail)
+
+	// The form answer wins over the resume
+	criterion = scoreExperience(20, map[string]interface{}{"Q_Experience": "2"}, parsed, 4)
+	assert.Equal(t, 50, criterion.Score)
+}
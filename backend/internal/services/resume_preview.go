@@ -0,0 +1,231 @@
+package services
+
+import (
+	"archive/zip"
+	"backend/internal/config"
+	"backend/internal/models"
+	"backend/internal/storage"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// previewKeyPrefix is the storage folder of generated resume previews
+const previewKeyPrefix = "previews/"
+
+// thumbnailTimeout limits rendering the first page of a PDF
+const thumbnailTimeout = 30 * time.Second
+
+// cachedPreview is stored next to the thumbnail, so a resume is only rendered once
+type cachedPreview struct {
+	HTML         string `json:"html"`
+	ThumbnailKey string `json:"thumbnail_key"` // empty when no thumbnail could be rendered
+}
+
+// previewKey is the key of the cached preview of a resume. Resume keys are unique per upload,
+// so a replaced resume never shows the preview of the previous one.
+func previewKey(resumeKey string) string {
+	return previewKeyPrefix + strings.TrimPrefix(resumeKey, "resumes/") + ".json"
+}
+
+// previewResumeKey returns the key of the resume a cached preview or thumbnail belongs to
+func previewResumeKey(key string) string {
+	rest := strings.TrimPrefix(key, previewKeyPrefix)
+	return "resumes/" + strings.TrimSuffix(rest, path.Ext(rest))
+}
+
+// GetResumePreview returns the preview of the resume of a submission, rendering and caching it on first use.
+// A revision of 0 is the current resume.
+func GetResumePreview(ctx context.Context, submissionID int, revision int) (*models.ResumePreview, error) {
+	resume, err := getSubmissionResume(ctx, submissionID, revision)
+	if err != nil {
+		return nil, err
+	}
+	store, err := GetStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing or unreadable cache entry is rendered again
+	cached, err := loadCachedPreview(ctx, store, resume.Key)
+	if err != nil {
+		cached, err = renderResumePreview(ctx, store, resume)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	preview := &models.ResumePreview{
+		JobSubmissionID: submissionID,
+		Revision:        revision,
+		HTML:            cached.HTML,
+	}
+	if cached.ThumbnailKey != "" {
+		preview.ThumbnailURL, err = SignedResumeURL(ctx, cached.ThumbnailKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return preview, nil
+}
+
+func loadCachedPreview(ctx context.Context, store storage.Storage, resumeKey string) (*cachedPreview, error) {
+	file, _, err := store.Get(ctx, previewKey(resumeKey))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var cached cachedPreview
+	if err := json.NewDecoder(file).Decode(&cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+// renderResumePreview builds the preview of a stored resume and caches it in the storage
+func renderResumePreview(ctx context.Context, store storage.Storage, resume *storedResume) (*cachedPreview, error) {
+	file, _, err := store.Get(ctx, resume.Key)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, ErrResumeNotFound
+		}
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := resume.FileName
+	if fileName == "" {
+		fileName = resume.Key
+	}
+	// Scanned resumes have no text, they may still get a thumbnail
+	text, err := extractText(data, fileName)
+	if err != nil && err != ErrUnsupportedResumeFormat {
+		return nil, err
+	}
+	cached := &cachedPreview{HTML: resumeHTML(text)}
+
+	thumbnail, ext := renderThumbnail(ctx, data)
+	if thumbnail != nil {
+		cached.ThumbnailKey = strings.TrimSuffix(previewKey(resume.Key), ".json") + ext
+		err = store.Put(ctx, cached.ThumbnailKey, bytes.NewReader(thumbnail), storage.PutOptions{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := json.Marshal(cached)
+	if err != nil {
+		return nil, err
+	}
+	err = store.Put(ctx, previewKey(resume.Key), bytes.NewReader(body), storage.PutOptions{ContentType: "application/json"})
+	if err != nil {
+		return nil, err
+	}
+	return cached, nil
+}
+
+// resumeHTML renders extracted text as paragraphs. Every line is escaped, so markup in the resume is shown as text.
+func resumeHTML(text string) string {
+	var out strings.Builder
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(paragraph), "\n")
+		if lines[0] == "" {
+			continue
+		}
+		for i, line := range lines {
+			lines[i] = html.EscapeString(line)
+		}
+		out.WriteString("<p>" + strings.Join(lines, "<br>") + "</p>\n")
+	}
+	return out.String()
+}
+
+// renderThumbnail returns an image of the first page and its extension, or nil when none can be made.
+// DOCX files usually embed one, PDFs are rendered when pdftoppm is installed.
+func renderThumbnail(ctx context.Context, data []byte) ([]byte, string) {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		thumbnail, err := renderPDFThumbnail(ctx, data)
+		if err != nil {
+			log.Printf("Failed to render PDF thumbnail: %v", err)
+		}
+		if thumbnail == nil {
+			return nil, ""
+		}
+		return thumbnail, ".png"
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return docxThumbnail(data)
+	}
+	return nil, ""
+}
+
+// docxThumbnail returns the first page image Word saves in docProps
+func docxThumbnail(data []byte) ([]byte, string) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ""
+	}
+	for _, f := range archive.File {
+		ext := strings.ToLower(path.Ext(f.Name))
+		if !strings.HasPrefix(f.Name, "docProps/thumbnail.") || (ext != ".png" && ext != ".jpeg" && ext != ".jpg") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, ""
+		}
+		thumbnail, err := io.ReadAll(io.LimitReader(rc, maxResumeSize()))
+		rc.Close()
+		if err != nil {
+			return nil, ""
+		}
+		return thumbnail, ext
+	}
+	return nil, ""
+}
+
+// renderPDFThumbnail renders the first page with pdftoppm, it returns no image when pdftoppm is not installed
+func renderPDFThumbnail(ctx context.Context, data []byte) ([]byte, error) {
+	renderer := config.GetConfig().PDFRenderer
+	if renderer == "" {
+		return nil, nil
+	}
+	binary, err := exec.LookPath(renderer)
+	if err != nil {
+		return nil, nil
+	}
+
+	dir, err := os.MkdirTemp("", "resume-preview-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	input := filepath.Join(dir, "resume.pdf")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, thumbnailTimeout)
+	defer cancel()
+	output := filepath.Join(dir, "page")
+	cmd := exec.CommandContext(ctx, binary, "-png", "-f", "1", "-l", "1", "-scale-to", "600", "-singlefile", input, output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return os.ReadFile(output + ".png")
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"archive/zip"
+	"backend/internal/config"
+	"backend/internal/storage"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeHTML(t *testing.T) {
+	text := "Jane Doe\nGo developer\n\n<script>alert(1)</script> & <b>bold</b>"
+
+	assert.Equal(t, "<p>Jane Doe<br>Go developer</p>\n"+
+		"<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp; &lt;b&gt;bold&lt;/b&gt;</p>\n", resumeHTML(text))
+	assert.Equal(t, "", resumeHTML(""))
+}
+
+func TestPreviewResumeKey(t *testing.T) {
+	resumeKey := "resumes/acme/j_1/0c9e.pdf"
+	key := previewKey(resumeKey)
+	assert.Equal(t, "previews/acme/j_1/0c9e.pdf.json", key)
+	assert.Equal(t, resumeKey, previewResumeKey(key))
+	assert.Equal(t, resumeKey, previewResumeKey("previews/acme/j_1/0c9e.pdf.png"))
+}
+
+func TestRenderResumePreview(t *testing.T) {
+	require.NoError(t, config.LoadConfig())
+	store := storage.NewMemoryStorage(&storage.URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("test")})
+	ctx := context.Background()
+
+	// DOCX with the first page image Word saves
+	var docx bytes.Buffer
+	archive := zip.NewWriter(&docx)
+	f, _ := archive.Create("word/document.xml")
+	f.Write([]byte(`<w:document><w:body><w:p><w:r><w:t>Jane &lt;Doe&gt;</w:t></w:r></w:p></w:body></w:document>`))
+	f, _ = archive.Create("docProps/thumbnail.jpeg")
+	f.Write([]byte("jpeg"))
+	require.NoError(t, archive.Close())
+	resume := &storedResume{Key: "resumes/acme/j_1/0c9e.docx", FileName: "cv.docx"}
+	store.Put(ctx, resume.Key, bytes.NewReader(docx.Bytes()), storage.PutOptions{})
+
+	preview, err := renderResumePreview(ctx, store, resume)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Jane &lt;Doe&gt;</p>\n", preview.HTML)
+	assert.Equal(t, "previews/acme/j_1/0c9e.docx.jpeg", preview.ThumbnailKey)
+
+	// The preview is cached next to the thumbnail
+	cached, err := loadCachedPreview(ctx, store, resume.Key)
+	require.NoError(t, err)
+	assert.Equal(t, preview, cached)
+	assert.ElementsMatch(t, []string{resume.Key, "previews/acme/j_1/0c9e.docx.json", "previews/acme/j_1/0c9e.docx.jpeg"}, store.Keys())
+
+	// Plain text resumes have no thumbnail
+	resume = &storedResume{Key: "resumes/acme/j_1/7b1f.txt", FileName: "cv.txt"}
+	store.Put(ctx, resume.Key, strings.NewReader("Jane Doe"), storage.PutOptions{})
+	preview, err = renderResumePreview(ctx, store, resume)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Jane Doe</p>\n", preview.HTML)
+	assert.Empty(t, preview.ThumbnailKey)
+
+	_, err = renderResumePreview(ctx, store, &storedResume{Key: "resumes/acme/j_1/missing.pdf"})
+	assert.Equal(t, ErrResumeNotFound, err)
+}
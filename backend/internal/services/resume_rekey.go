@@ -0,0 +1,175 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/storage"
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+)
+
+// ResumeRekeyResult summarizes a run of RekeyResumes
+type ResumeRekeyResult struct {
+	Keys    int // old keys found
+	Rekeyed int // submissions and revisions moved to a key of their own
+	Shared  int // old keys used by more than one submission, only the last upload survived in storage
+	Missing int // old keys without a stored file, left unchanged
+	Deleted int // old objects removed after moving
+}
+
+// resumeReference is a submission or an archived revision pointing at a resume
+type resumeReference struct {
+	Table        string `db:"source_table"`
+	ID           int    `db:"id"`
+	SubmissionID int    `db:"job_submission_id"`
+	JobID        string `db:"job_id"`
+	CompanyName  string `db:"company_name"`
+	Key          string `db:"resume_key"`
+	FileName     string `db:"resume_file_name"`
+}
+
+// RekeyResumes moves resumes stored under the old shared keys (resumes/0_<username>.<ext>) to
+// a unique key per submission in the folder of the company's job. Every submission and revision
+// gets its own copy, the old object is deleted once nothing references it.
+// With dryRun nothing is changed, the result tells what would be moved.
+func RekeyResumes(ctx context.Context, dryRun bool) (*ResumeRekeyResult, error) {
+	db := database.GetDB()
+	store, err := GetStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	var references []resumeReference
+	err = db.SelectContext(ctx, &references, `
+		SELECT 'job_submissions' AS source_table, js.id, js.id AS job_submission_id, js.job_id, u.company_name, js.resume_key, js.resume_file_name
+		FROM job_submissions js
+		JOIN application_form af ON js.form_uuid = af.form_uuid
+		JOIN jobs j ON af.job_id = j.id
+		JOIN users u ON j.user_id = u.id
+		WHERE js.resume_key <> ''
+		UNION ALL
+		SELECT 'job_submission_history', h.id, h.job_submission_id, js.job_id, u.company_name, h.resume_key, h.resume_file_name
+		FROM job_submission_history h
+		JOIN job_submissions js ON h.job_submission_id = js.id
+		JOIN application_form af ON js.form_uuid = af.form_uuid
+		JOIN jobs j ON af.job_id = j.id
+		JOIN users u ON j.user_id = u.id
+		WHERE h.resume_key <> ''
+		ORDER BY resume_key, source_table, id`)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the references needing a new key by their old key
+	var oldKeys []string
+	byKey := map[string][]resumeReference{}
+	for _, ref := range references {
+		if strings.HasPrefix(ref.Key, resumeKeyPrefix(ref.CompanyName, ref.JobID)) {
+			continue
+		}
+		if _, ok := byKey[ref.Key]; !ok {
+			oldKeys = append(oldKeys, ref.Key)
+		}
+		byKey[ref.Key] = append(byKey[ref.Key], ref)
+	}
+
+	result := &ResumeRekeyResult{Keys: len(oldKeys)}
+	for _, oldKey := range oldKeys {
+		refs := byKey[oldKey]
+		if countSubmissions(refs) > 1 {
+			result.Shared++
+			log.Printf("Warning: %s is shared by %d submissions, each gets a copy of the last upload", oldKey, countSubmissions(refs))
+		}
+
+		if err := rekeyResume(ctx, store, oldKey, refs, dryRun, result); err != nil {
+			return result, fmt.Errorf("failed to re-key %s: %w", oldKey, err)
+		}
+	}
+	return result, nil
+}
+
+// countSubmissions returns the number of different submissions among the references
+func countSubmissions(refs []resumeReference) int {
+	submissions := map[int]bool{}
+	for _, ref := range refs {
+		submissions[ref.SubmissionID] = true
+	}
+	return len(submissions)
+}
+
+// rekeyResume copies one old object to a new key for each reference and deletes it afterwards
+func rekeyResume(ctx context.Context, store storage.Storage, oldKey string, refs []resumeReference, dryRun bool, result *ResumeRekeyResult) error {
+	db := database.GetDB()
+
+	file, info, err := store.Get(ctx, oldKey)
+	if err == storage.ErrNotFound {
+		result.Missing++
+		log.Printf("Warning: %s is not in the storage, %d references left unchanged", oldKey, len(refs))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	file.Close()
+
+	for _, ref := range refs {
+		// The old key only tells the extension, the original name is unknown for old uploads
+		newKey := newResumeKey(ref.CompanyName, ref.JobID, path.Base(oldKey))
+		if dryRun {
+			log.Printf("Would move %s of %s %d to %s", oldKey, ref.Table, ref.ID, newKey)
+			result.Rekeyed++
+			continue
+		}
+
+		file, _, err := store.Get(ctx, oldKey)
+		if err != nil {
+			return err
+		}
+		opts := storage.PutOptions{ContentType: info.ContentType}
+		if ref.FileName != "" {
+			opts.Metadata = map[string]string{storage.MetadataOriginalFilename: ref.FileName}
+		}
+		err = store.Put(ctx, newKey, file, opts)
+		file.Close()
+		if err != nil {
+			return err
+		}
+
+		// Only move the reference if nobody changed it in the meantime
+		updated, err := db.ExecContext(ctx,
+			fmt.Sprintf("UPDATE %s SET resume_key = $1 WHERE id = $2 AND resume_key = $3", ref.Table),
+			newKey, ref.ID, oldKey)
+		if err != nil {
+			store.Delete(ctx, newKey)
+			return err
+		}
+		if rows, _ := updated.RowsAffected(); rows == 0 {
+			store.Delete(ctx, newKey)
+			continue
+		}
+		result.Rekeyed++
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	var referenced bool
+	err = db.GetContext(ctx, &referenced, `
+		SELECT EXISTS (SELECT 1 FROM job_submissions WHERE resume_key = $1)
+			OR EXISTS (SELECT 1 FROM job_submission_history WHERE resume_key = $1)`, oldKey)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		log.Printf("Warning: %s is still referenced, not deleted", oldKey)
+		return nil
+	}
+	if err := store.Delete(ctx, oldKey); err != nil {
+		return err
+	}
+	result.Deleted++
+	return nil
+}
@@ -0,0 +1,599 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var ErrUnsupportedResumeFormat = errors.New("resume format is not supported for text extraction")
+
+// maxResumeTextLength caps the extracted text stored with a submission
+const maxResumeTextLength = 100000
+
+// maxDecodedResumeSize caps the decompressed PDF streams or DOCX document read from one resume,
+// so a small compressed upload cannot expand into gigabytes. Text beyond it is left out.
+const maxDecodedResumeSize = 20 * maxResumeTextLength
+
+// maxCMapEntries caps the character codes the ToUnicode ranges of a PDF may expand into
+const maxCMapEntries = 0x10000
+
+// maxCMapCodeLength is the length in hex digits of the longest character code, codes have at most four bytes
+const maxCMapCodeLength = 8
+
+// ExtractResumeText returns the plain text of an uploaded PDF, DOCX or TXT resume
+func ExtractResumeText(file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxResumeSize()))
+	if err != nil {
+		return "", err
+	}
+	return extractText(data, file.Filename)
+}
+
+// extractText detects the document format from its content, falling back to the file extension
+func extractText(data []byte, fileName string) (string, error) {
+	var text string
+	var err error
+
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF")):
+		text, err = extractPDFText(data)
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		text, err = extractDOCXText(data)
+	case strings.EqualFold(filepath.Ext(fileName), ".txt") && utf8.Valid(data):
+		text = string(data)
+	default:
+		return "", ErrUnsupportedResumeFormat
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return truncateText(normalizeExtractedText(text), maxResumeTextLength), nil
+}
+
+// normalizeExtractedText collapses runs of spaces and blank lines
+func normalizeExtractedText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	normalized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && (len(normalized) == 0 || normalized[len(normalized)-1] == "") {
+			continue
+		}
+		normalized = append(normalized, line)
+	}
+	return strings.TrimSpace(strings.Join(normalized, "\n"))
+}
+
+func truncateText(text string, maxLength int) string {
+	if len(text) <= maxLength {
+		return text
+	}
+	text = text[:maxLength]
+	// Do not cut a multi-byte character in half
+	for !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+// extractDOCXText reads the paragraphs of word/document.xml
+func extractDOCXText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range archive.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+		return parseDOCXDocument(&io.LimitedReader{R: rc, N: maxDecodedResumeSize})
+	}
+	return "", ErrUnsupportedResumeFormat
+}
+
+// parseDOCXDocument reads the text of a document cut off at the limit of r, the text before the cut is kept
+func parseDOCXDocument(r *io.LimitedReader) (string, error) {
+	var text strings.Builder
+	decoder := xml.NewDecoder(r)
+	inText := false
+
+	for text.Len() <= maxResumeTextLength {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if r.N <= 0 {
+				break
+			}
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteString("\t")
+			case "br", "cr":
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	return text.String(), nil
+}
+
+var (
+	pdfStreamPattern = regexp.MustCompile(`(?s)<<(.*?)>>\s*stream\r?\n`)
+	pdfBFCharPattern = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>`)
+	pdfBFRangeLine   = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>`)
+)
+
+// extractPDFText extracts the text shown by the content streams of a PDF.
+// It handles uncompressed and Flate compressed streams and uses the ToUnicode maps of the
+// embedded fonts when present, which covers the PDFs produced by common word processors.
+func extractPDFText(data []byte) (string, error) {
+	streams := pdfStreams(data)
+
+	// ToUnicode maps are merged, fonts of a resume rarely disagree on a code
+	cmap := newPDFCMap()
+	for _, stream := range streams {
+		if bytes.Contains(stream, []byte("begincmap")) {
+			cmap.parse(stream)
+		}
+	}
+
+	var text strings.Builder
+	for _, stream := range streams {
+		if bytes.Contains(stream, []byte("begincmap")) {
+			continue
+		}
+		text.WriteString(pdfContentText(stream, cmap))
+	}
+
+	if strings.TrimSpace(text.String()) == "" {
+		return "", ErrUnsupportedResumeFormat
+	}
+	return text.String(), nil
+}
+
+// pdfStreams returns the decoded streams that may contain text or character maps.
+// Decompression stops once maxDecodedResumeSize bytes were decoded in total.
+func pdfStreams(data []byte) [][]byte {
+	streams := [][]byte{}
+	budget := int64(maxDecodedResumeSize)
+	for _, match := range pdfStreamPattern.FindAllSubmatchIndex(data, -1) {
+		dict := data[match[2]:match[3]]
+		start := match[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			continue
+		}
+		raw := data[start : start+end]
+
+		// Images, fonts and object streams carry no readable text
+		if bytes.Contains(dict, []byte("/Image")) || bytes.Contains(dict, []byte("/FontFile")) ||
+			bytes.Contains(dict, []byte("/Length1")) || bytes.Contains(dict, []byte("/ObjStm")) ||
+			bytes.Contains(dict, []byte("/XRef")) {
+			continue
+		}
+
+		if bytes.Contains(dict, []byte("/Filter")) {
+			if !bytes.Contains(dict, []byte("/FlateDecode")) {
+				continue
+			}
+			if budget <= 0 {
+				break
+			}
+			decoded, err := io.ReadAll(io.LimitReader(zlibReader(raw), budget))
+			if len(decoded) == 0 && err != nil {
+				continue
+			}
+			budget -= int64(len(decoded))
+			raw = decoded
+		}
+		streams = append(streams, raw)
+	}
+	return streams
+}
+
+func zlibReader(raw []byte) io.Reader {
+	r, err := zlib.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return bytes.NewReader(nil)
+	}
+	return r
+}
+
+// pdfCMap maps character codes (as upper case hex) to unicode text
+type pdfCMap struct {
+	codes map[string]string
+	// twoByte is set when the map was built from CID fonts using two byte codes
+	twoByte bool
+}
+
+func newPDFCMap() *pdfCMap {
+	return &pdfCMap{codes: map[string]string{}}
+}
+
+// set maps a code unless the map is full
+func (m *pdfCMap) set(code string, text string) bool {
+	if _, ok := m.codes[code]; !ok && len(m.codes) >= maxCMapEntries {
+		return false
+	}
+	m.codes[code] = text
+	if len(code) == 4 {
+		m.twoByte = true
+	}
+	return true
+}
+
+func (m *pdfCMap) parse(stream []byte) {
+	for _, section := range pdfSections(stream, "beginbfchar", "endbfchar") {
+		for _, match := range pdfBFCharPattern.FindAllSubmatch(section, -1) {
+			if len(match[1]) > maxCMapCodeLength {
+				continue
+			}
+			if !m.set(strings.ToUpper(string(match[1])), decodeUTF16Hex(string(match[2]))) {
+				return
+			}
+		}
+	}
+	for _, section := range pdfSections(stream, "beginbfrange", "endbfrange") {
+		for _, match := range pdfBFRangeLine.FindAllSubmatch(section, -1) {
+			low, high, target := string(match[1]), string(match[2]), string(match[3])
+			if len(low) > maxCMapCodeLength || len(high) > maxCMapCodeLength {
+				continue
+			}
+			lowValue, highValue, targetValue := hexValue(low), hexValue(high), hexValue(target)
+			if highValue < lowValue || highValue-lowValue > 0xFFFF {
+				continue
+			}
+			for code := lowValue; code <= highValue; code++ {
+				if !m.set(formatHex(code, len(low)), string(rune(targetValue+code-lowValue))) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// decode converts the bytes of a PDF string to text
+func (m *pdfCMap) decode(raw []byte) string {
+	if bytes.HasPrefix(raw, []byte{0xFE, 0xFF}) {
+		return decodeUTF16Hex(hex.EncodeToString(raw[2:]))
+	}
+
+	// Strings of simple fonts fall back to single byte decoding when a code is not mapped
+	if m.twoByte && len(raw)%2 == 0 {
+		var text strings.Builder
+		mapped := true
+		for i := 0; i < len(raw) && mapped; i += 2 {
+			var value string
+			value, mapped = m.codes[strings.ToUpper(hex.EncodeToString(raw[i:i+2]))]
+			text.WriteString(value)
+		}
+		if mapped {
+			return text.String()
+		}
+	}
+
+	var text strings.Builder
+	for _, b := range raw {
+		if mapped, ok := m.codes[strings.ToUpper(hex.EncodeToString([]byte{b}))]; ok {
+			text.WriteString(mapped)
+		} else {
+			// PDFDocEncoding matches Latin-1 for printable characters
+			text.WriteRune(rune(b))
+		}
+	}
+	return text.String()
+}
+
+func pdfSections(stream []byte, begin string, end string) [][]byte {
+	sections := [][]byte{}
+	rest := stream
+	for {
+		start := bytes.Index(rest, []byte(begin))
+		if start < 0 {
+			return sections
+		}
+		rest = rest[start+len(begin):]
+		stop := bytes.Index(rest, []byte(end))
+		if stop < 0 {
+			return sections
+		}
+		sections = append(sections, rest[:stop])
+		rest = rest[stop+len(end):]
+	}
+}
+
+// pdfContentText interprets the text operators of a content stream
+func pdfContentText(stream []byte, cmap *pdfCMap) string {
+	var text strings.Builder
+	var operands []pdfToken
+	newLine := func() {
+		if text.Len() > 0 && !strings.HasSuffix(text.String(), "\n") {
+			text.WriteString("\n")
+		}
+	}
+
+	for _, token := range tokenizePDFContent(stream) {
+		if !token.operator {
+			operands = append(operands, token)
+			continue
+		}
+
+		switch token.value {
+		case "Tj", "'", "\"":
+			if token.value != "Tj" {
+				newLine()
+			}
+			for _, operand := range operands {
+				if operand.str != nil {
+					text.WriteString(cmap.decode(operand.str))
+				}
+			}
+		case "TJ":
+			for _, operand := range operands {
+				if operand.str != nil {
+					text.WriteString(cmap.decode(operand.str))
+				} else if operand.number < -200 {
+					// A large negative adjustment inside TJ is a word gap
+					text.WriteString(" ")
+				}
+			}
+		case "T*", "ET", "Tm":
+			newLine()
+		case "Td", "TD":
+			if len(operands) == 2 && operands[1].number != 0 {
+				newLine()
+			} else {
+				text.WriteString(" ")
+			}
+		}
+		operands = operands[:0]
+	}
+	return text.String()
+}
+
+// pdfToken is an operand or operator of a content stream
+type pdfToken struct {
+	operator bool
+	value    string
+	str      []byte // decoded bytes of a string operand
+	number   float64
+}
+
+func tokenizePDFContent(stream []byte) []pdfToken {
+	tokens := []pdfToken{}
+	for i := 0; i < len(stream); {
+		c := stream[i]
+		switch {
+		case isPDFWhitespace(c) || c == '[' || c == ']':
+			i++
+		case c == '%':
+			for i < len(stream) && stream[i] != '\n' && stream[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			str, next := readPDFLiteralString(stream, i)
+			tokens = append(tokens, pdfToken{str: str})
+			i = next
+		case c == '<' && i+1 < len(stream) && stream[i+1] == '<':
+			// Skip inline dictionaries, e.g. marked content properties
+			end := bytes.Index(stream[i:], []byte(">>"))
+			if end < 0 {
+				return tokens
+			}
+			i += end + 2
+		case c == '<':
+			end := bytes.IndexByte(stream[i:], '>')
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, pdfToken{str: decodePDFHexString(stream[i+1 : i+end])})
+			i += end + 1
+		case c == '/':
+			start := i
+			i++
+			for i < len(stream) && !isPDFWhitespace(stream[i]) && !isPDFDelimiter(stream[i]) {
+				i++
+			}
+			tokens = append(tokens, pdfToken{value: string(stream[start:i])})
+		default:
+			start := i
+			for i < len(stream) && !isPDFWhitespace(stream[i]) && !isPDFDelimiter(stream[i]) {
+				i++
+			}
+			if i == start {
+				i++
+				continue
+			}
+			word := string(stream[start:i])
+			if number, ok := parsePDFNumber(word); ok {
+				tokens = append(tokens, pdfToken{value: word, number: number})
+			} else {
+				tokens = append(tokens, pdfToken{operator: true, value: word})
+			}
+		}
+	}
+	return tokens
+}
+
+func readPDFLiteralString(stream []byte, start int) ([]byte, int) {
+	var str []byte
+	depth := 0
+	for i := start; i < len(stream); i++ {
+		c := stream[i]
+		switch c {
+		case '(':
+			if depth > 0 {
+				str = append(str, c)
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return str, i + 1
+			}
+			str = append(str, c)
+		case '\\':
+			i++
+			if i >= len(stream) {
+				return str, i
+			}
+			switch e := stream[i]; e {
+			case 'n':
+				str = append(str, '\n')
+			case 'r':
+				str = append(str, '\r')
+			case 't':
+				str = append(str, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					value := 0
+					for j := 0; j < 3 && i < len(stream) && stream[i] >= '0' && stream[i] <= '7'; j++ {
+						value = value*8 + int(stream[i]-'0')
+						i++
+					}
+					i--
+					str = append(str, byte(value))
+				} else {
+					str = append(str, e)
+				}
+			}
+		default:
+			str = append(str, c)
+		}
+	}
+	return str, len(stream)
+}
+
+func decodePDFHexString(raw []byte) []byte {
+	digits := make([]byte, 0, len(raw))
+	for _, c := range raw {
+		if !isPDFWhitespace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	decoded, err := hex.DecodeString(string(digits))
+	if err != nil {
+		return nil
+	}
+	return decoded
+}
+
+func parsePDFNumber(word string) (float64, bool) {
+	var number float64
+	var fraction float64
+	sign := 1.0
+	seenDigit := false
+	for i, c := range word {
+		switch {
+		case (c == '-' || c == '+') && i == 0:
+			if c == '-' {
+				sign = -1
+			}
+		case c == '.' && fraction == 0:
+			fraction = 1
+		case c >= '0' && c <= '9':
+			seenDigit = true
+			if fraction > 0 {
+				fraction /= 10
+				number += float64(c-'0') * fraction
+			} else {
+				number = number*10 + float64(c-'0')
+			}
+		default:
+			return 0, false
+		}
+	}
+	return sign * number, seenDigit
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func decodeUTF16Hex(value string) string {
+	raw, err := hex.DecodeString(value)
+	if err != nil || len(raw)%2 == 1 {
+		return ""
+	}
+	units := make([]uint16, len(raw)/2)
+	for i := range units {
+		units[i] = uint16(raw[2*i])<<8 | uint16(raw[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+func hexValue(value string) int {
+	result := 0
+	for _, c := range strings.ToUpper(value) {
+		result *= 16
+		switch {
+		case c >= '0' && c <= '9':
+			result += int(c - '0')
+		case c >= 'A' && c <= 'F':
+			result += int(c-'A') + 10
+		}
+	}
+	return result
+}
+
+func formatHex(value int, width int) string {
+	const digits = "0123456789ABCDEF"
+	out := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		out[i] = digits[value&0xF]
+		value >>= 4
+	}
+	return string(out)
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildPDF wraps a content stream into a minimal PDF document
+func buildPDF(content []byte, compress bool, extraObjects ...string) []byte {
+	filter := ""
+	if compress {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		w.Write(content)
+		w.Close()
+		content = buf.Bytes()
+		filter = " /Filter /FlateDecode"
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	pdf.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	pdf.WriteString("2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n")
+	pdf.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>\nendobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj\n<< /Length %d%s >>\nstream\n", len(content), filter)
+	pdf.Write(content)
+	pdf.WriteString("\nendstream\nendobj\n")
+	for _, object := range extraObjects {
+		pdf.WriteString(object)
+	}
+	pdf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func buildDOCX(t *testing.T, documentXML string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("word/document.xml")
+	assert.NoError(t, err)
+	f.Write([]byte(documentXML))
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestExtractText(t *testing.T) {
+	content := []byte(`BT /F1 12 Tf 72 720 Td (Jane Doe) Tj 0 -14 Td [(Senior ) -50 (Go) -300 (Engineer)] TJ ET
+BT 72 680 Td (Skills: Kubernetes\051 \(k8s\)) Tj ET`)
+
+	cmap := `5 0 obj
+<< /Length 200 >>
+stream
+/CIDInit /ProcSet findresource begin
+begincmap
+2 beginbfchar
+<0001> <0048>
+<0002> <0069>
+endbfchar
+1 beginbfrange
+<0010> <0012> <0041>
+endbfrange
+endcmap
+endstream
+endobj
+`
+
+	testCases := []struct {
+		name        string
+		data        []byte
+		fileName    string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "Uncompressed PDF",
+			data:     buildPDF(content, false),
+			fileName: "resume.pdf",
+			expected: "Jane Doe\nSenior Go Engineer\nSkills: Kubernetes) (k8s)",
+		},
+		{
+			name:     "Compressed PDF",
+			data:     buildPDF(content, true),
+			fileName: "resume.pdf",
+			expected: "Jane Doe\nSenior Go Engineer\nSkills: Kubernetes) (k8s)",
+		},
+		{
+			name:     "PDF with ToUnicode map",
+			data:     buildPDF([]byte(`BT /F1 12 Tf <00010002> Tj 0 -14 Td <001000110012> Tj ET`), true, cmap),
+			fileName: "resume.pdf",
+			expected: "Hi\nABC",
+		},
+		{
+			name: "DOCX",
+			data: buildDOCX(t, `<?xml version="1.0"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Jane</w:t></w:r><w:r><w:t xml:space="preserve"> Doe</w:t></w:r></w:p>
+<w:p><w:r><w:t>Go</w:t><w:tab/><w:t>PostgreSQL</w:t></w:r></w:p>
+</w:body></w:document>`),
+			fileName: "resume.docx",
+			expected: "Jane Doe\nGo PostgreSQL",
+		},
+		{
+			name:     "Plain text",
+			data:     []byte("Jane Doe\r\n\r\n\r\nGo   developer  "),
+			fileName: "resume.TXT",
+			expected: "Jane Doe\n\nGo developer",
+		},
+		{
+			name:        "Legacy Word document",
+			data:        []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1},
+			fileName:    "resume.doc",
+			expectedErr: ErrUnsupportedResumeFormat,
+		},
+		{
+			name:        "PDF without text",
+			data:        buildPDF([]byte("0 0 m 100 100 l S"), false),
+			fileName:    "scan.pdf",
+			expectedErr: ErrUnsupportedResumeFormat,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			text, err := extractText(tc.data, tc.fileName)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestDefaultATSScorerUsesResumeText(t *testing.T) {
+	breakdown := NewDefaultATSScorer().Score(ATSScoreInput{
+		CandidateSkills: []string{"Go"},
+		ResumeText:      "Built services in Golang on K8S, deployed with Google tooling",
+		RequiredSkills:  []string{"Go", "Kubernetes", "Docker"},
+	})
+
+	criterion := breakdown.Criteria[0]
+	assert.Equal(t, []string{"Go", "Kubernetes"}, criterion.Matched)
+	assert.Equal(t, []string{"Docker"}, criterion.Missing)
+	assert.Equal(t, "2 of 3 skills matched, 1 found only in the resume", criterion.Detail)
+}
+
+func TestContainsTerm(t *testing.T) {
+	assert.True(t, containsTerm("go, sql", "go"))
+	assert.False(t, containsTerm("google", "go"))
+	assert.False(t, containsTerm("c++", "c"))
+	assert.True(t, containsTerm("i know c++.", "c++"))
+}
+
+func TestExtractTextLimitsDecompression(t *testing.T) {
+	// A few hundred KB of Flate data expanding to 8 MB of text operators
+	content := bytes.Repeat([]byte("BT (Go developer ) Tj ET\n"), 8<<20/25)
+	text, err := extractText(buildPDF(content, true), "resume.pdf")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(text, "Go developer"))
+	assert.LessOrEqual(t, len(text), maxResumeTextLength)
+
+	// Every range of a ToUnicode map could add 0xFFFF codes
+	var ranges strings.Builder
+	ranges.WriteString("begincmap\nbeginbfrange\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&ranges, "<%02X0000> <%02XFFFF> <0041>\n", i, i)
+	}
+	ranges.WriteString("endbfrange\nendcmap")
+	cmap := newPDFCMap()
+	cmap.parse([]byte(ranges.String()))
+	assert.Equal(t, maxCMapEntries, len(cmap.codes))
+
+	// Codes longer than four bytes are skipped instead of being kept as huge map keys
+	longCode := strings.Repeat("41", 1<<16)
+	cmap = newPDFCMap()
+	cmap.parse([]byte("beginbfchar\n<" + longCode + "> <0041>\n<41> <0042>\nendbfchar\n" +
+		"beginbfrange\n<" + longCode + "> <" + longCode + "> <0043>\nendbfrange"))
+	assert.Equal(t, map[string]string{"41": "B"}, cmap.codes)
+
+	// A document.xml cut off at the limit keeps the text before the cut
+	paragraphs := strings.Repeat("<w:p><w:r><w:t>Go developer</w:t></w:r></w:p>", 8<<20/46)
+	text, err = extractText(buildDOCX(t, `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`+
+		paragraphs+`</w:body></w:document>`), "resume.docx")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(text, "Go developer\nGo developer"))
+	assert.LessOrEqual(t, len(text), maxResumeTextLength)
+}
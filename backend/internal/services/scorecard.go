@@ -0,0 +1,299 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// defaultRatingScale is used for competencies created without a rating scale
+const defaultRatingScale = 5
+
+var (
+	ErrDuplicateCompetency = errors.New("the scorecard already has a competency with this name")
+	ErrUnknownCompetency   = errors.New("competency is not on the scorecard of the job")
+	ErrScorecardIncomplete = errors.New("every competency of the scorecard must be rated exactly once")
+	ErrRatingOutOfScale    = errors.New("rating is outside the rating scale of the competency")
+	ErrCommentRequired     = errors.New("a comment is required for this competency")
+	ErrFeedbackRequired    = errors.New("feedback is required when the job has no scorecard")
+)
+
+// jobCompetencies returns the ordered competencies of the scorecard of a job
+func jobCompetencies(ctx context.Context, q sqlx.QueryerContext, jobDBID int) ([]models.ScorecardCompetency, error) {
+	competencies := []models.ScorecardCompetency{}
+	err := sqlx.SelectContext(ctx, q, &competencies, `
+		SELECT id, name, description, rating_scale, comment_required, position
+		FROM scorecard_competencies
+		WHERE job_id = $1
+		ORDER BY position`, jobDBID)
+	return competencies, err
+}
+
+// GetScorecard returns the scorecard of a job of the current user
+func GetScorecard(ctx context.Context, jobID string) (*models.Scorecard, error) {
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	competencies, err := jobCompetencies(ctx, database.GetDB(), jobDBID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Scorecard{JobID: jobID, Competencies: competencies}, nil
+}
+
+// UpdateScorecard replaces the competencies of the scorecard of a job of the current user. Competencies
+// with an unchanged name keep their id, ratings already given keep the name and scale they were given with.
+func UpdateScorecard(ctx context.Context, jobID string, req models.UpdateScorecardRequest) (*models.Scorecard, error) {
+	jobDBID, err := getOwnedJobDBID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(req.Competencies))
+	seen := map[string]bool{}
+	for i, competency := range req.Competencies {
+		names[i] = strings.TrimSpace(competency.Name)
+		if seen[strings.ToLower(names[i])] {
+			return nil, ErrDuplicateCompetency
+		}
+		seen[strings.ToLower(names[i])] = true
+	}
+
+	tx, err := database.GetDB().BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM scorecard_competencies WHERE job_id = $1 AND NOT (name = ANY($2))",
+		jobDBID, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	for i, competency := range req.Competencies {
+		scale := competency.RatingScale
+		if scale == 0 {
+			scale = defaultRatingScale
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO scorecard_competencies (job_id, name, description, rating_scale, comment_required, position)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (job_id, name) DO UPDATE
+			SET description = EXCLUDED.description, rating_scale = EXCLUDED.rating_scale,
+				comment_required = EXCLUDED.comment_required, position = EXCLUDED.position`,
+			jobDBID, names[i], strings.TrimSpace(competency.Description), scale, competency.CommentRequired, i+1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	competencies, err := jobCompetencies(ctx, tx, jobDBID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &models.Scorecard{JobID: jobID, Competencies: competencies}, nil
+}
+
+// GetInterviewScorecard returns the scorecard of the job of an interview to its HR user and interviewers
+func GetInterviewScorecard(ctx context.Context, interviewID int) (*models.Scorecard, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	var job struct {
+		JobID   string `db:"job_id"`
+		JobDBID int    `db:"job_db_id"`
+	}
+	err := db.GetContext(ctx, &job, `
+		SELECT i.job_id, COALESCE(j.id, 0) AS job_db_id
+		FROM interviews i
+		LEFT JOIN jobs j ON j.job_id = i.job_id AND j.user_id = i.hr_user_id
+		WHERE i.id = $1 AND (i.hr_user_id = $2 OR i.interviewer_user_id = $2
+			OR EXISTS (SELECT 1 FROM interview_panelists p WHERE p.interview_id = i.id AND p.interviewer_user_id = $2))`,
+		interviewID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInterviewNotFound
+		}
+		return nil, err
+	}
+
+	competencies, err := jobCompetencies(ctx, db, job.JobDBID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Scorecard{JobID: job.JobID, Competencies: competencies}, nil
+}
+
+// checkScores verifies that feedback rates every competency of the scorecard once within its scale,
+// with the required comments. Jobs without a scorecard get free-text feedback.
+func checkScores(competencies []models.ScorecardCompetency, req *models.FeedbackRequest) error {
+	if len(competencies) == 0 {
+		if len(req.Scores) > 0 {
+			return ErrUnknownCompetency
+		}
+		if strings.TrimSpace(req.Feedback) == "" {
+			return ErrFeedbackRequired
+		}
+		return nil
+	}
+
+	byID := map[int]models.ScorecardCompetency{}
+	for _, competency := range competencies {
+		byID[competency.ID] = competency
+	}
+	rated := map[int]bool{}
+	for _, score := range req.Scores {
+		competency, ok := byID[score.CompetencyID]
+		if !ok {
+			return ErrUnknownCompetency
+		}
+		if rated[score.CompetencyID] {
+			return ErrScorecardIncomplete
+		}
+		rated[score.CompetencyID] = true
+		if score.Rating < 1 || score.Rating > competency.RatingScale {
+			return ErrRatingOutOfScale
+		}
+		if competency.CommentRequired && strings.TrimSpace(score.Comment) == "" {
+			return ErrCommentRequired
+		}
+	}
+	if len(rated) != len(competencies) {
+		return ErrScorecardIncomplete
+	}
+	return nil
+}
+
+// saveScores replaces the ratings of an interviewer for an interview
+func saveScores(ctx context.Context, tx *sqlx.Tx, interviewID int, interviewerID int, competencies []models.ScorecardCompetency, scores []models.ScoreRequest) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM interview_scores WHERE interview_id = $1 AND interviewer_user_id = $2",
+		interviewID, interviewerID)
+	if err != nil {
+		return err
+	}
+
+	byID := map[int]models.ScorecardCompetency{}
+	for _, competency := range competencies {
+		byID[competency.ID] = competency
+	}
+	for _, score := range scores {
+		competency := byID[score.CompetencyID]
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO interview_scores (interview_id, interviewer_user_id, competency_id, competency_name, rating, rating_scale, comment)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			interviewID, interviewerID, competency.ID, competency.Name, score.Rating, competency.RatingScale, strings.TrimSpace(score.Comment))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// competencyRating is a rating of one interviewer with the competency it was given for
+type competencyRating struct {
+	models.InterviewerRating
+	CompetencyID *int   `db:"competency_id"`
+	Name         string `db:"name"`
+	RatingScale  int    `db:"rating_scale"`
+}
+
+// GetSubmissionScorecard aggregates the scorecards and verdicts of all interviewers of a submission to a job
+// of the current user's company. Only HR sees them, interviewers rate independently. Cancelled interviews are left out.
+func GetSubmissionScorecard(ctx context.Context, submissionID int) (*models.CandidateScorecard, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID").(int)
+
+	var role string
+	if err := db.GetContext(ctx, &role, "SELECT role FROM users WHERE id = $1", userID); err != nil {
+		return nil, err
+	}
+	if role != "HR" {
+		return nil, ErrSubmissionNotFound
+	}
+	if _, err := getCompanySubmission(ctx, db, userID, submissionID); err != nil {
+		return nil, err
+	}
+
+	ratings := []competencyRating{}
+	err := db.SelectContext(ctx, &ratings, `
+		SELECT s.interview_id, s.interviewer_user_id, u.username AS interviewer_name, s.rating, s.comment,
+			s.competency_id, COALESCE(c.name, s.competency_name) AS name, s.rating_scale
+		FROM interview_scores s
+		JOIN interviews i ON s.interview_id = i.id
+		JOIN users u ON s.interviewer_user_id = u.id
+		LEFT JOIN scorecard_competencies c ON s.competency_id = c.id
+		WHERE i.job_submission_id = $1 AND i.status <> $2
+		ORDER BY c.position NULLS LAST, name, s.rating_scale, i.id, s.interviewer_user_id`,
+		submissionID, models.InterviewStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	verdicts := []string{}
+	err = db.SelectContext(ctx, &verdicts, `
+		SELECT verdict FROM interviews WHERE job_submission_id = $1 AND status <> $2
+		UNION ALL
+		SELECT p.verdict FROM interview_panelists p JOIN interviews i ON p.interview_id = i.id
+		WHERE i.job_submission_id = $1 AND i.status <> $2`,
+		submissionID, models.InterviewStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	scorecard := &models.CandidateScorecard{
+		JobSubmissionID: submissionID,
+		Verdict:         aggregateVerdict(verdicts),
+		Verdicts:        map[string]int{},
+		Competencies:    groupCompetencyScores(ratings),
+	}
+	for _, verdict := range verdicts {
+		scorecard.Verdicts[verdict]++
+	}
+	return scorecard, nil
+}
+
+// groupCompetencyScores groups ordered ratings by competency and averages them. Ratings of a removed
+// competency are grouped by name, ratings given on another scale are grouped separately.
+func groupCompetencyScores(ratings []competencyRating) []models.CompetencyScores {
+	type groupKey struct {
+		id    int
+		name  string
+		scale int
+	}
+	results := []models.CompetencyScores{}
+	index := map[groupKey]int{}
+	totals := []int{}
+	for _, r := range ratings {
+		key := groupKey{name: r.Name, scale: r.RatingScale}
+		if r.CompetencyID != nil {
+			key = groupKey{id: *r.CompetencyID, scale: r.RatingScale}
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(results)
+			index[key] = i
+			results = append(results, models.CompetencyScores{CompetencyID: r.CompetencyID, Name: r.Name, RatingScale: r.RatingScale})
+			totals = append(totals, 0)
+		}
+		results[i].Ratings = append(results[i].Ratings, r.InterviewerRating)
+		totals[i] += r.Rating
+	}
+
+	for i := range results {
+		average := float64(totals[i]) / float64(len(results[i].Ratings))
+		results[i].AverageRating = math.Round(average*100) / 100
+	}
+	return results
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"backend/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckScores(t *testing.T) {
+	competencies := []models.ScorecardCompetency{
+		{ID: 1, Name: "Problem solving", RatingScale: 5},
+		{ID: 2, Name: "Communication", RatingScale: 3, CommentRequired: true},
+	}
+	feedback := func(scores ...models.ScoreRequest) *models.FeedbackRequest {
+		return &models.FeedbackRequest{Verdict: models.InterviewVerdictYes, Scores: scores}
+	}
+
+	assert.NoError(t, checkScores(competencies, feedback(
+		models.ScoreRequest{CompetencyID: 1, Rating: 5},
+		models.ScoreRequest{CompetencyID: 2, Rating: 1, Comment: "Hard to follow"},
+	)))
+	assert.Equal(t, ErrScorecardIncomplete, checkScores(competencies, feedback(
+		models.ScoreRequest{CompetencyID: 1, Rating: 4},
+	)))
+	assert.Equal(t, ErrScorecardIncomplete, checkScores(competencies, feedback(
+		models.ScoreRequest{CompetencyID: 1, Rating: 4},
+		models.ScoreRequest{CompetencyID: 1, Rating: 3},
+	)))
+	assert.Equal(t, ErrUnknownCompetency, checkScores(competencies, feedback(
+		models.ScoreRequest{CompetencyID: 3, Rating: 4},
+	)))
+	assert.Equal(t, ErrRatingOutOfScale, checkScores(competencies, feedback(
+		models.ScoreRequest{CompetencyID: 1, Rating: 4},
+		models.ScoreRequest{CompetencyID: 2, Rating: 4, Comment: "Clear"},
+	)))
+	assert.Equal(t, ErrCommentRequired, checkScores(competencies, feedback(
+		models.ScoreRequest{CompetencyID: 1, Rating: 4},
+		models.ScoreRequest{CompetencyID: 2, Rating: 2, Comment: " "},
+	)))
+
+	// Jobs without a scorecard get free-text feedback
+	assert.Equal(t, ErrFeedbackRequired, checkScores(nil, feedback()))
+	assert.NoError(t, checkScores(nil, &models.FeedbackRequest{Verdict: models.InterviewVerdictNo, Feedback: "Weak on SQL"}))
+	assert.Equal(t, ErrUnknownCompetency, checkScores(nil, feedback(models.ScoreRequest{CompetencyID: 1, Rating: 4})))
+}
+
+func TestGroupCompetencyScores(t *testing.T) {
+	solving := 1
+	rating := func(competencyID *int, name string, scale int, interviewerID int, value int) competencyRating {
+		return competencyRating{
+			InterviewerRating: models.InterviewerRating{InterviewerID: interviewerID, Rating: value},
+			CompetencyID:      competencyID,
+			Name:              name,
+			RatingScale:       scale,
+		}
+	}
+	scores := groupCompetencyScores([]competencyRating{
+		rating(&solving, "Problem solving", 5, 10, 4),
+		rating(&solving, "Problem solving", 5, 11, 5),
+		rating(&solving, "Problem solving", 5, 12, 4),
+		// Ratings given before the scale changed are kept apart
+		rating(&solving, "Problem solving", 3, 13, 3),
+		// Ratings of a removed competency are grouped by name
+		rating(nil, "Culture", 5, 10, 2),
+		rating(nil, "Culture", 5, 11, 3),
+	})
+
+	assert.Len(t, scores, 3)
+	assert.Equal(t, &solving, scores[0].CompetencyID)
+	assert.Len(t, scores[0].Ratings, 3)
+	assert.Equal(t, 4.33, scores[0].AverageRating)
+	assert.Equal(t, 3, scores[1].RatingScale)
+	assert.Equal(t, 3.0, scores[1].AverageRating)
+	assert.Nil(t, scores[2].CompetencyID)
+	assert.Equal(t, "Culture", scores[2].Name)
+	assert.Equal(t, 2.5, scores[2].AverageRating)
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSkillNotFound       = errors.New("skill not found")
+	ErrSkillNameRequired   = errors.New("skill name is required")
+	ErrSkillExists         = errors.New("skill name or alias is already used by another skill of your company")
+	ErrSkillParentNotFound = errors.New("parent skill not found")
+	ErrSkillParentCycle    = errors.New("a skill cannot be its own parent or the parent of its parent")
+	ErrSkillTaxonomyAccess = errors.New("only HR users can edit the skills taxonomy")
+)
+
+// maxSkillSuggestions caps the number of autocomplete results
+const maxSkillSuggestions = 50
+
+const skillColumns = `s.id, s.company_name, s.name, s.aliases, s.parent_id, COALESCE(p.name, '') AS parent_name, s.created_at, s.updated_at`
+
+func scanSkill(row rowScanner) (*models.Skill, error) {
+	var skill models.Skill
+	var aliases pq.StringArray
+	err := row.Scan(
+		&skill.ID,
+		&skill.CompanyName,
+		&skill.Name,
+		&aliases,
+		&skill.ParentID,
+		&skill.ParentName,
+		&skill.CreatedAt,
+		&skill.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	skill.Aliases = []string(aliases)
+	return &skill, nil
+}
+
+// getCompanySkills returns the taxonomy entries a company added, parents first
+func getCompanySkills(ctx context.Context, companyName string) ([]*models.Skill, error) {
+	db := database.GetDB()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+skillColumns+`
+		FROM skills s
+		LEFT JOIN skills p ON s.parent_id = p.id
+		WHERE s.company_name = $1
+		ORDER BY s.parent_id NULLS FIRST, LOWER(s.name)`, companyName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skills := []*models.Skill{}
+	for rows.Next() {
+		skill, err := scanSkill(rows)
+		if err != nil {
+			return nil, err
+		}
+		skills = append(skills, skill)
+	}
+	return skills, rows.Err()
+}
+
+// GetSkillTaxonomy returns the built-in taxonomy extended with the entries of the company
+func GetSkillTaxonomy(ctx context.Context, companyName string) (*SkillTaxonomy, error) {
+	skills, err := getCompanySkills(ctx, companyName)
+	if err != nil {
+		return nil, err
+	}
+
+	taxonomy := DefaultSkillTaxonomy().clone()
+	for _, skill := range skills {
+		taxonomy.add(skill.Name, skill.Aliases, skill.ParentName)
+	}
+	return taxonomy, nil
+}
+
+// getUserSkillTaxonomy returns the taxonomy of the company the user belongs to
+func getUserSkillTaxonomy(ctx context.Context, userID interface{}) (*SkillTaxonomy, error) {
+	companyName, err := getUserCompanyName(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return GetSkillTaxonomy(ctx, companyName)
+}
+
+// getFormSkillTaxonomy returns the taxonomy of the company that owns the job of an application form
+func getFormSkillTaxonomy(ctx context.Context, formUUID string) (*SkillTaxonomy, error) {
+	companyName, err := getFormCompanyName(ctx, formUUID)
+	if err != nil {
+		return nil, err
+	}
+	return GetSkillTaxonomy(ctx, companyName)
+}
+
+// getTaxonomyEditor returns the company of the current user if they may edit the taxonomy
+func getTaxonomyEditor(ctx context.Context) (string, error) {
+	db := database.GetDB()
+	userID := ctx.Value("userID")
+
+	var user struct {
+		Role        string `db:"role"`
+		CompanyName string `db:"company_name"`
+	}
+	if err := db.GetContext(ctx, &user, "SELECT role, company_name FROM users WHERE id = $1", userID); err != nil {
+		return "", err
+	}
+	if user.Role != "HR" {
+		return "", ErrSkillTaxonomyAccess
+	}
+	return user.CompanyName, nil
+}
+
+// ListSkills returns the taxonomy entries of the current user's company
+func ListSkills(ctx context.Context) ([]*models.Skill, error) {
+	companyName, err := getUserCompanyName(ctx, ctx.Value("userID"))
+	if err != nil {
+		return nil, err
+	}
+	return getCompanySkills(ctx, companyName)
+}
+
+// validateSkill checks that names and aliases are unique within the company and that the parent is valid
+func validateSkill(ctx context.Context, companyName string, skillID int, req *models.Skill) error {
+	db := database.GetDB()
+
+	terms := []string{skillKey(req.Name)}
+	for _, alias := range req.Aliases {
+		terms = append(terms, skillKey(alias))
+	}
+
+	var taken bool
+	err := db.GetContext(ctx, &taken, `
+		SELECT EXISTS(
+			SELECT 1 FROM skills
+			WHERE company_name = $1 AND id <> $2
+			AND (LOWER(name) = ANY($3) OR EXISTS(SELECT 1 FROM unnest(aliases) a WHERE LOWER(a) = ANY($3))))`,
+		companyName, skillID, pq.Array(terms))
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrSkillExists
+	}
+
+	if req.ParentID == nil {
+		return nil
+	}
+	if *req.ParentID == skillID {
+		return ErrSkillParentCycle
+	}
+
+	// Walk up from the new parent, reaching the skill itself would create a cycle
+	parentID := req.ParentID
+	for depth := 0; parentID != nil && depth < 100; depth++ {
+		var next *int
+		err := db.GetContext(ctx, &next, "SELECT parent_id FROM skills WHERE id = $1 AND company_name = $2", *parentID, companyName)
+		if err != nil {
+			if err == sql.ErrNoRows && depth == 0 {
+				return ErrSkillParentNotFound
+			}
+			return err
+		}
+		if next != nil && *next == skillID {
+			return ErrSkillParentCycle
+		}
+		parentID = next
+	}
+	return nil
+}
+
+// normalizeSkillRequest trims the name and drops empty or duplicate aliases
+func normalizeSkillRequest(req *models.Skill) {
+	req.Name = cleanSkillName(req.Name)
+	aliases := []string{}
+	seen := map[string]bool{skillKey(req.Name): true}
+	for _, alias := range req.Aliases {
+		alias = cleanSkillName(alias)
+		if key := skillKey(alias); key != "" && !seen[key] {
+			seen[key] = true
+			aliases = append(aliases, alias)
+		}
+	}
+	req.Aliases = aliases
+}
+
+// CreateSkill adds a skill to the taxonomy of the current user's company
+func CreateSkill(ctx context.Context, req *models.Skill) error {
+	db := database.GetDB()
+
+	companyName, err := getTaxonomyEditor(ctx)
+	if err != nil {
+		return err
+	}
+
+	normalizeSkillRequest(req)
+	if req.Name == "" {
+		return ErrSkillNameRequired
+	}
+	if err := validateSkill(ctx, companyName, 0, req); err != nil {
+		return err
+	}
+
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO skills (company_name, name, aliases, parent_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at`,
+		companyName, req.Name, pq.Array(req.Aliases), req.ParentID).Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrSkillExists
+		}
+		return err
+	}
+
+	req.CompanyName = companyName
+	return fillSkillParentName(ctx, req)
+}
+
+// UpdateSkill changes the name, aliases or parent of a skill of the current user's company
+func UpdateSkill(ctx context.Context, skillID int, req *models.Skill) error {
+	db := database.GetDB()
+
+	companyName, err := getTaxonomyEditor(ctx)
+	if err != nil {
+		return err
+	}
+
+	normalizeSkillRequest(req)
+	if req.Name == "" {
+		return ErrSkillNameRequired
+	}
+	if err := validateSkill(ctx, companyName, skillID, req); err != nil {
+		return err
+	}
+
+	err = db.QueryRowContext(ctx, `
+		UPDATE skills SET name = $1, aliases = $2, parent_id = $3, updated_at = NOW()
+		WHERE id = $4 AND company_name = $5
+		RETURNING id, created_at, updated_at`,
+		req.Name, pq.Array(req.Aliases), req.ParentID, skillID, companyName).Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrSkillNotFound
+		}
+		if isUniqueViolation(err) {
+			return ErrSkillExists
+		}
+		return err
+	}
+
+	req.CompanyName = companyName
+	return fillSkillParentName(ctx, req)
+}
+
+func fillSkillParentName(ctx context.Context, skill *models.Skill) error {
+	skill.ParentName = ""
+	if skill.ParentID == nil {
+		return nil
+	}
+	return database.GetDB().GetContext(ctx, &skill.ParentName, "SELECT name FROM skills WHERE id = $1", *skill.ParentID)
+}
+
+// DeleteSkill removes a skill from the taxonomy, its children lose their parent
+func DeleteSkill(ctx context.Context, skillID int) error {
+	db := database.GetDB()
+
+	companyName, err := getTaxonomyEditor(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.ExecContext(ctx, "DELETE FROM skills WHERE id = $1 AND company_name = $2", skillID, companyName)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrSkillNotFound
+	}
+	return nil
+}
+
+// AutocompleteSkills suggests canonical skill names of the current user's company for a partial input
+func AutocompleteSkills(ctx context.Context, query string, limit int) ([]models.SkillSuggestion, error) {
+	taxonomy, err := getUserSkillTaxonomy(ctx, ctx.Value("userID"))
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxSkillSuggestions {
+		limit = 10
+	}
+	return taxonomy.Suggest(query, limit), nil
+}
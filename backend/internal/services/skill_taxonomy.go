@@ -0,0 +1,271 @@
+package services
+
+import (
+	"backend/internal/models"
+	"sort"
+	"strings"
+)
+
+// defaultSkill is a built-in taxonomy entry, company entries take precedence over it
+type defaultSkill struct {
+	Name    string
+	Aliases []string
+	Parent  string
+}
+
+// defaultSkills covers common spellings so every company gets sensible matching out of the box
+var defaultSkills = []defaultSkill{
+	{Name: "Programming Languages"},
+	{Name: "Databases"},
+	{Name: "Cloud"},
+	{Name: "DevOps"},
+	{Name: "Frontend"},
+	{Name: "Data Science"},
+
+	{Name: "Go", Aliases: []string{"golang"}, Parent: "Programming Languages"},
+	{Name: "JavaScript", Aliases: []string{"js", "ecmascript"}, Parent: "Programming Languages"},
+	{Name: "TypeScript", Aliases: []string{"ts"}, Parent: "Programming Languages"},
+	{Name: "Python", Aliases: []string{"py"}, Parent: "Programming Languages"},
+	{Name: "Java", Parent: "Programming Languages"},
+	{Name: "C#", Aliases: []string{"c sharp", "csharp"}, Parent: "Programming Languages"},
+	{Name: "C++", Aliases: []string{"cpp"}, Parent: "Programming Languages"},
+	{Name: "Ruby", Parent: "Programming Languages"},
+	{Name: "Rust", Parent: "Programming Languages"},
+	{Name: "Kotlin", Parent: "Programming Languages"},
+	{Name: "PostgreSQL", Aliases: []string{"postgres", "psql"}, Parent: "Databases"},
+	{Name: "MySQL", Parent: "Databases"},
+	{Name: "MongoDB", Aliases: []string{"mongo"}, Parent: "Databases"},
+	{Name: "Redis", Parent: "Databases"},
+	{Name: "SQL", Parent: "Databases"},
+	{Name: "AWS", Aliases: []string{"amazon web services"}, Parent: "Cloud"},
+	{Name: "GCP", Aliases: []string{"google cloud platform", "google cloud"}, Parent: "Cloud"},
+	{Name: "Azure", Aliases: []string{"microsoft azure"}, Parent: "Cloud"},
+	{Name: "Kubernetes", Aliases: []string{"k8s"}, Parent: "DevOps"},
+	{Name: "Docker", Parent: "DevOps"},
+	{Name: "Terraform", Parent: "DevOps"},
+	{Name: "CI/CD", Aliases: []string{"ci cd", "cicd"}, Parent: "DevOps"},
+	{Name: "React", Aliases: []string{"reactjs", "react.js"}, Parent: "Frontend"},
+	{Name: "Vue", Aliases: []string{"vuejs", "vue.js"}, Parent: "Frontend"},
+	{Name: "Angular", Aliases: []string{"angularjs"}, Parent: "Frontend"},
+	{Name: "Node.js", Aliases: []string{"node", "nodejs"}, Parent: "Programming Languages"},
+	{Name: "REST", Aliases: []string{"rest api", "restful"}},
+	{Name: "Machine Learning", Aliases: []string{"ml"}, Parent: "Data Science"},
+}
+
+// SkillTaxonomy normalizes skill names to their canonical spelling.
+// Lookups are case-insensitive and ignore repeated whitespace.
+type SkillTaxonomy struct {
+	canonical map[string]string   // name or alias key -> canonical name
+	aliases   map[string][]string // canonical key -> alias keys
+	parents   map[string]string   // canonical key -> parent canonical name
+}
+
+func newSkillTaxonomy() *SkillTaxonomy {
+	return &SkillTaxonomy{
+		canonical: map[string]string{},
+		aliases:   map[string][]string{},
+		parents:   map[string]string{},
+	}
+}
+
+var defaultSkillTaxonomy = buildDefaultSkillTaxonomy()
+
+func buildDefaultSkillTaxonomy() *SkillTaxonomy {
+	taxonomy := newSkillTaxonomy()
+	for _, skill := range defaultSkills {
+		taxonomy.add(skill.Name, skill.Aliases, skill.Parent)
+	}
+	return taxonomy
+}
+
+// DefaultSkillTaxonomy returns the built-in taxonomy used when no company taxonomy applies
+func DefaultSkillTaxonomy() *SkillTaxonomy {
+	return defaultSkillTaxonomy
+}
+
+// clone copies the taxonomy so company entries can be layered on top of the defaults
+func (t *SkillTaxonomy) clone() *SkillTaxonomy {
+	copied := newSkillTaxonomy()
+	for key, name := range t.canonical {
+		copied.canonical[key] = name
+	}
+	for key, aliases := range t.aliases {
+		copied.aliases[key] = append([]string{}, aliases...)
+	}
+	for key, parent := range t.parents {
+		copied.parents[key] = parent
+	}
+	return copied
+}
+
+// add registers a skill, replacing any earlier entry using the same name or aliases
+func (t *SkillTaxonomy) add(name string, aliases []string, parent string) {
+	key := skillKey(name)
+	if key == "" {
+		return
+	}
+
+	t.canonical[key] = name
+	for _, alias := range aliases {
+		if aliasKey := skillKey(alias); aliasKey != "" && aliasKey != key {
+			// An alias moving to another skill must no longer be listed under its previous one
+			if previous, ok := t.canonical[aliasKey]; ok && skillKey(previous) != key {
+				t.removeAlias(skillKey(previous), aliasKey)
+			}
+			t.canonical[aliasKey] = name
+			t.aliases[key] = appendUnique(t.aliases[key], aliasKey)
+		}
+	}
+	if parent != "" {
+		t.parents[key] = parent
+	}
+}
+
+func (t *SkillTaxonomy) removeAlias(key string, alias string) {
+	aliases := t.aliases[key][:0]
+	for _, existing := range t.aliases[key] {
+		if existing != alias {
+			aliases = append(aliases, existing)
+		}
+	}
+	t.aliases[key] = aliases
+}
+
+// cleanSkillName trims a skill name and collapses repeated whitespace
+func cleanSkillName(skill string) string {
+	return strings.Join(strings.Fields(skill), " ")
+}
+
+// skillKey is the case and whitespace insensitive lookup key of a skill
+func skillKey(skill string) string {
+	return strings.ToLower(cleanSkillName(skill))
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// Normalize returns the canonical name of a skill, unknown skills are returned trimmed
+func (t *SkillTaxonomy) Normalize(skill string) string {
+	if name, ok := t.canonical[skillKey(skill)]; ok {
+		return name
+	}
+	return cleanSkillName(skill)
+}
+
+// NormalizeAll normalizes a list of skills and drops duplicates, keeping the original order
+func (t *SkillTaxonomy) NormalizeAll(skills []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, skill := range skills {
+		name := t.Normalize(skill)
+		if key := skillKey(name); key != "" && !seen[key] {
+			seen[key] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
+
+// Key returns the lookup key of the canonical name of a skill, used to compare skills
+func (t *SkillTaxonomy) Key(skill string) string {
+	return skillKey(t.Normalize(skill))
+}
+
+// Terms returns the canonical name and aliases of a skill as lowercase terms for text search
+func (t *SkillTaxonomy) Terms(skill string) []string {
+	key := t.Key(skill)
+	if key == "" {
+		return nil
+	}
+	return append([]string{key}, t.aliases[key]...)
+}
+
+// Parent returns the category of a skill, or an empty string
+func (t *SkillTaxonomy) Parent(skill string) string {
+	return t.parents[t.Key(skill)]
+}
+
+// Expand returns the keys of a skill and of every skill in its category, so filtering by
+// "Databases" also finds "PostgreSQL"
+func (t *SkillTaxonomy) Expand(skill string) []string {
+	root := t.Key(skill)
+	if root == "" {
+		return nil
+	}
+
+	expanded := []string{root}
+	for i := 0; i < len(expanded); i++ {
+		for child, parent := range t.parents {
+			if skillKey(parent) == expanded[i] {
+				expanded = appendUnique(expanded, child)
+			}
+		}
+	}
+	sort.Strings(expanded[1:])
+	return expanded
+}
+
+// Suggest returns skills whose name or alias starts with the query, followed by those containing it
+func (t *SkillTaxonomy) Suggest(query string, limit int) []models.SkillSuggestion {
+	query = skillKey(query)
+	if query == "" {
+		return []models.SkillSuggestion{}
+	}
+
+	type rankedSuggestion struct {
+		models.SkillSuggestion
+		rank int
+	}
+
+	best := map[string]rankedSuggestion{}
+	for key, name := range t.canonical {
+		rank := -1
+		switch {
+		case key == query:
+			rank = 0
+		case strings.HasPrefix(key, query):
+			rank = 1
+		case strings.Contains(key, query):
+			rank = 2
+		}
+		if rank < 0 {
+			continue
+		}
+
+		match := rankedSuggestion{models.SkillSuggestion{Name: name, Parent: t.parents[skillKey(name)]}, rank}
+		if key != skillKey(name) {
+			match.MatchedAlias = key
+		}
+		// Prefer the best ranked match of a skill, and its name over its aliases
+		if existing, ok := best[name]; !ok || match.rank < existing.rank ||
+			(match.rank == existing.rank && match.MatchedAlias == "") {
+			best[name] = match
+		}
+	}
+
+	ranked := make([]rankedSuggestion, 0, len(best))
+	for _, match := range best {
+		ranked = append(ranked, match)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].rank != ranked[j].rank {
+			return ranked[i].rank < ranked[j].rank
+		}
+		return strings.ToLower(ranked[i].Name) < strings.ToLower(ranked[j].Name)
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	suggestions := make([]models.SkillSuggestion, len(ranked))
+	for i, match := range ranked {
+		suggestions[i] = match.SkillSuggestion
+	}
+	return suggestions
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"backend/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkillTaxonomyNormalize(t *testing.T) {
+	taxonomy := DefaultSkillTaxonomy()
+
+	assert.Equal(t, "Go", taxonomy.Normalize("golang"))
+	assert.Equal(t, "PostgreSQL", taxonomy.Normalize("  Postgres "))
+	assert.Equal(t, "Google Cloud Platform", cleanSkillName("Google   Cloud Platform"))
+	assert.Equal(t, "GCP", taxonomy.Normalize("google   cloud"))
+	// Unknown skills are kept as entered, only trimmed
+	assert.Equal(t, "Elixir", taxonomy.Normalize(" Elixir"))
+
+	assert.Equal(t, []string{"Go", "Kubernetes", "Elixir"}, taxonomy.NormalizeAll([]string{"golang", "K8s", "Go", "", "Elixir", "elixir"}))
+}
+
+func TestSkillTaxonomyCompanyEntries(t *testing.T) {
+	taxonomy := DefaultSkillTaxonomy().clone()
+	taxonomy.add("Golang", []string{"go"}, "Backend")
+	taxonomy.add("Backend", nil, "")
+
+	assert.Equal(t, "Golang", taxonomy.Normalize("go"))
+	assert.Equal(t, "Backend", taxonomy.Parent("go"))
+	assert.Equal(t, []string{"backend", "golang"}, taxonomy.Expand("backend"))
+
+	// The defaults are not changed by company entries
+	assert.Equal(t, "Go", DefaultSkillTaxonomy().Normalize("golang"))
+}
+
+func TestSkillTaxonomyExpand(t *testing.T) {
+	taxonomy := DefaultSkillTaxonomy()
+
+	assert.Equal(t, []string{"mongodb", "mysql", "postgresql", "redis", "sql"}, taxonomy.Expand("databases")[1:])
+	assert.Equal(t, []string{"kubernetes"}, taxonomy.Expand("k8s"))
+	assert.Nil(t, taxonomy.Expand(" "))
+}
+
+func TestSkillTaxonomySuggest(t *testing.T) {
+	taxonomy := DefaultSkillTaxonomy()
+
+	assert.Equal(t, []models.SkillSuggestion{
+		{Name: "Kubernetes", MatchedAlias: "k8s", Parent: "DevOps"},
+	}, taxonomy.Suggest("k8", 10))
+	// The name is preferred over an alias matching as well
+	assert.Equal(t, []models.SkillSuggestion{
+		{Name: "PostgreSQL", Parent: "Databases"},
+	}, taxonomy.Suggest("postg", 10))
+
+	suggestions := taxonomy.Suggest("java", 10)
+	assert.Equal(t, "Java", suggestions[0].Name)
+	assert.Equal(t, "JavaScript", suggestions[1].Name)
+
+	assert.Len(t, taxonomy.Suggest("a", 3), 3)
+	assert.Empty(t, taxonomy.Suggest("", 10))
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrAttachmentTooLarge   = errors.New("file exceeds the maximum allowed size")
+	ErrAttachmentType       = errors.New("file type is not allowed")
+	ErrUnexpectedAttachment = errors.New("file field is not part of this application form")
+	ErrMissingAttachment    = errors.New("required file is missing")
+)
+
+// resumeFieldName is the multipart field of the primary resume upload
+const resumeFieldName = "resume"
+
+var allowedAttachmentExtensions = map[string]bool{
+	".pdf":  true,
+	".doc":  true,
+	".docx": true,
+	".txt":  true,
+	".rtf":  true,
+	".odt":  true,
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+}
+
+// templateFileField is a `file` question declared in a form template
+type templateFileField struct {
+	Name     string
+	Required bool
+}
+
+// pendingAttachment is a validated upload waiting to be stored
+type pendingAttachment struct {
+	FieldName string
+	File      *multipart.FileHeader
+}
+
+// getFormTemplateFields loads the template fields of the application form
+func getFormTemplateFields(ctx context.Context, formUUID string) ([]map[string]interface{}, error) {
+	db := database.GetDB()
+
+	var fieldsJSON []byte
+	err := db.GetContext(ctx, &fieldsJSON, `
+		SELECT ft.fields FROM application_form af
+		JOIN form_templates ft ON af.form_id = ft.id
+		WHERE af.form_uuid = $1`, formUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrFormNotFound
+		}
+		return nil, err
+	}
+
+	var fields []map[string]interface{}
+	if err := json.Unmarshal(fieldsJSON, &fields); err != nil {
+		// Older templates were stored as a plain object, they declare no file fields
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// templateFieldString returns the first non-empty string value among keys.
+// Templates built by the frontend use question_id/question_type, older ones field_name/field_type or id/type.
+func templateFieldString(field map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := field[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// templateFileFields returns the `file` questions of a template, except the resume question
+// which is covered by the primary resume upload
+func templateFileFields(fields []map[string]interface{}) []templateFileField {
+	fileFields := []templateFileField{}
+	for _, field := range fields {
+		if !strings.EqualFold(templateFieldString(field, "question_type", "field_type", "type"), "file") {
+			continue
+		}
+
+		name := templateFieldString(field, "question_id", "field_name", "id")
+		if name == "" || strings.Contains(strings.ToLower(name), resumeFieldName) {
+			continue
+		}
+
+		required, _ := field["required"].(bool)
+		fileFields = append(fileFields, templateFileField{Name: name, Required: required})
+	}
+	return fileFields
+}
+
+// collectAttachments validates the uploaded files against the `file` fields declared in the template
+func collectAttachments(form *multipart.Form, fileFields []templateFileField) ([]pendingAttachment, error) {
+	attachments := []pendingAttachment{}
+	declared := map[string]bool{resumeFieldName: true}
+
+	for _, field := range fileFields {
+		declared[field.Name] = true
+
+		var files []*multipart.FileHeader
+		if form != nil {
+			files = form.File[field.Name]
+		}
+		if len(files) == 0 {
+			if field.Required {
+				return nil, fmt.Errorf("%w: %s", ErrMissingAttachment, field.Name)
+			}
+			continue
+		}
+
+		for _, file := range files {
+			if err := validateAttachment(file); err != nil {
+				return nil, fmt.Errorf("%w: %s (%s)", err, field.Name, file.Filename)
+			}
+			attachments = append(attachments, pendingAttachment{FieldName: field.Name, File: file})
+		}
+	}
+
+	if form != nil {
+		for name := range form.File {
+			if !declared[name] {
+				return nil, fmt.Errorf("%w: %s", ErrUnexpectedAttachment, name)
+			}
+		}
+	}
+
+	return attachments, nil
+}
+
+// validateAttachment checks the size and file type of an upload
+func validateAttachment(file *multipart.FileHeader) error {
+	if file.Size > maxAttachmentSize() {
+		return tooLarge(maxAttachmentSize())
+	}
+	if !allowedAttachmentExtensions[strings.ToLower(filepath.Ext(file.Filename))] {
+		return ErrAttachmentType
+	}
+	return nil
+}
+
+// rowQueryer is implemented by both *sqlx.DB and *sqlx.Tx
+type rowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// insertSubmissionAttachments stores the attachment metadata of a saved submission
+func insertSubmissionAttachments(ctx context.Context, db rowQueryer, submission *models.JobSubmission) error {
+	for i := range submission.Attachments {
+		attachment := &submission.Attachments[i]
+		attachment.JobSubmissionID = submission.ID
+
+		err := db.QueryRowContext(ctx, `
+			INSERT INTO submission_attachments (job_submission_id, field_name, file_name, content_type, size_bytes, file_key)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at`,
+			attachment.JobSubmissionID,
+			attachment.FieldName,
+			attachment.FileName,
+			attachment.ContentType,
+			attachment.SizeBytes,
+			attachment.FileKey).Scan(&attachment.ID, &attachment.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAttachmentsForSubmissions returns the attachments of the given submissions keyed by submission id,
+// with expiring signed URLs
+func GetAttachmentsForSubmissions(ctx context.Context, submissionIDs []int) (map[int][]models.SubmissionAttachment, error) {
+	db := database.GetDB()
+	attachments := map[int][]models.SubmissionAttachment{}
+
+	if len(submissionIDs) == 0 {
+		return attachments, nil
+	}
+
+	var rows []models.SubmissionAttachment
+	err := db.SelectContext(ctx, &rows, `
+		SELECT id, job_submission_id, field_name, file_name, content_type, size_bytes, file_key, created_at
+		FROM submission_attachments
+		WHERE job_submission_id = ANY($1)
+		ORDER BY job_submission_id, field_name, id`,
+		pq.Array(submissionIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := signAttachmentURLs(ctx, rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		attachments[row.JobSubmissionID] = append(attachments[row.JobSubmissionID], row)
+	}
+	return attachments, nil
+}
+
+// signAttachmentURLs sets an expiring URL on each attachment
+func signAttachmentURLs(ctx context.Context, attachments []models.SubmissionAttachment) error {
+	for i := range attachments {
+		url, err := SignedResumeURL(ctx, attachments[i].FileKey)
+		if err != nil {
+			return err
+		}
+		attachments[i].FileURL = url
+	}
+	return nil
+}
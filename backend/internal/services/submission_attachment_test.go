@@ -0,0 +1,94 @@
+package services
+
+import (
+	"backend/internal/config"
+	"errors"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateFileFields(t *testing.T) {
+	fields := []map[string]interface{}{
+		{"question_id": "Q_Skills", "question_type": "checkbox"},
+		{"question_id": "Q_Resume", "question_type": "file"},
+		{"question_id": "Q_CoverLetter", "question_type": "file", "required": true},
+		{"field_name": "portfolio", "field_type": "FILE"},
+	}
+
+	fileFields := templateFileFields(fields)
+
+	assert.Equal(t, []templateFileField{
+		{Name: "Q_CoverLetter", Required: true},
+		{Name: "portfolio", Required: false},
+	}, fileFields)
+}
+
+func TestCollectAttachments(t *testing.T) {
+	assert.NoError(t, config.LoadConfig())
+	fileFields := []templateFileField{
+		{Name: "Q_CoverLetter", Required: true},
+		{Name: "portfolio"},
+	}
+	newForm := func(files map[string][]*multipart.FileHeader) *multipart.Form {
+		return &multipart.Form{File: files}
+	}
+	resume := []*multipart.FileHeader{{Filename: "resume.pdf", Size: 100}}
+
+	testCases := []struct {
+		name        string
+		form        *multipart.Form
+		expectedErr error
+		expectedLen int
+	}{
+		{
+			name: "Valid attachments",
+			form: newForm(map[string][]*multipart.FileHeader{
+				"resume":        resume,
+				"Q_CoverLetter": {{Filename: "cover.docx", Size: 100}},
+				"portfolio":     {{Filename: "a.pdf", Size: 100}, {Filename: "b.PNG", Size: 100}},
+			}),
+			expectedLen: 3,
+		},
+		{
+			name:        "Missing required attachment",
+			form:        newForm(map[string][]*multipart.FileHeader{"resume": resume}),
+			expectedErr: ErrMissingAttachment,
+		},
+		{
+			name: "File too large",
+			form: newForm(map[string][]*multipart.FileHeader{
+				"Q_CoverLetter": {{Filename: "cover.pdf", Size: maxAttachmentSize() + 1}},
+			}),
+			expectedErr: ErrAttachmentTooLarge,
+		},
+		{
+			name: "File type not allowed",
+			form: newForm(map[string][]*multipart.FileHeader{
+				"Q_CoverLetter": {{Filename: "cover.exe", Size: 100}},
+			}),
+			expectedErr: ErrAttachmentType,
+		},
+		{
+			name: "Undeclared file field",
+			form: newForm(map[string][]*multipart.FileHeader{
+				"Q_CoverLetter": {{Filename: "cover.pdf", Size: 100}},
+				"transcript":    {{Filename: "grades.pdf", Size: 100}},
+			}),
+			expectedErr: ErrUnexpectedAttachment,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attachments, err := collectAttachments(tc.form, fileFields)
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr), "unexpected error: %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, attachments, tc.expectedLen)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"archive/zip"
+	"backend/internal/config"
+	"backend/internal/scanner"
+	"backend/internal/storage"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+var ErrInfectedFile = errors.New("file was rejected by the malware scan")
+
+// Content types of uploads, sniffed from their content
+const (
+	contentTypePDF  = "application/pdf"
+	contentTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	contentTypeText = "text/plain; charset=utf-8"
+)
+
+// allowedResumeTypes are the sniffed content types accepted as resume, the file name does not matter
+var allowedResumeTypes = map[string]bool{
+	contentTypePDF:  true,
+	contentTypeDOCX: true,
+	contentTypeText: true,
+}
+
+// maxResumeSize is the upload limit for resumes
+func maxResumeSize() int64 {
+	return config.GetConfig().Upload.MaxResumeSize
+}
+
+// maxAttachmentSize is the per-file upload limit for application attachments
+func maxAttachmentSize() int64 {
+	return config.GetConfig().Upload.MaxAttachmentSize
+}
+
+// tooLarge wraps ErrAttachmentTooLarge with the limit that was exceeded
+func tooLarge(limit int64) error {
+	return fmt.Errorf("%w of %s", ErrAttachmentTooLarge, formatSize(limit))
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20 && size%(1<<20) == 0:
+		return fmt.Sprintf("%dMB", size>>20)
+	case size >= 1<<10 && size%(1<<10) == 0:
+		return fmt.Sprintf("%dKB", size>>10)
+	default:
+		return fmt.Sprintf("%d bytes", size)
+	}
+}
+
+// validateResume checks the size of a resume and that its content is a PDF, DOCX or TXT file
+func validateResume(file *multipart.FileHeader) error {
+	if file.Size > maxResumeSize() {
+		return tooLarge(maxResumeSize())
+	}
+	contentType, err := detectContentType(file)
+	if err != nil {
+		return fmt.Errorf("failed to read resume: %v", err)
+	}
+	if !allowedResumeTypes[contentType] {
+		return ErrResumeType
+	}
+	return nil
+}
+
+// detectContentType sniffs the type of an upload from its first bytes.
+// The Content-Type sent by the client and the file extension are not trusted.
+func detectContentType(file *multipart.FileHeader) (string, error) {
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		return contentTypePDF, nil
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		// DOCX files are zip archives with a word/document.xml part
+		if isDOCX(src, file.Size) {
+			return contentTypeDOCX, nil
+		}
+		return "application/zip", nil
+	}
+	return http.DetectContentType(head), nil
+}
+
+// isDOCX looks for the main document part in the zip directory, without reading the whole archive
+func isDOCX(src io.ReaderAt, size int64) bool {
+	archive, err := zip.NewReader(src, size)
+	if err != nil {
+		return false
+	}
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	fileScanner   scanner.Scanner
+	fileScannerMu sync.Mutex
+)
+
+// GetScanner returns the malware scanner selected by the configuration
+func GetScanner() (scanner.Scanner, error) {
+	fileScannerMu.Lock()
+	defer fileScannerMu.Unlock()
+
+	if fileScanner != nil {
+		return fileScanner, nil
+	}
+	s, err := scanner.New(config.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	fileScanner = s
+	return fileScanner, nil
+}
+
+// SetScanner overrides the malware scanner, e.g. with a fake in tests
+func SetScanner(s scanner.Scanner) {
+	fileScannerMu.Lock()
+	defer fileScannerMu.Unlock()
+	fileScanner = s
+}
+
+// scanUploads checks the files of an application for malware before anything is stored or saved.
+// Infected files are moved to the quarantine folder of the company's job for inspection.
+func scanUploads(ctx context.Context, companyName string, jobID string, files ...*multipart.FileHeader) error {
+	s, err := GetScanner()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		result, err := scanUpload(ctx, s, file)
+		if err != nil {
+			log.Printf("Failed to scan %s: %v", file.Filename, err)
+			return fmt.Errorf("failed to scan %s: %v", file.Filename, err)
+		}
+		if !result.Infected {
+			continue
+		}
+
+		key, err := quarantineUpload(ctx, companyName, jobID, file, result.Signature)
+		if err != nil {
+			log.Printf("Failed to quarantine %s: %v", file.Filename, err)
+		} else {
+			log.Printf("Quarantined %s (%s) as %s", file.Filename, result.Signature, key)
+		}
+		return fmt.Errorf("%w: %s", ErrInfectedFile, file.Filename)
+	}
+	return nil
+}
+
+func scanUpload(ctx context.Context, s scanner.Scanner, file *multipart.FileHeader) (*scanner.Result, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	return s.Scan(ctx, src)
+}
+
+// quarantineUpload stores an infected file under quarantine/<company>/<job>/, away from the resumes and attachments
+func quarantineUpload(ctx context.Context, companyName string, jobID string, file *multipart.FileHeader, signature string) (string, error) {
+	store, err := GetStorage()
+	if err != nil {
+		return "", err
+	}
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	key := fmt.Sprintf("quarantine/%s/%s/%s%s", sanitizeKeySegment(companyName), sanitizeKeySegment(jobID),
+		uuid.NewString(), keyExtension(file.Filename))
+	err = store.Put(ctx, key, src, storage.PutOptions{
+		ContentType: "application/octet-stream",
+		Metadata: map[string]string{
+			storage.MetadataOriginalFilename: file.Filename,
+			"malware-signature":              signature,
+		},
+	})
+	return key, err
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"archive/zip"
+	"backend/internal/config"
+	"backend/internal/scanner"
+	"backend/internal/storage"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFileHeader builds an uploaded file as the multipart parser would
+func newFileHeader(t *testing.T, fileName string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("resume", fileName)
+	require.NoError(t, err)
+	part.Write(content)
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	return form.File["resume"][0]
+}
+
+func zipFile(t *testing.T, names ...string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, name := range names {
+		f, err := archive.Create(name)
+		require.NoError(t, err)
+		f.Write([]byte("<w:document/>"))
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+func TestDetectContentType(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fileName string
+		content  []byte
+		expected string
+	}{
+		{"PDF", "resume.pdf", []byte("%PDF-1.4\n%âãÏÓ"), contentTypePDF},
+		{"DOCX", "resume.docx", zipFile(t, "[Content_Types].xml", "word/document.xml"), contentTypeDOCX},
+		{"Plain zip renamed to docx", "resume.docx", zipFile(t, "payload.bin"), "application/zip"},
+		{"Text", "resume.txt", []byte("Jane Doe\nGo developer"), contentTypeText},
+		{"Executable renamed to pdf", "resume.pdf", []byte("MZ\x90\x00\x03\x00\x00\x00\x04\x00"), "application/octet-stream"},
+		{"HTML renamed to txt", "resume.txt", []byte("<html><script>alert(1)</script></html>"), "text/html; charset=utf-8"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contentType, err := detectContentType(newFileHeader(t, tc.fileName, tc.content))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, contentType)
+		})
+	}
+}
+
+func TestValidateResume(t *testing.T) {
+	require.NoError(t, config.LoadConfig())
+	config.GetConfig().Upload.MaxResumeSize = 1 << 10
+	defer config.LoadConfig()
+
+	assert.NoError(t, validateResume(newFileHeader(t, "resume.pdf", []byte("%PDF-1.4 resume"))))
+	// The content decides, not the extension
+	assert.NoError(t, validateResume(newFileHeader(t, "resume", []byte("Jane Doe, Go developer"))))
+	assert.Equal(t, ErrResumeType, validateResume(newFileHeader(t, "resume.pdf", []byte("MZ\x90\x00\x03\x00"))))
+
+	err := validateResume(newFileHeader(t, "resume.txt", bytes.Repeat([]byte("a"), 2<<10)))
+	assert.True(t, errors.Is(err, ErrAttachmentTooLarge), "unexpected error: %v", err)
+	assert.Contains(t, err.Error(), "1KB")
+}
+
+// fakeScanner reports files containing "virus" as infected
+type fakeScanner struct {
+	err     error
+	scanned []string
+}
+
+func (s *fakeScanner) Scan(ctx context.Context, body io.Reader) (*scanner.Result, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	s.scanned = append(s.scanned, string(data))
+	if s.err != nil {
+		return nil, s.err
+	}
+	if strings.Contains(string(data), "virus") {
+		return &scanner.Result{Infected: true, Signature: "Fake-Virus"}, nil
+	}
+	return &scanner.Result{}, nil
+}
+
+func TestScanUploads(t *testing.T) {
+	store := storage.NewMemoryStorage(&storage.URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("test")})
+	SetStorage(store)
+	defer SetStorage(nil)
+	fake := &fakeScanner{}
+	SetScanner(fake)
+	defer SetScanner(nil)
+	ctx := context.Background()
+
+	resume := newFileHeader(t, "resume.pdf", []byte("%PDF-1.4 resume"))
+	cover := newFileHeader(t, "cover.pdf", []byte("%PDF-1.4 virus"))
+
+	assert.NoError(t, scanUploads(ctx, "Acme", "J_1", resume))
+	assert.Empty(t, store.Keys())
+
+	// The infected file is quarantined, nothing else is stored
+	err := scanUploads(ctx, "Acme", "J_1", resume, cover)
+	assert.True(t, errors.Is(err, ErrInfectedFile), "unexpected error: %v", err)
+	keys := store.Keys()
+	require.Len(t, keys, 1)
+	assert.True(t, strings.HasPrefix(keys[0], "quarantine/acme/j_1/"), keys[0])
+	file, info, err := store.Get(ctx, keys[0])
+	require.NoError(t, err)
+	file.Close()
+	assert.Equal(t, "cover.pdf", info.Metadata[storage.MetadataOriginalFilename])
+	assert.Equal(t, "Fake-Virus", info.Metadata["malware-signature"])
+
+	// Files that cannot be scanned are rejected
+	SetScanner(&fakeScanner{err: errors.New("clamd is down")})
+	err = scanUploads(ctx, "Acme", "J_1", resume)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrInfectedFile))
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStorage keeps files in memory, for tests
+type MemoryStorage struct {
+	signer *URLSigner
+
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	data []byte
+	info ObjectInfo
+}
+
+func NewMemoryStorage(signer *URLSigner) *MemoryStorage {
+	return &MemoryStorage{signer: signer, objects: map[string]memoryObject{}}
+}
+
+func (s *MemoryStorage) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = memoryObject{
+		data: data,
+		info: ObjectInfo{
+			Key:          key,
+			Size:         int64(len(data)),
+			ContentType:  contentType(key, opts.ContentType),
+			LastModified: time.Now(),
+			Metadata:     copyMetadata(opts.Metadata),
+		},
+	}
+	return nil
+}
+
+func (s *MemoryStorage) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	info := object.info
+	info.Metadata = copyMetadata(info.Metadata)
+	return io.NopCloser(bytes.NewReader(object.data)), &info, nil
+}
+
+func (s *MemoryStorage) Delete(ctx context.Context, key string) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *MemoryStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	objects := []ObjectInfo{}
+	for key, object := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, object.info)
+		}
+	}
+	return objects, nil
+}
+
+func (s *MemoryStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return s.signer.Sign(key, ttl, time.Now()), nil
+}
+
+func (s *MemoryStorage) URL(key string) string {
+	return s.signer.URL(key)
+}
+
+// Signer returns the signer of the URLs this backend hands out
+func (s *MemoryStorage) Signer() *URLSigner {
+	return s.signer
+}
+
+// Keys returns the keys of all stored files, for assertions in tests
+func (s *MemoryStorage) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.objects))
+	for key := range s.objects {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3Options configure the S3 backend. Credentials come from the usual AWS environment variables and files.
+type S3Options struct {
+	Region   string
+	Endpoint string // custom endpoint of an S3 compatible service such as MinIO, empty for AWS
+	Bucket   string
+}
+
+// S3Storage keeps files in an S3 bucket
+type S3Storage struct {
+	client   *s3.S3
+	uploader *s3manager.Uploader
+	bucket   string
+	endpoint string
+}
+
+func NewS3Storage(opts S3Options) (*S3Storage, error) {
+	awsConfig := &aws.Config{Region: aws.String(opts.Region)}
+	if opts.Endpoint != "" {
+		// S3 compatible services usually do not support bucket subdomains
+		awsConfig.Endpoint = aws.String(opts.Endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("AWS session error: %w", err)
+	}
+	client := s3.New(sess)
+
+	return &S3Storage{
+		client:   client,
+		uploader: s3manager.NewUploaderWithClient(client),
+		bucket:   opts.Bucket,
+		endpoint: strings.TrimRight(opts.Endpoint, "/"),
+	}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	// The uploader streams the body in parts instead of reading it into memory
+	_, err = s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType(key, opts.ContentType)),
+		Metadata:    encodeS3Metadata(opts.Metadata),
+	})
+	if err != nil {
+		return fmt.Errorf("S3 upload error: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && (awsErr.Code() == s3.ErrCodeNoSuchKey || awsErr.Code() == "NotFound") {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, fmt.Errorf("S3 download error: %w", err)
+	}
+
+	info := &ObjectInfo{
+		Key:          key,
+		Size:         aws.Int64Value(output.ContentLength),
+		ContentType:  contentType(key, aws.StringValue(output.ContentType)),
+		LastModified: aws.TimeValue(output.LastModified),
+		Metadata:     decodeS3Metadata(output.Metadata),
+	}
+	return output.Body, info, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("S3 delete error: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := []ObjectInfo{}
+	err := s.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			key := aws.StringValue(object.Key)
+			objects = append(objects, ObjectInfo{
+				Key:          key,
+				Size:         aws.Int64Value(object.Size),
+				ContentType:  contentType(key, ""),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("S3 list error: %w", err)
+	}
+	return objects, nil
+}
+
+func (s *S3Storage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	key, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	req, _ := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	req.SetContext(ctx)
+	signedURL, err := req.Presign(ttl)
+	if err != nil {
+		return "", fmt.Errorf("S3 presign error: %w", err)
+	}
+	return signedURL, nil
+}
+
+func (s *S3Storage) URL(key string) string {
+	escaped := (&url.URL{Path: strings.TrimLeft(key, "/")}).EscapedPath()
+	if s.endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, escaped)
+	}
+	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.bucket, escaped)
+}
+
+// encodeS3Metadata encodes non-ASCII values as RFC 2047 words, S3 only accepts ASCII in metadata headers
+func encodeS3Metadata(metadata map[string]string) map[string]*string {
+	encoded := make(map[string]*string, len(metadata))
+	for key, value := range metadata {
+		encoded[key] = aws.String(mime.QEncoding.Encode("utf-8", value))
+	}
+	return encoded
+}
+
+// decodeS3Metadata returns user-defined metadata with lowercase keys, S3 returns them capitalized
+func decodeS3Metadata(metadata map[string]*string) map[string]string {
+	decoder := new(mime.WordDecoder)
+	decoded := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		text, err := decoder.DecodeHeader(aws.StringValue(value))
+		if err != nil {
+			text = aws.StringValue(value)
+		}
+		decoded[strings.ToLower(key)] = text
+	}
+	return decoded
+}
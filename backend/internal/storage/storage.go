@@ -0,0 +1,149 @@
+// Package storage keeps uploaded files (resumes, attachments) in a blob store.
+// The backend is selected by the STORAGE_BACKEND setting.
+package storage
+
+import (
+	"backend/internal/config"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("file not found")
+	ErrInvalidKey = errors.New("invalid file key")
+)
+
+// Backends
+const (
+	BackendS3     = "s3"
+	BackendLocal  = "local"
+	BackendMemory = "memory"
+)
+
+// Storage stores files under slash separated keys such as resumes/jane.pdf
+type Storage interface {
+	// Put stores the file, replacing an existing file with the same key
+	Put(ctx context.Context, key string, body io.Reader, opts PutOptions) error
+	// Get opens the file, the caller closes it. It returns ErrNotFound for unknown keys.
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Delete removes the file, deleting an unknown key is not an error
+	Delete(ctx context.Context, key string) error
+	// List returns the files whose key starts with prefix, e.g. "resumes/"
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// SignedURL returns a URL that gives access to the file until it expires
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// URL returns the permanent location of the file
+	URL(key string) string
+}
+
+// PutOptions describe the stored file
+type PutOptions struct {
+	ContentType string            // guessed from the key's extension when empty
+	Metadata    map[string]string // user-defined metadata such as the original file name, not kept by the local backend
+}
+
+// ObjectInfo describes a stored file
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+	Metadata     map[string]string
+}
+
+// MetadataOriginalFilename is the metadata key of the name the file was uploaded with
+const MetadataOriginalFilename = "original-filename"
+
+// New returns the storage backend selected by the configuration
+func New(cfg *config.Config) (Storage, error) {
+	signer := &URLSigner{BaseURL: cfg.Storage.BaseURL, Secret: []byte(cfg.JWTSecret)}
+
+	switch cfg.Storage.Backend {
+	case BackendS3:
+		return NewS3Storage(S3Options{
+			Region:   cfg.AWSRegion,
+			Endpoint: cfg.AWSEndpoint,
+			Bucket:   cfg.S3Bucket,
+		})
+	case BackendLocal:
+		return NewLocalStorage(cfg.Storage.LocalDir, signer)
+	case BackendMemory:
+		return NewMemoryStorage(signer), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+// CleanKey validates a key and returns it without leading slashes.
+// Keys cannot leave the storage root, e.g. through "..".
+func CleanKey(key string) (string, error) {
+	key = strings.TrimLeft(key, "/")
+	if key == "" || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return key, nil
+}
+
+// contentType returns the content type of a file, guessed from the key when not given
+func contentType(key string, given string) string {
+	if given != "" {
+		return given
+	}
+	if guessed := mime.TypeByExtension(path.Ext(key)); guessed != "" {
+		return guessed
+	}
+	return "application/octet-stream"
+}
+
+// URLSigner signs download URLs served by this API for backends without their own signed URLs
+type URLSigner struct {
+	BaseURL string
+	Secret  []byte
+}
+
+// FilesPath is the route serving files through signed URLs
+const FilesPath = "/api/files/"
+
+func (s *URLSigner) signature(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.Secret)
+	fmt.Fprintf(mac, "%s\n%d", key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns a URL to the file that is valid for ttl
+func (s *URLSigner) Sign(key string, ttl time.Duration, now time.Time) string {
+	expires := now.Add(ttl).Unix()
+	escaped := (&url.URL{Path: key}).EscapedPath()
+	return fmt.Sprintf("%s%s%s?expires=%d&signature=%s",
+		strings.TrimRight(s.BaseURL, "/"), FilesPath, escaped, expires, s.signature(key, expires))
+}
+
+// Verify reports whether the expires and signature parameters of a signed URL are valid for the key
+func (s *URLSigner) Verify(key string, expires string, signature string, now time.Time) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.signature(key, expiresAt)))
+}
+
+// URL returns the unsigned location of the file served by this API
+func (s *URLSigner) URL(key string) string {
+	return strings.TrimRight(s.BaseURL, "/") + FilesPath + (&url.URL{Path: key}).EscapedPath()
+}
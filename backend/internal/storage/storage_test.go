@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanKey(t *testing.T) {
+	key, err := CleanKey("/resumes/jane.pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, "resumes/jane.pdf", key)
+
+	for _, invalid := range []string{"", "/", "../etc/passwd", "resumes/../../x", "resumes//jane.pdf", `resumes\jane.pdf`} {
+		_, err := CleanKey(invalid)
+		assert.ErrorIs(t, err, ErrInvalidKey, invalid)
+	}
+}
+
+func TestURLSigner(t *testing.T) {
+	signer := &URLSigner{BaseURL: "http://localhost:8080/", Secret: []byte("secret")}
+	now := time.Now()
+
+	signed, err := url.Parse(signer.Sign("resumes/jane doe.pdf", time.Minute, now))
+	require.NoError(t, err)
+	assert.Equal(t, "/api/files/resumes/jane doe.pdf", signed.Path)
+
+	expires, signature := signed.Query().Get("expires"), signed.Query().Get("signature")
+	assert.True(t, signer.Verify("resumes/jane doe.pdf", expires, signature, now))
+	// Another file, an expired link or another secret
+	assert.False(t, signer.Verify("resumes/john.pdf", expires, signature, now))
+	assert.False(t, signer.Verify("resumes/jane doe.pdf", expires, signature, now.Add(2*time.Minute)))
+	other := &URLSigner{BaseURL: signer.BaseURL, Secret: []byte("other")}
+	assert.False(t, other.Verify("resumes/jane doe.pdf", expires, signature, now))
+}
+
+// testStorage runs the behaviour every backend shares
+func testStorage(t *testing.T, store Storage) {
+	ctx := context.Background()
+
+	err := store.Put(ctx, "resumes/jane.pdf", strings.NewReader("%PDF-1.4 resume"), PutOptions{})
+	require.NoError(t, err)
+
+	file, info, err := store.Get(ctx, "resumes/jane.pdf")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, "%PDF-1.4 resume", string(data))
+	assert.Equal(t, int64(len(data)), info.Size)
+	assert.Equal(t, "application/pdf", info.ContentType)
+
+	// Put replaces the file
+	require.NoError(t, store.Put(ctx, "resumes/jane.pdf", strings.NewReader("updated"), PutOptions{}))
+	file, _, err = store.Get(ctx, "resumes/jane.pdf")
+	require.NoError(t, err)
+	data, _ = io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, "updated", string(data))
+
+	// Listing by prefix
+	require.NoError(t, store.Put(ctx, "resumes/acme/john.pdf", strings.NewReader("john"), PutOptions{}))
+	require.NoError(t, store.Put(ctx, "attachments/jane.pdf", strings.NewReader("cover letter"), PutOptions{}))
+	objects, err := store.List(ctx, "resumes/")
+	require.NoError(t, err)
+	keys := []string{}
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+		assert.False(t, object.LastModified.IsZero())
+	}
+	assert.ElementsMatch(t, []string{"resumes/jane.pdf", "resumes/acme/john.pdf"}, keys)
+
+	signedURL, err := store.SignedURL(ctx, "resumes/jane.pdf", time.Minute)
+	assert.NoError(t, err)
+	assert.Contains(t, signedURL, "signature=")
+
+	require.NoError(t, store.Delete(ctx, "resumes/jane.pdf"))
+	_, _, err = store.Get(ctx, "resumes/jane.pdf")
+	assert.ErrorIs(t, err, ErrNotFound)
+	// Deleting twice is fine
+	assert.NoError(t, store.Delete(ctx, "resumes/jane.pdf"))
+
+	assert.ErrorIs(t, store.Put(ctx, "../outside.txt", strings.NewReader("x"), PutOptions{}), ErrInvalidKey)
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testStorage(t, NewMemoryStorage(&URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("secret")}))
+}
+
+func TestMemoryStorageMetadata(t *testing.T) {
+	store := NewMemoryStorage(&URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("secret")})
+	ctx := context.Background()
+
+	metadata := map[string]string{MetadataOriginalFilename: "Jane Doe CV.pdf"}
+	require.NoError(t, store.Put(ctx, "resumes/jane.pdf", strings.NewReader("%PDF"), PutOptions{Metadata: metadata}))
+	metadata[MetadataOriginalFilename] = "changed.pdf"
+
+	file, info, err := store.Get(ctx, "resumes/jane.pdf")
+	require.NoError(t, err)
+	file.Close()
+	assert.Equal(t, "Jane Doe CV.pdf", info.Metadata[MetadataOriginalFilename])
+}
+
+func TestLocalStorage(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir(), &URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("secret")})
+	require.NoError(t, err)
+	testStorage(t, store)
+}
+
+func TestS3StorageURL(t *testing.T) {
+	store, err := NewS3Storage(S3Options{Region: "us-east-1", Bucket: "resumes"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://resumes.s3.amazonaws.com/resumes/jane.pdf", store.URL("resumes/jane.pdf"))
+
+	// Custom endpoints such as MinIO use path style URLs
+	store, err = NewS3Storage(S3Options{Region: "us-east-1", Endpoint: "http://localhost:9000/", Bucket: "resumes"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000/resumes/resumes/jane.pdf", store.URL("resumes/jane.pdf"))
+}
+
+func TestS3Metadata(t *testing.T) {
+	encoded := encodeS3Metadata(map[string]string{MetadataOriginalFilename: "Zoë CV.pdf", "plain": "cv.pdf"})
+	assert.Equal(t, "cv.pdf", *encoded["plain"])
+	assert.NotContains(t, *encoded[MetadataOriginalFilename], "ë")
+
+	// S3 returns the keys capitalized
+	decoded := decodeS3Metadata(map[string]*string{"Original-Filename": encoded[MetadataOriginalFilename]})
+	assert.Equal(t, "Zoë CV.pdf", decoded[MetadataOriginalFilename])
+}
@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkUpdateSubmissionsH(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+
+	var jobIdNum, formIdNum int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_BULK', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('bulk_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+
+	submissionIDs := []int{}
+	for _, candidate := range []struct{ name, email, status string }{
+		{"Jane", "jane@example.com", "applied"},
+		{"John", "john@example.com", "applied"},
+		{"Ann", "ann@example.com", "withdrawn"},
+	} {
+		var id int
+		err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, status)
+			VALUES ('1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d', 'J_BULK', $1, $2, '{}', 'resume.pdf', $3) RETURNING id`,
+			candidate.name, candidate.email, candidate.status).Scan(&id)
+		assert.NoError(t, err)
+		submissionIDs = append(submissionIDs, id)
+	}
+
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", hrUserID)
+		c.Next()
+	})
+	router.POST("/api/jobs/submissions/bulk", handlers.BulkUpdateSubmissionsH)
+
+	bulk := func(body map[string]interface{}) (int, map[string]interface{}) {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", "/api/jobs/submissions/bulk", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var response struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		return resp.Code, response.Data
+	}
+
+	// The withdrawn and the unknown submission fail, the others are moved and emailed
+	code, data := bulk(map[string]interface{}{
+		"submission_ids": append(submissionIDs, 999999),
+		"action":         "move",
+		"stage":          "shortlisted",
+		"email":          map[string]string{"subject": "Update on {{job_title}}", "body": "Hi {{name}}, you are now {{stage}}."},
+	})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), data["succeeded"])
+	assert.Equal(t, float64(2), data["failed"])
+	assert.Equal(t, float64(2), data["emails_queued"])
+
+	results := data["results"].([]interface{})
+	assert.Equal(t, "shortlisted", results[0].(map[string]interface{})["status"])
+	assert.NotEmpty(t, results[2].(map[string]interface{})["error"])
+
+	var subject, body string
+	err = db.QueryRow("SELECT subject, body FROM email_queue WHERE to_email = 'jane@example.com'").Scan(&subject, &body)
+	assert.NoError(t, err)
+	assert.Equal(t, "Update on Backend Engineer", subject)
+	assert.Equal(t, "Hi Jane, you are now Shortlisted.", body)
+
+	// Tag, assign and reject
+	code, data = bulk(map[string]interface{}{"submission_ids": submissionIDs[:2], "action": "tag", "tags": []string{"Strong Backend"}})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), data["succeeded"])
+
+	code, _ = bulk(map[string]interface{}{"submission_ids": submissionIDs[:2], "action": "assign", "assignee_id": hrUserID})
+	assert.Equal(t, http.StatusOK, code)
+
+	code, data = bulk(map[string]interface{}{"submission_ids": submissionIDs[:1], "action": "reject", "reason": "Position filled"})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "rejected", data["results"].([]interface{})[0].(map[string]interface{})["status"])
+
+	var tags, assigned int
+	db.QueryRow("SELECT COUNT(*) FROM submission_tags WHERE tag = 'strong backend'").Scan(&tags)
+	db.QueryRow("SELECT COUNT(*) FROM job_submissions WHERE assigned_to = $1", hrUserID).Scan(&assigned)
+	assert.Equal(t, 2, tags)
+	assert.Equal(t, 2, assigned)
+
+	// Missing parameters are rejected before anything changes
+	code, _ = bulk(map[string]interface{}{"submission_ids": submissionIDs, "action": "move"})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = bulk(map[string]interface{}{"submission_ids": submissionIDs, "action": "assign", "assignee_id": 999999})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
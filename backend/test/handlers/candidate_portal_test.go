@@ -0,0 +1,161 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"backend/internal/api/handlers"
+	"backend/internal/api/middleware"
+	"backend/internal/services"
+	"backend/test"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCandidateApplicationStatusPage(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+
+	var jobIdNum, formIdNum, submissionID int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_STATUS', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('status_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('5b9a2f3e-3a9c-4a43-9d1c-2f6a4b0f1e11', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, ats_score)
+		VALUES ('5b9a2f3e-3a9c-4a43-9d1c-2f6a4b0f1e11', 'J_STATUS', 'Candidate', 'candidate@example.com', '{}', 'resume.pdf', 87)
+		RETURNING id`).Scan(&submissionID)
+	assert.NoError(t, err)
+
+	router := test.SetupTestRouter()
+	router.GET("/api/candidate/application", handlers.GetCandidateApplicationH)
+	router.POST("/api/candidate/application/withdraw", handlers.WithdrawCandidateApplicationH)
+
+	token, err := services.GenerateCandidateToken(submissionID, "candidate@example.com")
+	assert.NoError(t, err)
+
+	// Status page
+	req, _ := http.NewRequest("GET", "/api/candidate/application?token="+token, nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend Engineer", response["job_title"])
+	assert.Equal(t, "Test Company", response["company_name"])
+	assert.Equal(t, "applied", response["status"])
+	assert.Equal(t, true, response["can_withdraw"])
+	assert.NotContains(t, response, "ats_score")
+
+	// Invalid token
+	req, _ = http.NewRequest("GET", "/api/candidate/application?token=invalid", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	// Token for another email
+	otherToken, _ := services.GenerateCandidateToken(submissionID, "someone@example.com")
+	req, _ = http.NewRequest("GET", "/api/candidate/application?token="+otherToken, nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+	// Withdraw, then withdrawing again is rejected
+	req, _ = http.NewRequest("POST", "/api/candidate/application/withdraw?token="+token, nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var status string
+	err = db.QueryRow("SELECT status FROM job_submissions WHERE id = $1", submissionID).Scan(&status)
+	assert.NoError(t, err)
+	assert.Equal(t, "withdrawn", status)
+
+	req, _ = http.NewRequest("POST", "/api/candidate/application/withdraw?token="+token, nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusConflict, resp.Code)
+}
+
+// recordingMailer keeps the emails sent in the background
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []services.EmailMessage
+}
+
+func (m *recordingMailer) Send(msg services.EmailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *recordingMailer) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sent)
+}
+
+func TestRequestStatusLink(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	var jobIdNum, formIdNum int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_LINK', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('link_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('6c0b3a4f-4b0d-4b54-8e2d-3a7b5c1a2f22', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key)
+		VALUES ('6c0b3a4f-4b0d-4b54-8e2d-3a7b5c1a2f22', 'J_LINK', 'Candidate', 'link@example.com', '{}', 'resume.pdf')`)
+	assert.NoError(t, err)
+
+	mailer := &recordingMailer{}
+	services.SetMailer(mailer)
+	defer services.SetMailer(nil)
+
+	router := test.SetupTestRouter()
+	router.POST("/api/candidate/status-link", middleware.LimitRequestsPerIP(2, time.Hour), handlers.RequestStatusLinkH)
+	request := func(email string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(map[string]string{"job_id": "J_LINK", "email": email})
+		req, _ := http.NewRequest("POST", "/api/candidate/status-link", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Both emails get the same answer, only the candidate gets a link
+	resp := request("link@example.com")
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	resp = request("unknown@example.com")
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.Eventually(t, func() bool { return mailer.count() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "link@example.com", mailer.sent[0].To)
+	assert.Equal(t, "Your application for Backend Engineer", mailer.sent[0].Subject)
+
+	// Further requests from the same IP are rejected
+	resp = request("link@example.com")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+}
@@ -0,0 +1,114 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchCandidatesH(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+
+	var jobIdNum, formIdNum int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_SEARCH', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('search_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e5f', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+
+	// The search document is computed on the fly for rows without a stored one
+	for _, candidate := range []struct {
+		name, email, formData, resumeText, status string
+		skills                                    []string
+		score                                     int
+	}{
+		{"Jane Doe", "jane.doe@example.com", `{"q_motivation": "I love building payment systems"}`, "Built billing services in Go", "applied", []string{"Go"}, 90},
+		{"John Smith", "john@example.com", `{"q_motivation": "Frontend work"}`, "React developer who also wrote payments dashboards", "shortlisted", []string{"React"}, 60},
+		{"Ann Lee", "ann@example.com", `{}`, "Data engineer", "rejected", []string{"Python"}, 30},
+	} {
+		_, err = db.Exec(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, resume_text, skills, status, ats_score)
+			VALUES ('7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e5f', 'J_SEARCH', $1, $2, $3, 'resume.pdf', $4, $5, $6, $7)`,
+			candidate.name, candidate.email, candidate.formData, candidate.resumeText, pq.Array(candidate.skills), candidate.status, candidate.score)
+		assert.NoError(t, err)
+	}
+
+	// Rows inserted directly have no stored search document until the upgrade script backfills it
+	backfill, err := os.ReadFile("../../internal/database/scripts/job_submission_columns.sql")
+	assert.NoError(t, err)
+	_, err = db.Exec(string(backfill))
+	assert.NoError(t, err)
+
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", hrUserID)
+		c.Next()
+	})
+	router.GET("/api/candidates/search", handlers.SearchCandidatesH)
+
+	search := func(query string) (int, []map[string]interface{}, map[string]interface{}) {
+		req, _ := http.NewRequest("GET", "/api/candidates/search"+query, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var response struct {
+			Data       []map[string]interface{} `json:"data"`
+			Pagination map[string]interface{}   `json:"pagination"`
+		}
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		return resp.Code, response.Data, response.Pagination
+	}
+
+	// Stemmed match in form answers and resume text, form answers rank higher
+	code, results, pagination := search("?q=payment")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Jane Doe", results[0]["username"])
+	assert.Equal(t, float64(2), pagination["total"])
+
+	// Name, email and skill
+	_, results, _ = search("?q=smith")
+	assert.Len(t, results, 1)
+	_, results, _ = search("?q=jane.doe@example.com")
+	assert.Len(t, results, 1)
+	_, results, _ = search("?q=python")
+	assert.Equal(t, "Ann Lee", results[0]["username"])
+
+	// Filters
+	_, results, _ = search("?q=payment&status=shortlisted")
+	assert.Len(t, results, 1)
+	_, results, _ = search("?min_score=50&max_score=80")
+	assert.Len(t, results, 1)
+	_, results, _ = search("?skill=golang")
+	assert.Equal(t, "Jane Doe", results[0]["username"])
+	_, results, _ = search("?job_id=UNKNOWN")
+	assert.Len(t, results, 0)
+
+	// Pagination
+	_, results, pagination = search("?page=2&page_size=2")
+	assert.Len(t, results, 1)
+	assert.Equal(t, float64(3), pagination["total"])
+	assert.Equal(t, float64(2), pagination["total_pages"])
+
+	// Invalid ranges
+	code, _, _ = search("?min_score=80&max_score=50")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _, _ = search("?from_date=2024-13-01")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
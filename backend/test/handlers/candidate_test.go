@@ -0,0 +1,109 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCandidateProfile(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	interviewerID, _ := test.InsertTestInterviewerUser(db)
+
+	var candidateID, otherCandidateID int
+	err := db.QueryRow(`INSERT INTO candidates (company_name, email, name, phone)
+		VALUES ('Test Company', 'jane@example.com', 'Jane Doe', '5551234567') RETURNING id`).Scan(&candidateID)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO candidates (company_name, email, name)
+		VALUES ('Other Company', 'jane@example.com', 'Jane Doe') RETURNING id`).Scan(&otherCandidateID)
+	assert.NoError(t, err)
+
+	// Jane applied to two jobs of the company
+	submissionIDs := []int{}
+	for i, jobID := range []string{"J_CAND_1", "J_CAND_2"} {
+		var jobIdNum, formIdNum, submissionID int
+		err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+			VALUES ($1, $2, $3, 'Test Description', $4) RETURNING id`,
+			jobID, hrUserID, fmt.Sprintf("Engineer %d", i+1), pq.Array([]string{"Go"})).Scan(&jobIdNum)
+		assert.NoError(t, err)
+		err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+			VALUES ($1, $2, '[]') RETURNING id`, fmt.Sprintf("cand_template_%d", i), hrUserID).Scan(&formIdNum)
+		assert.NoError(t, err)
+		formUUID := fmt.Sprintf("7c1d2e3f-4a5b-4c6d-8e9f-0a1b2c3d4e5%d", i)
+		_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+			VALUES ($1, $2, $3, 'active')`, formUUID, jobIdNum, formIdNum)
+		assert.NoError(t, err)
+		err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, ats_score, candidate_id, candidate_match)
+			VALUES ($1, $2, 'Jane Doe', 'jane@example.com', '{}', 'resume.pdf', 80, $3, 'email')
+			RETURNING id`, formUUID, jobID, candidateID).Scan(&submissionID)
+		assert.NoError(t, err)
+		submissionIDs = append(submissionIDs, submissionID)
+	}
+
+	var availabilityID int
+	err = db.QueryRow(`INSERT INTO availabilities (user_id, date, from_time, to_time)
+		VALUES ($1, '2030-01-15', '10:00', '11:00') RETURNING id`, interviewerID).Scan(&availabilityID)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO interviews (job_id, hr_user_id, job_submission_id, interviewer_user_id, availability_id, feedback, verdict, status)
+		VALUES ('J_CAND_1', $1, $2, $3, $4, 'Strong Go skills', 'passed', 'completed')`,
+		hrUserID, submissionIDs[0], interviewerID, availabilityID)
+	assert.NoError(t, err)
+
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", hrUserID)
+		c.Next()
+	})
+	router.GET("/api/candidates", handlers.ListCandidatesH)
+	router.GET("/api/candidates/:id", handlers.GetCandidateH)
+
+	request := func(path string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest("GET", path, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		return resp, response
+	}
+
+	// Only candidates of the user's company are listed
+	resp, response := request("/api/candidates?q=jane")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	candidates := response["data"].([]interface{})
+	assert.Len(t, candidates, 1)
+	candidate := candidates[0].(map[string]interface{})
+	assert.Equal(t, float64(candidateID), candidate["id"])
+	assert.Equal(t, float64(2), candidate["application_count"])
+
+	// The profile shows both applications and the interview feedback
+	resp, profile := request(fmt.Sprintf("/api/candidates/%d", candidateID))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "jane@example.com", profile["email"])
+	assert.Len(t, profile["applications"], 2)
+	interviews := profile["interviews"].([]interface{})
+	assert.Len(t, interviews, 1)
+	interview := interviews[0].(map[string]interface{})
+	assert.Equal(t, "Strong Go skills", interview["feedback"])
+	assert.Equal(t, "passed", interview["verdict"])
+	assert.Equal(t, "2030-01-15", interview["date"])
+
+	// Candidates of another company are not visible
+	resp, _ = request(fmt.Sprintf("/api/candidates/%d", otherCandidateID))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	resp, _ = request("/api/candidates/abc")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
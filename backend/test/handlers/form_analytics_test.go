@@ -0,0 +1,130 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFormFunnelH(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	// Insert a test user and set up router
+	userID, _ := test.InsertTestUser(db)
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Next()
+	})
+
+	// Set up routes
+	router.POST("/api/jobs", handlers.CreateJobH)
+	router.POST("/api/forms/templates", handlers.CreateFormTemplateH)
+	router.POST("/api/jobs/:job_id/forms", handlers.LinkJobToFormTemplateH)
+	router.GET("/api/forms/:form_uuid", handlers.GetFormDetailsH)
+	router.POST("/api/forms/:form_uuid/events", handlers.RecordFormEventH)
+	router.GET("/api/forms/:form_uuid/analytics", handlers.GetFormFunnelH)
+
+	// Create test job and form template, then link them
+	jobID := createTestJobForForm(t, router, userID)
+	formTemplateID := createTestFormTemplateForForm(t, router, userID)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"form_template_id": formTemplateID})
+	req, _ := http.NewRequest("POST", "/api/jobs/"+jobID+"/forms", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	var linkResponse map[string]interface{}
+	err := json.Unmarshal(resp.Body.Bytes(), &linkResponse)
+	assert.NoError(t, err)
+	formUUID := linkResponse["form_uuid"].(string)
+
+	// Two views from linkedin, one direct view
+	for _, path := range []string{
+		"/api/forms/" + formUUID + "?utm_source=LinkedIn&utm_campaign=spring",
+		"/api/forms/" + formUUID + "?utm_source=linkedin",
+		"/api/forms/" + formUUID,
+	} {
+		req, _ = http.NewRequest("GET", path, nil)
+		resp = httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	}
+
+	// One linkedin candidate starts filling in the form
+	jsonBody, _ = json.Marshal(map[string]interface{}{"event_type": "start", "utm_source": "linkedin"})
+	req, _ = http.NewRequest("POST", "/api/forms/"+formUUID+"/events", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	// Views are recorded when the form is fetched and submissions by the application endpoint
+	for _, eventType := range []string{"view", "submit"} {
+		jsonBody, _ = json.Marshal(map[string]interface{}{"event_type": eventType})
+		req, _ = http.NewRequest("POST", "/api/forms/"+formUUID+"/events", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp = httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	}
+
+	// Fetch the funnel
+	req, _ = http.NewRequest("GET", "/api/forms/"+formUUID+"/analytics", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	totals := response["totals"].(map[string]interface{})
+	assert.Equal(t, float64(3), totals["views"])
+	assert.Equal(t, float64(1), totals["starts"])
+	assert.Equal(t, float64(0), totals["submissions"])
+	assert.Equal(t, float64(1), totals["drop_off"])
+
+	bySource := response["by_source"].([]interface{})
+	assert.Len(t, bySource, 2)
+	assert.Equal(t, "linkedin", bySource[0].(map[string]interface{})["source"])
+	assert.Equal(t, float64(2), bySource[0].(map[string]interface{})["views"])
+
+	assert.Len(t, response["by_day"].([]interface{}), 1)
+}
+
+func TestGetFormFunnelH_NotOwner(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	userID, _ := test.InsertTestUser(db)
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Next()
+	})
+	router.GET("/api/forms/:form_uuid/analytics", handlers.GetFormFunnelH)
+
+	// Unknown form
+	req, _ := http.NewRequest("GET", "/api/forms/123e4567-e89b-12d3-a456-426614174000/analytics", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// Invalid UUID
+	req, _ = http.NewRequest("GET", "/api/forms/not-a-uuid/analytics", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
@@ -238,3 +238,92 @@ func TestDeleteFormTemplateH(t *testing.T) {
 	router.ServeHTTP(resp, req)
 	assert.Equal(t, http.StatusNotFound, resp.Code)
 }
+
+func TestSharedFormTemplateLibrary(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	// Two HR users of the same company
+	ownerID, _ := test.InsertTestUser(db)
+	var teammateID int
+	err := db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+		VALUES ('teammate@example.com', 'hash', 'teammate', 'HR', 'Test Company') RETURNING id`).Scan(&teammateID)
+	assert.NoError(t, err)
+
+	newRouter := func(userID int) *gin.Engine {
+		router := test.SetupTestRouter()
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Next()
+		})
+		router.POST("/api/jobs", handlers.CreateJobH)
+		router.POST("/api/jobs/:job_id/forms", handlers.LinkJobToFormTemplateH)
+		router.POST("/api/forms/templates", handlers.CreateFormTemplateH)
+		router.GET("/api/forms/templates/library", handlers.ListSharedFormTemplatesH)
+		router.PATCH("/api/forms/templates/:form_template_id/share", handlers.ShareFormTemplateH)
+		router.POST("/api/forms/templates/:form_template_id/copy", handlers.CopyFormTemplateH)
+		return router
+	}
+	ownerRouter := newRouter(ownerID)
+	teammateRouter := newRouter(teammateID)
+
+	templateID := createTestFormTemplate(t, ownerRouter, ownerID)
+	jobID := createTestJobForForm(t, teammateRouter, teammateID)
+
+	doJSON := func(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Template ids are unique per company
+	resp := doJSON(teammateRouter, "POST", "/api/forms/templates", map[string]interface{}{
+		"form_template_id": templateID,
+		"fields":           []map[string]interface{}{},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// Private templates cannot be linked or copied by teammates
+	resp = doJSON(teammateRouter, "POST", "/api/jobs/"+jobID+"/forms", map[string]interface{}{"form_template_id": templateID})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = doJSON(teammateRouter, "POST", "/api/forms/templates/"+templateID+"/copy", map[string]interface{}{"new_form_template_id": "TEMPLATE_COPY"})
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// Only the owner can share the template
+	resp = doJSON(teammateRouter, "PATCH", "/api/forms/templates/"+templateID+"/share", map[string]interface{}{"shared": true})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = doJSON(ownerRouter, "PATCH", "/api/forms/templates/"+templateID+"/share", map[string]interface{}{"shared": true})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// Shared template shows up in the teammate's library
+	req, _ := http.NewRequest("GET", "/api/forms/templates/library", nil)
+	resp = httptest.NewRecorder()
+	teammateRouter.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var library []map[string]interface{}
+	err = json.Unmarshal(resp.Body.Bytes(), &library)
+	assert.NoError(t, err)
+	assert.Len(t, library, 1)
+	assert.Equal(t, templateID, library[0]["form_template_id"])
+	assert.Equal(t, "testuser", library[0]["owner_username"])
+
+	// Teammate can link the shared template and copy it
+	resp = doJSON(teammateRouter, "POST", "/api/jobs/"+jobID+"/forms", map[string]interface{}{"form_template_id": templateID})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	resp = doJSON(teammateRouter, "POST", "/api/forms/templates/"+templateID+"/copy", map[string]interface{}{"new_form_template_id": "TEMPLATE_COPY"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	var copied map[string]interface{}
+	err = json.Unmarshal(resp.Body.Bytes(), &copied)
+	assert.NoError(t, err)
+	assert.Equal(t, "TEMPLATE_COPY", copied["form_template_id"])
+	assert.Equal(t, float64(teammateID), copied["user_id"])
+	assert.Equal(t, false, copied["shared"])
+	assert.NotNil(t, copied["copied_from_id"])
+	assert.Len(t, copied["fields"], 3)
+}
@@ -0,0 +1,186 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/internal/models"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRescheduleAndCancelInterview(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	interviewerIDs := make([]int, 2)
+	for i := range interviewerIDs {
+		err := db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+			VALUES ($1, 'hash', $2, 'Interviewer', 'Test Company') RETURNING id`,
+			fmt.Sprintf("change%d@example.com", i), fmt.Sprintf("change_%d", i)).Scan(&interviewerIDs[i])
+		assert.NoError(t, err)
+	}
+	insertSlot := func(userID int, date string, from string, to string) int {
+		var id int
+		err := db.QueryRow(`INSERT INTO availabilities (user_id, date, from_time, to_time)
+			VALUES ($1, $2, $3, $4) RETURNING id`, userID, date, from, to).Scan(&id)
+		assert.NoError(t, err)
+		return id
+	}
+	leadSlot := insertSlot(interviewerIDs[0], "2030-01-15", "10:00", "11:00")
+	leadNewSlot := insertSlot(interviewerIDs[0], "2030-01-16", "14:00", "15:00")
+	panelSlot := insertSlot(interviewerIDs[1], "2030-01-15", "10:00", "11:00")
+	panelNewSlot := insertSlot(interviewerIDs[1], "2030-01-16", "14:00", "15:00")
+	otherSlot := insertSlot(interviewerIDs[1], "2030-01-17", "09:00", "10:00")
+
+	var jobIdNum, submissionID int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_CHANGE', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	var formIdNum int
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('change_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('8b9c0d1e-2f3a-4b4c-9d5e-6f7a8b9c0d1e', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key)
+		VALUES ('8b9c0d1e-2f3a-4b4c-9d5e-6f7a8b9c0d1e', 'J_CHANGE', 'Jane Doe', 'jane@example.com', '{}', 'resumes/jane.pdf')
+		RETURNING id`).Scan(&submissionID)
+	assert.NoError(t, err)
+
+	currentUser := hrUserID
+	router := test.SetupTestRouter()
+	authorized := router.Group("/api", func(c *gin.Context) {
+		c.Set("userID", currentUser)
+		c.Next()
+	})
+	authorized.POST("/interviews", handlers.CreateInterviewH)
+	authorized.GET("/interviews/:id", handlers.GetInterviewH)
+	authorized.POST("/interviews/:id/reschedule", handlers.RescheduleInterviewH)
+	authorized.POST("/interviews/:id/cancel", handlers.CancelInterviewH)
+	authorized.POST("/interviews/:id/feedback", handlers.SubmitFeedbackH)
+
+	request := func(method string, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	book := func() *httptest.ResponseRecorder {
+		return request("POST", "/api/interviews", gin.H{
+			"job_id":              "J_CHANGE",
+			"job_submission_id":   submissionID,
+			"interviewer_user_id": interviewerIDs[0],
+			"availability_id":     leadSlot,
+			"panelists": []gin.H{
+				{"interviewer_user_id": interviewerIDs[1], "availability_id": panelSlot},
+			},
+		})
+	}
+
+	resp := book()
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var interview models.Interview
+	json.Unmarshal(resp.Body.Bytes(), &interview)
+	path := fmt.Sprintf("/api/interviews/%d", interview.ID)
+
+	// A reason is required
+	resp = request("POST", path+"/reschedule", gin.H{"availability_id": leadNewSlot})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// The new slot must belong to the interviewer
+	resp = request("POST", path+"/reschedule", gin.H{"availability_id": otherSlot, "reason": "Conflict"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// Panelists listed must be on the panel
+	resp = request("POST", path+"/reschedule", gin.H{
+		"availability_id": leadNewSlot,
+		"reason":          "Conflict",
+		"panelists":       []gin.H{{"interviewer_user_id": interviewerIDs[0], "availability_id": leadSlot}},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// HR moves the interview and its panelist, the old slots are released
+	resp = request("POST", path+"/reschedule", gin.H{
+		"availability_id": leadNewSlot,
+		"reason":          "Candidate asked for another day",
+		"panelists":       []gin.H{{"interviewer_user_id": interviewerIDs[1], "availability_id": panelNewSlot}},
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var rescheduled models.Interview
+	json.Unmarshal(resp.Body.Bytes(), &rescheduled)
+	assert.Equal(t, leadNewSlot, rescheduled.AvailabilityID)
+	assert.Equal(t, "2030-01-16", rescheduled.Date)
+	assert.Equal(t, models.InterviewStatusScheduled, rescheduled.Status)
+	if assert.Len(t, rescheduled.Panelists, 1) {
+		assert.Equal(t, panelNewSlot, rescheduled.Panelists[0].AvailabilityID)
+	}
+	if assert.Len(t, rescheduled.Changes, 1) {
+		assert.Equal(t, models.InterviewChangeRescheduled, rescheduled.Changes[0].Action)
+		assert.Equal(t, "Candidate asked for another day", rescheduled.Changes[0].Reason)
+		assert.Equal(t, "2030-01-15 10:00-11:00", rescheduled.Changes[0].PreviousSlot)
+		assert.Equal(t, "2030-01-16 14:00-15:00", rescheduled.Changes[0].NewSlot)
+	}
+
+	// The assigned interviewer cancels, the other interviewer cannot
+	currentUser = interviewerIDs[1]
+	resp = request("POST", path+"/cancel", gin.H{"reason": "Sick"})
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	currentUser = interviewerIDs[0]
+	resp = request("POST", path+"/cancel", gin.H{"reason": "Sick"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var cancelled models.Interview
+	json.Unmarshal(resp.Body.Bytes(), &cancelled)
+	assert.Equal(t, models.InterviewStatusCancelled, cancelled.Status)
+	if assert.Len(t, cancelled.Changes, 2) {
+		assert.Equal(t, models.InterviewChangeCancelled, cancelled.Changes[1].Action)
+		assert.Equal(t, "Sick", cancelled.Changes[1].Reason)
+		assert.Equal(t, interviewerIDs[0], *cancelled.Changes[1].UserID)
+	}
+
+	// Cancelled interviews are closed
+	resp = request("POST", path+"/cancel", gin.H{"reason": "Again"})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	resp = request("POST", path+"/reschedule", gin.H{"availability_id": leadSlot, "reason": "Back on"})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	resp = request("POST", path+"/feedback", gin.H{"verdict": "yes", "feedback": "Good"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// The panelist still sees the cancelled interview and its history
+	currentUser = interviewerIDs[1]
+	resp = request("GET", path, nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The released slots can be booked again
+	currentUser = hrUserID
+	resp = request("POST", "/api/interviews", gin.H{
+		"job_id":              "J_CHANGE",
+		"job_submission_id":   submissionID,
+		"interviewer_user_id": interviewerIDs[0],
+		"availability_id":     leadNewSlot,
+		"panelists": []gin.H{
+			{"interviewer_user_id": interviewerIDs[1], "availability_id": panelNewSlot},
+		},
+	})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	// The cancelled interview is kept
+	var status string
+	err = db.QueryRow("SELECT status FROM interviews WHERE id = $1", interview.ID).Scan(&status)
+	assert.NoError(t, err)
+	assert.Equal(t, models.InterviewStatusCancelled, status)
+}
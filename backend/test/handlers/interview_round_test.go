@@ -0,0 +1,165 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/internal/models"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanelInterviewRounds(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	interviewerIDs := make([]int, 3)
+	slotIDs := make([]int, 3)
+	for i := range interviewerIDs {
+		err := db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+			VALUES ($1, 'hash', $2, 'Interviewer', 'Test Company') RETURNING id`,
+			fmt.Sprintf("panel%d@example.com", i), fmt.Sprintf("panel_%d", i)).Scan(&interviewerIDs[i])
+		assert.NoError(t, err)
+		err = db.QueryRow(`INSERT INTO availabilities (user_id, date, from_time, to_time)
+			VALUES ($1, '2030-01-15', '10:00', '11:00') RETURNING id`, interviewerIDs[i]).Scan(&slotIDs[i])
+		assert.NoError(t, err)
+	}
+
+	var jobIdNum, formIdNum, submissionID int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_PANEL', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('panel_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key)
+		VALUES ('7a8b9c0d-1e2f-4a3b-8c4d-5e6f7a8b9c0d', 'J_PANEL', 'Jane Doe', 'jane@example.com', '{}', 'resumes/jane.pdf')
+		RETURNING id`).Scan(&submissionID)
+	assert.NoError(t, err)
+
+	currentUser := hrUserID
+	router := test.SetupTestRouter()
+	authorized := router.Group("/api", func(c *gin.Context) {
+		c.Set("userID", currentUser)
+		c.Next()
+	})
+	authorized.GET("/jobs/:job_id/interview-rounds", handlers.ListInterviewRoundsH)
+	authorized.POST("/jobs/:job_id/interview-rounds", handlers.CreateInterviewRoundH)
+	authorized.GET("/jobs/submissions/:submission_id/interview-rounds", handlers.GetSubmissionInterviewRoundsH)
+	authorized.POST("/interviews", handlers.CreateInterviewH)
+	authorized.POST("/interviews/:id/feedback", handlers.SubmitFeedbackH)
+
+	request := func(method string, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Rounds are ordered as they are added
+	var screening, technical models.InterviewRound
+	resp := request("POST", "/api/jobs/J_PANEL/interview-rounds", gin.H{"name": "Screening"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	json.Unmarshal(resp.Body.Bytes(), &screening)
+	resp = request("POST", "/api/jobs/J_PANEL/interview-rounds", gin.H{"name": "Technical"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	json.Unmarshal(resp.Body.Bytes(), &technical)
+	assert.Equal(t, 2, technical.Position)
+	resp = request("POST", "/api/jobs/J_PANEL/interview-rounds", gin.H{"name": "Technical"})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	// A panel interview in the technical round
+	resp = request("POST", "/api/interviews", gin.H{
+		"job_id":              "J_PANEL",
+		"job_submission_id":   submissionID,
+		"interviewer_user_id": interviewerIDs[0],
+		"availability_id":     slotIDs[0],
+		"round_id":            technical.ID,
+		"panelists": []gin.H{
+			{"interviewer_user_id": interviewerIDs[1], "availability_id": slotIDs[1]},
+		},
+	})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var panel models.Interview
+	json.Unmarshal(resp.Body.Bytes(), &panel)
+	assert.NotZero(t, panel.ID)
+	assert.Len(t, panel.Panelists, 1)
+
+	// Panelists bring their own slots
+	resp = request("POST", "/api/interviews", gin.H{
+		"job_id":              "J_PANEL",
+		"job_submission_id":   submissionID,
+		"interviewer_user_id": interviewerIDs[2],
+		"availability_id":     slotIDs[2],
+		"panelists": []gin.H{
+			{"interviewer_user_id": interviewerIDs[1], "availability_id": slotIDs[2]},
+		},
+	})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// An existing single-interviewer request without a round
+	resp = request("POST", "/api/interviews", gin.H{
+		"job_id":              "J_PANEL",
+		"job_submission_id":   submissionID,
+		"interviewer_user_id": interviewerIDs[2],
+		"availability_id":     slotIDs[2],
+	})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var single models.Interview
+	json.Unmarshal(resp.Body.Bytes(), &single)
+
+	// Every interviewer gives their own feedback
+	feedbackPath := fmt.Sprintf("/api/interviews/%d/feedback", panel.ID)
+	currentUser = interviewerIDs[1]
+	resp = request("POST", feedbackPath, gin.H{"verdict": "yes", "feedback": "Solid system design"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var status string
+	db.QueryRow("SELECT status FROM interviews WHERE id = $1", panel.ID).Scan(&status)
+	assert.Equal(t, "scheduled", status)
+
+	currentUser = interviewerIDs[0]
+	resp = request("POST", feedbackPath, gin.H{"verdict": "strong_yes", "feedback": "Strong Go skills"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	db.QueryRow("SELECT status FROM interviews WHERE id = $1", panel.ID).Scan(&status)
+	assert.Equal(t, "completed", status)
+
+	currentUser = interviewerIDs[2]
+	resp = request("POST", feedbackPath, gin.H{"verdict": "no", "feedback": "Not on the panel"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// HR sees the rounds with each interviewer's feedback
+	currentUser = hrUserID
+	resp = request("GET", fmt.Sprintf("/api/jobs/submissions/%d/interview-rounds", submissionID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var rounds []models.InterviewRoundResult
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &rounds))
+	assert.Len(t, rounds, 2)
+	assert.Equal(t, "Technical", rounds[0].Name)
+	assert.Equal(t, models.InterviewVerdictPassed, rounds[0].Verdict)
+	assert.Len(t, rounds[0].Feedback, 2)
+	assert.Equal(t, "Strong Go skills", rounds[0].Feedback[0].Feedback)
+	assert.Equal(t, "Solid system design", rounds[0].Feedback[1].Feedback)
+	assert.Nil(t, rounds[1].RoundID)
+	assert.Equal(t, models.InterviewVerdictPending, rounds[1].Verdict)
+	assert.Equal(t, single.ID, rounds[1].Feedback[0].InterviewID)
+
+	// Interviewers of the company do not see the feedback of the other interviewers
+	currentUser = interviewerIDs[1]
+	resp = request("GET", fmt.Sprintf("/api/jobs/submissions/%d/interview-rounds", submissionID), nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
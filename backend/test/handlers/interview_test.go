@@ -52,16 +52,56 @@ func TestCreateInterview(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Insert test job submission
-	_, err = db.Exec(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_url) 
-		VALUES ($1, $2, $3, $4, $5, $6)`,
-		"123e4567-e89b-12d3-a456-426614174000", jobID, "test candidate", "test_candidate@test.com", json.RawMessage(`{}`), "resume.pdf")
+	var submissionID int
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key) 
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		"123e4567-e89b-12d3-a456-426614174000", jobID, "test candidate", "test_candidate@test.com", json.RawMessage(`{}`), "resume.pdf").Scan(&submissionID)
+	assert.NoError(t, err)
+
+	// A job of another HR user with an application
+	var otherHRUserID int
+	err = db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+		VALUES ('other@example.com', 'hash', 'otherhr', 'HR', 'Other Company') RETURNING id`).Scan(&otherHRUserID)
 	assert.NoError(t, err)
+	var otherFormIdNum int
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields) 
+		VALUES ($1, $2, $3) RETURNING id`,
+		"other_template", otherHRUserID, json.RawMessage(`{}`)).Scan(&otherFormIdNum)
+	assert.NoError(t, err)
+	// The other HR user also uses the job id of the first job, job ids are only unique per user
+	otherSubmissionIDs := map[string]int{}
+	for i, otherJobID := range []string{"J_OTHER", jobID} {
+		var otherJobIdNum int
+		err = db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required) 
+			VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			otherJobID, otherHRUserID, "Other Job", "Test Description", pq.Array([]string{"Go"})).Scan(&otherJobIdNum)
+		assert.NoError(t, err)
+		otherFormUUID := fmt.Sprintf("223e4567-e89b-12d3-a456-42661417400%d", i)
+		_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status) 
+			VALUES ($1, $2, $3, $4)`,
+			otherFormUUID, otherJobIdNum, otherFormIdNum, "active")
+		assert.NoError(t, err)
+		var otherSubmissionID int
+		err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key) 
+			VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			otherFormUUID, otherJobID, "other candidate", "other_candidate@test.com", json.RawMessage(`{}`), "resume.pdf").Scan(&otherSubmissionID)
+		assert.NoError(t, err)
+		otherSubmissionIDs[otherJobID] = otherSubmissionID
+	}
 	
-	// Insert test availability
-	var availID int
+	// Insert test availability, slots must not have started when they are booked
+	var availID, pastAvailID, hrAvailID int
 	err = db.QueryRow(`INSERT INTO availabilities (user_id, date, from_time, to_time) 
 		VALUES ($1, $2, $3, $4) RETURNING id`,
-		interviewerID, "2024-01-01", "10:00:00", "11:00:00").Scan(&availID)
+		interviewerID, "2030-01-01", "10:00:00", "11:00:00").Scan(&availID)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO availabilities (user_id, date, from_time, to_time) 
+		VALUES ($1, $2, $3, $4) RETURNING id`,
+		interviewerID, "2024-01-01", "10:00:00", "11:00:00").Scan(&pastAvailID)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO availabilities (user_id, date, from_time, to_time) 
+		VALUES ($1, $2, $3, $4) RETURNING id`,
+		hrUserID, "2030-01-01", "12:00:00", "13:00:00").Scan(&hrAvailID)
 	assert.NoError(t, err)
 
 	router := test.SetupTestRouter()
@@ -87,17 +127,67 @@ func TestCreateInterview(t *testing.T) {
 			name: "Invalid job_id",
 			requestBody: map[string]interface{}{
 				"job_id": 123,
-				"job_submission_id": 1,
+				"job_submission_id": submissionID,
+				"interviewer_user_id": interviewerID,
+				"availability_id": availID,
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Slot of another user",
+			requestBody: map[string]interface{}{
+				"job_id": jobID,
+				"job_submission_id": submissionID,
+				"interviewer_user_id": interviewerID,
+				"availability_id": hrAvailID,
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Slot in the past",
+			requestBody: map[string]interface{}{
+				"job_id": jobID,
+				"job_submission_id": submissionID,
+				"interviewer_user_id": interviewerID,
+				"availability_id": pastAvailID,
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Submission of another job",
+			requestBody: map[string]interface{}{
+				"job_id": jobID,
+				"job_submission_id": otherSubmissionIDs["J_OTHER"],
+				"interviewer_user_id": interviewerID,
+				"availability_id": availID,
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "Submission of another HR user's job with the same job id",
+			requestBody: map[string]interface{}{
+				"job_id": jobID,
+				"job_submission_id": otherSubmissionIDs[jobID],
 				"interviewer_user_id": interviewerID,
 				"availability_id": availID,
 			},
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			name: "Job of another HR user",
+			requestBody: map[string]interface{}{
+				"job_id": "J_OTHER",
+				"job_submission_id": otherSubmissionIDs["J_OTHER"],
+				"interviewer_user_id": interviewerID,
+				"availability_id": availID,
+			},
+			expectedCode: http.StatusNotFound,
+		},
 		{
 			name: "Valid request",
 			requestBody: map[string]interface{}{
 				"job_id": jobID,
-				"job_submission_id": 1,
+				"job_submission_id": submissionID,
 				"interviewer_user_id": interviewerID,
 				"availability_id": availID,
 			},
@@ -123,6 +213,10 @@ func TestCreateInterview(t *testing.T) {
 				assert.Equal(t, tc.requestBody["job_id"], response["job_id"])
 				assert.Equal(t, float64(hrUserID), response["hr_user_id"])
 				assert.Equal(t, "scheduled", response["status"])
+				assert.NotZero(t, response["id"])
+				assert.Equal(t, "2030-01-01", response["date"])
+				assert.Equal(t, "10:00", response["from_time"])
+				assert.Equal(t, "11:00", response["to_time"])
 				assert.NotEmpty(t, response["created_at"])
 				assert.NotEmpty(t, response["updated_at"]) 
 			}
@@ -149,7 +243,7 @@ func TestDeleteInterviewH(t *testing.T) {
 	assert.NoError(t, err)
 	
 	// Insert test job submission
-	_, err = db.Exec(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_url) 
+	_, err = db.Exec(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key) 
 		VALUES ($1, $2, $3, $4, $5, $6)`,
 		"123e4567-e89b-12d3-a456-426614174000", jobID, "test candidate", "test_candidate@test.com", json.RawMessage(`{}`), "resume.pdf")
 	assert.NoError(t, err)
@@ -190,6 +284,11 @@ func TestDeleteInterviewH(t *testing.T) {
 			interviewID:  interviewID,
 			expectedCode: http.StatusOK,
 		},
+		{
+			name:         "Already cancelled interview",
+			interviewID:  interviewID,
+			expectedCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -201,11 +300,11 @@ func TestDeleteInterviewH(t *testing.T) {
 			assert.Equal(t, tc.expectedCode, resp.Code)
 
 			if tc.expectedCode == http.StatusOK {
-				// Verify interview was actually deleted
-				var count int
-				err := db.QueryRow("SELECT COUNT(*) FROM interviews WHERE id = $1", tc.interviewID).Scan(&count)
+				// Verify interview was kept as cancelled
+				var status string
+				err := db.QueryRow("SELECT status FROM interviews WHERE id = $1", tc.interviewID).Scan(&status)
 				assert.NoError(t, err)
-				assert.Equal(t, 0, count)
+				assert.Equal(t, "cancelled", status)
 			}
 		})
 	}
@@ -230,7 +329,7 @@ func TestSubmitFeedbackH(t *testing.T) {
 	assert.NoError(t, err)
 	
 	// Insert test job submission
-	_, err = db.Exec(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_url) 
+	_, err = db.Exec(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key) 
 		VALUES ($1, $2, $3, $4, $5, $6)`,
 		"123e4567-e89b-12d3-a456-426614174000", jobID, "test candidate", "test_candidate@test.com", json.RawMessage(`{}`), "resume.pdf")
 	assert.NoError(t, err)
@@ -265,17 +364,34 @@ func TestSubmitFeedbackH(t *testing.T) {
 		{
 			name:        "Non-existent interview ID",
 			interviewID: 99999,
+			requestBody: map[string]interface{}{
+				"verdict":  "yes",
+				"feedback": "Great candidate",
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:        "Verdict outside strong no to strong yes",
+			interviewID: interviewID,
 			requestBody: map[string]interface{}{
 				"verdict":  "passed",
 				"feedback": "Great candidate",
 			},
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			name:        "Free-text feedback is required without a scorecard",
+			interviewID: interviewID,
+			requestBody: map[string]interface{}{
+				"verdict": "yes",
+			},
+			expectedCode: http.StatusBadRequest,
+		},
 		{
 			name:        "Valid feedback submission",
 			interviewID: interviewID,
 			requestBody: map[string]interface{}{
-				"verdict":  "passed",
+				"verdict":  "yes",
 				"feedback": "Great candidate",
 			},
 			expectedCode: http.StatusOK,
@@ -298,7 +414,7 @@ func TestSubmitFeedbackH(t *testing.T) {
 				var status, verdict string
 				err := db.QueryRow("SELECT status, verdict FROM interviews WHERE id = $1", tc.interviewID).Scan(&status, &verdict)
 				assert.NoError(t, err)
-				assert.Equal(t, "passed", verdict)
+				assert.Equal(t, "yes", verdict)
 				assert.Equal(t, "completed", status)
 			}
 		})
@@ -325,7 +441,7 @@ func TestListAllInterviewsH(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Insert test job submissions
-	_, err = db.Exec(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_url) 
+	_, err = db.Exec(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key) 
 		VALUES ($1, $2, $3, $4, $5, $6), ($7, $8, $9, $10, $11, $12)`,
 		"uuid1", jobID, "candidate1", "candidate1@test.com", json.RawMessage(`{}`), "resume1.pdf",
 		"uuid2", jobID, "candidate2", "candidate2@test.com", json.RawMessage(`{}`), "resume2.pdf")
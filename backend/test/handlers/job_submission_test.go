@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"mime/multipart"
 	"net/http"
@@ -14,6 +16,7 @@ import (
 	"backend/internal/api/handlers"
 	"backend/internal/models"
 	"backend/internal/services"
+	"backend/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
@@ -25,34 +28,33 @@ import (
 var router *gin.Engine
 var originalDB *sqlx.DB
 
-// Define a variable for the upload function to make it easier to mock
-var uploadResumeFunc = services.UploadResumeToS3
-
 // MockDB for database testing
 type MockDB struct {
 	*sqlx.DB
 	mock.Mock
 }
 
-// MockUploadResumeToS3 mocks the S3 upload functionality
-func mockUploadResumeToS3(file *multipart.FileHeader, userID int, username string) (string, error) {
-	return fmt.Sprintf("https://example.com/resumes/%d_%s.pdf", userID, username), nil
+// mockStorage keeps uploaded files in memory instead of S3
+func mockStorage() storage.Storage {
+	return storage.NewMemoryStorage(&storage.URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("test")})
+}
+
+// failingStorage fails every upload
+type failingStorage struct {
+	storage.Storage
 }
 
-// MockFailedUploadResumeToS3 mocks a failed S3 upload
-func mockFailedUploadResumeToS3(file *multipart.FileHeader, userID int, username string) (string, error) {
-	return "", fmt.Errorf("S3 upload failed")
+func (failingStorage) Put(ctx context.Context, key string, body io.Reader, opts storage.PutOptions) error {
+	return fmt.Errorf("S3 upload failed")
 }
 
-// CreateHandleFormSubmissionWithCustomUploader creates a handler with a custom uploader
-func CreateHandleFormSubmissionWithCustomUploader(uploader func(*multipart.FileHeader, int, string) (string, error)) gin.HandlerFunc {
+// CreateHandleFormSubmissionWithStorage creates a handler storing uploaded files in the given storage
+func CreateHandleFormSubmissionWithStorage(store storage.Storage) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Save the original function
-		originalUploader := uploadResumeFunc
-		// Replace with our custom function
-		uploadResumeFunc = uploader
+		// Replace the configured storage
+		services.SetStorage(store)
 		// Restore after the handler completes
-		defer func() { uploadResumeFunc = originalUploader }()
+		defer services.SetStorage(nil)
 
 		// Call the actual handler
 		handlers.HandleFormSubmission(c)
@@ -134,7 +136,7 @@ func setupTestDB() {
 
 	// 🔹 Insert test job submissions
 	_, err = db.Exec(`
-		INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, skills, resume_url, ats_score, status)
+		INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, skills, resume_key, ats_score, status)
 		VALUES
 			('4d9a4320-f1d1-43f2-8477-edd07f557442', '5678', 'John Doe', 'john@example.com', '{"experience":"5 years","location":"Remote","skills":["Go","AWS"]}', ARRAY['Go', 'AWS'], 'https://s3.aws.com/resume1.pdf', 95, 'applied'),
 			('4d9a4320-f1d1-43f2-8477-edd07f557442', '5678', 'Jane Doe', 'jane@example.com', '{"experience":"3 years","location":"Onsite","skills":["Java","Docker"]}', ARRAY['Java', 'Docker'], 'https://s3.aws.com/resume2.pdf', 88, 'applied');
@@ -246,7 +248,7 @@ func TestHandleFormSubmission_Success(t *testing.T) {
 
 	// Create router with custom handler
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Assert response
@@ -293,11 +295,11 @@ func TestHandleFormSubmission_DuplicateApplication(t *testing.T) {
 
 	// Create router with custom handler
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Assert response - should fail with duplicate application error
-	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, http.StatusConflict, recorder.Code)
 
 	// Parse response body
 	var response map[string]interface{}
@@ -306,7 +308,7 @@ func TestHandleFormSubmission_DuplicateApplication(t *testing.T) {
 
 	// Check for error message
 	assert.Contains(t, response, "error")
-	assert.Equal(t, "User has already applied for this job", response["error"])
+	assert.Equal(t, services.ErrDuplicateApplication.Error(), response["error"])
 }
 
 func TestHandleFormSubmission_InvalidFormData(t *testing.T) {
@@ -334,7 +336,7 @@ func TestHandleFormSubmission_InvalidFormData(t *testing.T) {
 
 	// Create router with route
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Assert response - should fail with invalid form data
@@ -375,7 +377,7 @@ func TestHandleFormSubmission_InvalidUUID(t *testing.T) {
 
 	// Create router with route
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Assert response - should fail with invalid UUID
@@ -416,7 +418,7 @@ func TestHandleFormSubmission_S3UploadFailure(t *testing.T) {
 
 	// Create router with route
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockFailedUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(failingStorage{mockStorage()}))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Assert response - should fail with S3 upload error
@@ -457,7 +459,7 @@ func TestHandleFormSubmission_MissingRequiredFields(t *testing.T) {
 
 	// Create router with route
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Assert response - should fail with binding error
@@ -498,7 +500,7 @@ func TestHandleFormSubmission_InvalidUserID(t *testing.T) {
 
 	// Create router with route
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Assert response - should fail with invalid user ID format
@@ -537,15 +539,12 @@ func TestHandleFormSubmission_MissingUserID(t *testing.T) {
 	// Set URL parameter
 	req.URL.Path = "/api/forms/" + formUUID + "/submit"
 
-	// Add test header to avoid actual S3 uploads
-	req.Header.Set("X-Test-Mode", "true")
-
 	// Create recorder and serve request
 	recorder := httptest.NewRecorder()
 
 	// Create router with route
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Assert response - should succeed
@@ -635,7 +634,7 @@ func TestHandleFormSubmission_JobNotFound(t *testing.T) {
 
 	// Create router with custom handler
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Check if we get an appropriate response for job not found
@@ -676,7 +675,7 @@ func TestHandleFormSubmission_InvalidEmail(t *testing.T) {
 
 	// Create router with custom handler
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Should fail validation
@@ -718,7 +717,7 @@ func TestHandleFormSubmission_EmptySkills(t *testing.T) {
 
 	// Create router with custom handler
 	testRouter := gin.Default()
-	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithCustomUploader(mockUploadResumeToS3))
+	testRouter.POST("/api/forms/:form_uuid/submit", CreateHandleFormSubmissionWithStorage(mockStorage()))
 	testRouter.ServeHTTP(recorder, req)
 
 	// Since the skills column has a NOT NULL constraint, we expect an error
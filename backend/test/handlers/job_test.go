@@ -150,6 +150,34 @@ func TestUpdateJobH(t *testing.T) {
 	assert.Contains(t, skills, "PostgreSQL")
 	assert.Contains(t, skills, "Docker")
 	assert.Contains(t, skills, "Kubernetes")
+
+	// Allow updates, then update again without a resubmission policy
+	updateBody["resubmission_policy"] = "allow_update"
+	updateBody["resubmission_window_days"] = 30
+	jsonBody, _ = json.Marshal(updateBody)
+	req, _ = http.NewRequest("PUT", "/api/jobs/"+jobID, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	delete(updateBody, "resubmission_policy")
+	delete(updateBody, "resubmission_window_days")
+	jsonBody, _ = json.Marshal(updateBody)
+	req, _ = http.NewRequest("PUT", "/api/jobs/"+jobID, bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The stored policy and window are kept
+	var policy string
+	var windowDays int
+	err = db.QueryRow("SELECT resubmission_policy, resubmission_window_days FROM jobs WHERE job_id = $1 AND user_id = $2",
+		jobID, userID).Scan(&policy, &windowDays)
+	assert.NoError(t, err)
+	assert.Equal(t, "allow_update", policy)
+	assert.Equal(t, 30, windowDays)
 }
 
 func TestGetJobByIdH(t *testing.T) {
@@ -0,0 +1,130 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubmissionNotesAndTags(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	interviewerID, _ := test.InsertTestInterviewerUser(db)
+	var interviewerName string
+	db.QueryRow("SELECT username FROM users WHERE id = $1", interviewerID).Scan(&interviewerName)
+
+	var jobIdNum, formIdNum, submissionID int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_NOTES', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('notes_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key)
+		VALUES ('2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e', 'J_NOTES', 'Jane Doe', 'jane@example.com', '{}', 'resume.pdf')
+		RETURNING id`).Scan(&submissionID)
+	assert.NoError(t, err)
+
+	currentUser := hrUserID
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", currentUser)
+		c.Next()
+	})
+	router.GET("/api/jobs/:job_id/submissions", handlers.GetFormSubmissions)
+	router.GET("/api/jobs/submissions/:submission_id/notes", handlers.ListSubmissionNotesH)
+	router.POST("/api/jobs/submissions/:submission_id/notes", handlers.CreateSubmissionNoteH)
+	router.PUT("/api/jobs/submissions/:submission_id/notes/:note_id", handlers.UpdateSubmissionNoteH)
+	router.DELETE("/api/jobs/submissions/:submission_id/notes/:note_id", handlers.DeleteSubmissionNoteH)
+	router.POST("/api/jobs/submissions/:submission_id/tags", handlers.AddSubmissionTagsH)
+	router.DELETE("/api/jobs/submissions/:submission_id/tags/:tag", handlers.RemoveSubmissionTagH)
+	router.GET("/api/notifications", handlers.ListNotificationsH)
+	router.PUT("/api/notifications/:id/read", handlers.MarkNotificationReadH)
+
+	request := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		return resp, response
+	}
+	notesPath := fmt.Sprintf("/api/jobs/submissions/%d/notes", submissionID)
+
+	// A note mentioning a teammate notifies them
+	resp, note := request("POST", notesPath, map[string]interface{}{"body": "Great culture fit, ask about visa @" + interviewerName})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, []interface{}{interviewerName}, note["mentions"])
+	noteID := int(note["id"].(float64))
+
+	currentUser = interviewerID
+	req, _ := http.NewRequest("GET", "/api/notifications?unread=true", nil)
+	notificationsResp := httptest.NewRecorder()
+	router.ServeHTTP(notificationsResp, req)
+	var notifications []map[string]interface{}
+	json.Unmarshal(notificationsResp.Body.Bytes(), &notifications)
+	assert.Len(t, notifications, 1)
+	assert.Equal(t, "testuser mentioned you in a note on Jane Doe's application for Backend Engineer", notifications[0]["message"])
+
+	resp, _ = request("PUT", fmt.Sprintf("/api/notifications/%d/read", int(notifications[0]["id"].(float64))), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The teammate replies, but cannot edit or delete the HR's note
+	resp, _ = request("POST", notesPath, map[string]interface{}{"body": "Will do", "parent_id": noteID})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	resp, _ = request("PUT", fmt.Sprintf("%s/%d", notesPath, noteID), map[string]interface{}{"body": "Changed"})
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	resp, _ = request("DELETE", fmt.Sprintf("%s/%d", notesPath, noteID), nil)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	// The author edits and deletes, the reply stays under a placeholder
+	currentUser = hrUserID
+	resp, note = request("PUT", fmt.Sprintf("%s/%d", notesPath, noteID), map[string]interface{}{"body": "Great culture fit"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Empty(t, note["mentions"])
+	resp, _ = request("DELETE", fmt.Sprintf("%s/%d", notesPath, noteID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("GET", notesPath, nil)
+	notesResp := httptest.NewRecorder()
+	router.ServeHTTP(notesResp, req)
+	var threads []map[string]interface{}
+	json.Unmarshal(notesResp.Body.Bytes(), &threads)
+	assert.Len(t, threads, 1)
+	assert.Equal(t, true, threads[0]["deleted"])
+	assert.Len(t, threads[0]["replies"], 1)
+
+	// Tags filter the job's submissions
+	resp, _ = request("POST", fmt.Sprintf("/api/jobs/submissions/%d/tags", submissionID), map[string]interface{}{"tags": []string{"Visa", "senior"}})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, listing := request("GET", "/api/jobs/J_NOTES/submissions?tags=visa,senior", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Len(t, listing["data"], 1)
+	assert.Equal(t, []interface{}{"senior", "visa"}, listing["data"].([]interface{})[0].(map[string]interface{})["tags"])
+
+	resp, _ = request("DELETE", fmt.Sprintf("/api/jobs/submissions/%d/tags/visa", submissionID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	_, listing = request("GET", "/api/jobs/J_NOTES/submissions?tags=visa", nil)
+	assert.Empty(t, listing["data"])
+}
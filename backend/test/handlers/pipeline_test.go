@@ -0,0 +1,116 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobPipelineStages(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+
+	var jobIdNum, formIdNum, submissionID int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_PIPELINE', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('pipeline_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key)
+		VALUES ('9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a', 'J_PIPELINE', 'Candidate', 'candidate@example.com', '{}', 'resume.pdf')
+		RETURNING id`).Scan(&submissionID)
+	assert.NoError(t, err)
+
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", hrUserID)
+		c.Next()
+	})
+	router.GET("/api/jobs/:job_id/pipeline", handlers.GetJobPipelineH)
+	router.PUT("/api/jobs/:job_id/pipeline", handlers.UpdateJobPipelineH)
+	router.PUT("/api/jobs/submissions/:submission_id/status", handlers.UpdateSubmissionStatusH)
+	router.GET("/api/jobs/submissions/:submission_id/stages", handlers.GetSubmissionStagesH)
+
+	request := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	statusPath := fmt.Sprintf("/api/jobs/submissions/%d/status", submissionID)
+
+	// Jobs start with the default pipeline
+	resp := request("GET", "/api/jobs/J_PIPELINE/pipeline", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var pipeline map[string]interface{}
+	json.Unmarshal(resp.Body.Bytes(), &pipeline)
+	assert.Equal(t, true, pipeline["is_default"])
+	assert.Len(t, pipeline["stages"], 5)
+
+	// Removing the stage the candidate is in is not allowed
+	resp = request("PUT", "/api/jobs/J_PIPELINE/pipeline", map[string]interface{}{
+		"stages": []map[string]interface{}{{"name": "Phone screen"}, {"name": "Offer", "type": "hired"}},
+	})
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	resp = request("PUT", "/api/jobs/J_PIPELINE/pipeline", map[string]interface{}{
+		"stages": []map[string]interface{}{
+			{"key": "applied", "name": "Applied"},
+			{"name": "Phone screen"},
+			{"name": "Onsite"},
+			{"name": "Offer", "type": "hired"},
+			{"name": "Rejected", "type": "rejected"},
+		},
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// Unknown stages and backward moves are rejected
+	resp = request("PUT", statusPath, map[string]interface{}{"status": "shortlisted"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = request("PUT", statusPath, map[string]interface{}{"status": "onsite", "reason": "Strong resume"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request("PUT", statusPath, map[string]interface{}{"status": "phone_screen"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = request("PUT", statusPath, map[string]interface{}{"status": "rejected"})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// The stage history lists every move with who made it
+	resp = request("GET", fmt.Sprintf("/api/jobs/submissions/%d/stages", submissionID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var history struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	json.Unmarshal(resp.Body.Bytes(), &history)
+	assert.Len(t, history.Data, 2)
+	assert.Equal(t, "applied", history.Data[0]["from_stage"])
+	assert.Equal(t, "onsite", history.Data[0]["to_stage"])
+	assert.Equal(t, "Strong resume", history.Data[0]["reason"])
+	assert.Equal(t, "testuser", history.Data[0]["changed_by_name"])
+	assert.Equal(t, "rejected", history.Data[1]["to_stage"])
+
+	// Submissions of other users' jobs are not found
+	resp = request("PUT", "/api/jobs/submissions/999999/status", map[string]interface{}{"status": "onsite"})
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,120 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobRanking(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	interviewerID, _ := test.InsertTestInterviewerUser(db)
+
+	var jobIdNum, formIdNum int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_RANK', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('rank_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+
+	insertSubmission := func(name, email string, score int, breakdown string, status string) int {
+		var id int
+		err := db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, ats_score, ats_breakdown, status)
+			VALUES ('3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f', 'J_RANK', $1, $2, '{}', 'resume.pdf', $3, $4, $5)
+			RETURNING id`, name, email, score, breakdown, status).Scan(&id)
+		assert.NoError(t, err)
+		return id
+	}
+	passing := `{"score": 100, "criteria": [{"criterion": "required_skills", "score": 100, "matched": ["Go"]}]}`
+	failing := `{"score": 0, "criteria": [{"criterion": "required_skills", "score": 0, "missing": ["Go"]}]}`
+
+	interviewed := insertSubmission("Alice", "alice@example.com", 70, passing, "applied")
+	top := insertSubmission("Bob", "bob@example.com", 90, passing, "applied")
+	knockedOut := insertSubmission("Carol", "carol@example.com", 95, failing, "applied")
+	insertSubmission("Dave", "dave@example.com", 100, passing, "rejected")
+
+	var availabilityID int
+	err = db.QueryRow(`INSERT INTO availabilities (user_id, date, from_time, to_time)
+		VALUES ($1, '2030-01-15', '10:00', '11:00') RETURNING id`, interviewerID).Scan(&availabilityID)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO interviews (job_id, hr_user_id, job_submission_id, interviewer_user_id, availability_id, verdict, status)
+		VALUES ('J_RANK', $1, $2, $3, $4, 'passed', 'completed')`, hrUserID, interviewed, interviewerID, availabilityID)
+	assert.NoError(t, err)
+
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", hrUserID)
+		c.Next()
+	})
+	router.GET("/api/jobs/:job_id/ranking", handlers.GetJobRankingH)
+	router.GET("/api/jobs/:job_id/ranking/weights", handlers.GetJobRankingWeightsH)
+	router.PUT("/api/jobs/:job_id/ranking/weights", handlers.UpdateJobRankingWeightsH)
+
+	request := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		return resp, response
+	}
+	shortlistIDs := func(response map[string]interface{}) []float64 {
+		ids := []float64{}
+		for _, candidate := range response["shortlist"].([]interface{}) {
+			ids = append(ids, candidate.(map[string]interface{})["submission_id"].(float64))
+		}
+		return ids
+	}
+
+	// Default weights, the rejected candidate is not ranked
+	resp, weights := request("GET", "/api/jobs/J_RANK/ranking/weights", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, true, weights["is_default"])
+
+	resp, ranking := request("GET", "/api/jobs/J_RANK/ranking", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(3), ranking["candidates"])
+	assert.Equal(t, []float64{float64(top), float64(interviewed), float64(knockedOut)}, shortlistIDs(ranking))
+	first := ranking["shortlist"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, float64(1), first["rank"])
+	assert.NotEmpty(t, first["explanation"])
+
+	// Ranking by interviews only puts the interviewed candidate first, knocked out candidates can be excluded
+	resp, _ = request("PUT", "/api/jobs/J_RANK/ranking/weights", map[string]interface{}{
+		"score": 0, "knockout": 0, "interviews": 100, "recency": 0, "exclude_knocked_out": true,
+	})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp, ranking = request("GET", "/api/jobs/J_RANK/ranking?limit=1", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, float64(2), ranking["candidates"])
+	assert.Equal(t, []float64{float64(interviewed)}, shortlistIDs(ranking))
+
+	// All weights 0
+	resp, _ = request("PUT", "/api/jobs/J_RANK/ranking/weights", map[string]interface{}{"score": 0})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// Jobs of other users
+	resp, _ = request("GET", "/api/jobs/UNKNOWN/ranking", nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,151 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/internal/services"
+	"backend/internal/storage"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResubmissionAndHistory(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	services.SetStorage(storage.NewMemoryStorage(&storage.URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("test")}))
+	defer services.SetStorage(nil)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	var otherUserID int
+	err := db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+		VALUES ('other@example.com', 'hash', 'other_hr', 'HR', 'Other Company') RETURNING id`).Scan(&otherUserID)
+	assert.NoError(t, err)
+
+	// Both companies use the same job id, only the first one allows updates
+	formUUIDs := map[string]string{}
+	for i, owner := range []struct {
+		userID int
+		policy string
+	}{{hrUserID, "allow_update"}, {otherUserID, "reject"}} {
+		var jobIdNum, formIdNum int
+		err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required, resubmission_policy, resubmission_window_days)
+			VALUES ('J_RESUBMIT', $1, 'Backend Engineer', 'Test Description', $2, $3, 30) RETURNING id`,
+			owner.userID, pq.Array([]string{"Go"}), owner.policy).Scan(&jobIdNum)
+		assert.NoError(t, err)
+		err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+			VALUES ($1, $2, '[]') RETURNING id`, fmt.Sprintf("resubmit_template_%d", i), owner.userID).Scan(&formIdNum)
+		assert.NoError(t, err)
+		formUUID := fmt.Sprintf("9d0e1f2a-3b4c-4d5e-8f6a-7b8c9d0e1f2%d", i)
+		_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+			VALUES ($1, $2, $3, 'active')`, formUUID, jobIdNum, formIdNum)
+		assert.NoError(t, err)
+		formUUIDs[owner.policy] = formUUID
+	}
+
+	currentUser := hrUserID
+	router := test.SetupTestRouter()
+	router.POST("/api/jobs/:job_id/apply", handlers.HandleFormSubmission)
+	authorized := router.Group("/api", func(c *gin.Context) {
+		c.Set("userID", currentUser)
+		c.Next()
+	})
+	authorized.GET("/jobs/submissions/:submission_id/history", handlers.GetSubmissionHistoryH)
+
+	apply := func(formUUID string, skills string, token string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		w.WriteField("job_id", "J_RESUBMIT")
+		w.WriteField("username", "Jane Doe")
+		w.WriteField("email", "jane@example.com")
+		w.WriteField("form_uuid", formUUID)
+		w.WriteField("form_data", fmt.Sprintf(`{"Q_Skills": [%s]}`, skills))
+		if token != "" {
+			w.WriteField("token", token)
+		}
+		file, _ := w.CreateFormFile("resume", "resume.txt")
+		file.Write([]byte("Jane Doe, Go developer"))
+		w.Close()
+
+		req, _ := http.NewRequest("POST", "/api/jobs/J_RESUBMIT/apply", &body)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// The first application is created, the second one updates it
+	resp := apply(formUUIDs["allow_update"], `"Go"`, "")
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	var submissionID int
+	err = db.QueryRow("SELECT id FROM job_submissions WHERE form_uuid = $1", formUUIDs["allow_update"]).Scan(&submissionID)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO submission_attachments (job_submission_id, field_name, file_name, file_key)
+		VALUES ($1, 'Q_CoverLetter', 'cover.pdf', 'attachments/j_resubmit/cover.pdf')`, submissionID)
+	assert.NoError(t, err)
+
+	// Knowing the email is not enough, the update needs the magic link sent to it
+	resp = apply(formUUIDs["allow_update"], `"Go", "PostgreSQL"`, "")
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+	otherToken, err := services.GenerateCandidateToken(submissionID+1, "jane@example.com")
+	assert.NoError(t, err)
+	resp = apply(formUUIDs["allow_update"], `"Go", "PostgreSQL"`, otherToken)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	token, err := services.GenerateCandidateToken(submissionID, "jane@example.com")
+	assert.NoError(t, err)
+	resp = apply(formUUIDs["allow_update"], `"Go", "PostgreSQL"`, token)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var response struct {
+		Data struct {
+			Revision int `json:"revision"`
+		} `json:"data"`
+	}
+	json.Unmarshal(resp.Body.Bytes(), &response)
+	assert.Equal(t, 2, response.Data.Revision)
+
+	path := fmt.Sprintf("/api/jobs/submissions/%d/history", submissionID)
+
+	// The owner sees the previous version
+	resp = httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var history struct {
+		Data []struct {
+			Revision    int      `json:"revision"`
+			Skills      []string `json:"skills"`
+			Attachments []struct {
+				FileURL string `json:"file_url"`
+			} `json:"attachments"`
+		} `json:"data"`
+	}
+	json.Unmarshal(resp.Body.Bytes(), &history)
+	if assert.Len(t, history.Data, 1) {
+		assert.Equal(t, 1, history.Data[0].Revision)
+		assert.Equal(t, []string{"Go"}, history.Data[0].Skills)
+		// The attachment of the previous version is handed out through an expiring link
+		if assert.Len(t, history.Data[0].Attachments, 1) {
+			assert.Contains(t, history.Data[0].Attachments[0].FileURL, "/api/files/attachments/j_resubmit/cover.pdf?expires=")
+		}
+	}
+
+	// A user of another company with a job of the same job id does not
+	currentUser = otherUserID
+	resp = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", path, nil)
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
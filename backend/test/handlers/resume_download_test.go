@@ -0,0 +1,331 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/api/handlers"
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/internal/storage"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadResume(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	var otherUserID int
+	err := db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+		VALUES ('other@example.com', 'hash', 'otherhr', 'HR', 'Other Company') RETURNING id`).Scan(&otherUserID)
+	assert.NoError(t, err)
+
+	var jobIdNum, formIdNum, submissionID int
+	err = db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_RESUME', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('resume_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('4d5e6f7a-8b9c-4d0e-9f1a-2b3c4d5e6f7a', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, revision)
+		VALUES ('4d5e6f7a-8b9c-4d0e-9f1a-2b3c4d5e6f7a', 'J_RESUME', 'Jane Doe', 'jane@example.com', '{}', 'resumes/jane_v2.pdf', 2)
+		RETURNING id`).Scan(&submissionID)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO job_submission_history (job_submission_id, revision, username, form_data, resume_key, submitted_at)
+		VALUES ($1, 1, 'Jane Doe', '{}', 'resumes/jane_v1.pdf', NOW())`, submissionID)
+	assert.NoError(t, err)
+
+	store := storage.NewMemoryStorage(&storage.URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("test")})
+	services.SetStorage(store)
+	defer services.SetStorage(nil)
+	store.Put(context.Background(), "resumes/jane_v2.pdf", strings.NewReader("current resume"), storage.PutOptions{})
+	store.Put(context.Background(), "resumes/jane_v1.pdf", strings.NewReader("first resume"), storage.PutOptions{})
+
+	currentUser := hrUserID
+	router := test.SetupTestRouter()
+	router.GET("/api/files/*key", handlers.ServeFileH)
+	authorized := router.Group("/api", func(c *gin.Context) {
+		c.Set("userID", currentUser)
+		c.Next()
+	})
+	authorized.GET("/jobs/submissions/:submission_id/resume", handlers.DownloadResumeH)
+	authorized.GET("/jobs/:job_id/submissions", handlers.GetFormSubmissions)
+
+	request := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+	resumePath := fmt.Sprintf("/api/jobs/submissions/%d/resume", submissionID)
+
+	// Redirect to a signed URL that serves the file
+	resp := request(resumePath)
+	assert.Equal(t, http.StatusFound, resp.Code)
+	location, err := url.Parse(resp.Header().Get("Location"))
+	assert.NoError(t, err)
+	assert.Contains(t, location.RawQuery, "signature=")
+	resp = request(location.RequestURI())
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "current resume", resp.Body.String())
+
+	// A tampered signature is rejected
+	resp = request(strings.Replace(location.RequestURI(), "jane_v2", "jane_v1", 1))
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+
+	// Streaming a previous revision
+	resp = request(resumePath + "?stream=true&revision=1")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "first resume", resp.Body.String())
+	assert.Contains(t, resp.Header().Get("Content-Disposition"), "Jane_Doe_resume.pdf")
+
+	resp = request(resumePath + "?revision=5")
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// The submission listing only contains expiring URLs
+	resp = request("/api/jobs/J_RESUME/submissions")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "signature=")
+	assert.NotContains(t, resp.Body.String(), "resume_key")
+
+	// Users of other companies cannot see the resume
+	currentUser = otherUserID
+	resp = request(resumePath)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	// Neither can an interviewer of the same company, only the job owner sees candidate files
+	var interviewerID int
+	err = db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+		VALUES ('interviewer@example.com', 'hash', 'interviewer', 'Interviewer', 'Test Company') RETURNING id`).Scan(&interviewerID)
+	assert.NoError(t, err)
+	currentUser = interviewerID
+	resp = request(resumePath)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestRekeyResumes(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	var jobIdNum, formIdNum int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_REKEY', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`, hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('rekey_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+
+	// Another company using the same job id does not change the folder of the resumes
+	var otherUserID int
+	err = db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+		VALUES ('other@example.com', 'hash', 'otherhr', 'HR', 'Other Company') RETURNING id`).Scan(&otherUserID)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_REKEY', $1, 'Backend Engineer', 'Test Description', $2)`, otherUserID, pq.Array([]string{"Go"}))
+	assert.NoError(t, err)
+
+	// Two applicants named John Smith shared the old key, one of them updated the resume once
+	var firstID, secondID int
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, revision)
+		VALUES ('5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b', 'J_REKEY', 'John Smith', 'john1@example.com', '{}', 'resumes/0_John_Smith.pdf', 2)
+		RETURNING id`).Scan(&firstID)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, resume_file_name)
+		VALUES ('5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b', 'J_REKEY', 'John Smith', 'john2@example.com', '{}', 'resumes/0_John_Smith.pdf', 'cv.pdf')
+		RETURNING id`).Scan(&secondID)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO job_submission_history (job_submission_id, revision, username, form_data, resume_key, submitted_at)
+		VALUES ($1, 1, 'John Smith', '{}', 'resumes/0_John_Smith.pdf', NOW())`, firstID)
+	assert.NoError(t, err)
+
+	store := storage.NewMemoryStorage(&storage.URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("test")})
+	services.SetStorage(store)
+	defer services.SetStorage(nil)
+	store.Put(context.Background(), "resumes/0_John_Smith.pdf", strings.NewReader("last upload"), storage.PutOptions{})
+
+	// A dry run changes nothing
+	result, err := services.RekeyResumes(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Keys)
+	assert.Equal(t, 3, result.Rekeyed)
+	assert.Equal(t, []string{"resumes/0_John_Smith.pdf"}, store.Keys())
+
+	result, err = services.RekeyResumes(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Rekeyed)
+	assert.Equal(t, 1, result.Shared)
+	assert.Equal(t, 1, result.Deleted)
+
+	var keys []string
+	rows, err := db.Query(`SELECT resume_key FROM job_submissions UNION ALL SELECT resume_key FROM job_submission_history`)
+	assert.NoError(t, err)
+	for rows.Next() {
+		var key string
+		assert.NoError(t, rows.Scan(&key))
+		keys = append(keys, key)
+	}
+	rows.Close()
+	assert.Len(t, keys, 3)
+	assert.ElementsMatch(t, keys, store.Keys())
+	for _, key := range keys {
+		assert.True(t, strings.HasPrefix(key, "resumes/test_company/j_rekey/"), key)
+		file, info, err := store.Get(context.Background(), key)
+		assert.NoError(t, err)
+		file.Close()
+		assert.Equal(t, int64(len("last upload")), info.Size)
+	}
+
+	// Running again finds nothing to move
+	result, err = services.RekeyResumes(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Keys)
+}
+
+func TestSweepOrphanedFiles(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	_, err := db.Exec(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_SWEEP', $1, 'Backend Engineer', 'Test Description', $2)`, hrUserID, pq.Array([]string{"Go"}))
+	assert.NoError(t, err)
+
+	signer := &storage.URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("test")}
+	store := storage.NewMemoryStorage(signer)
+	services.SetStorage(store)
+	defer services.SetStorage(nil)
+	ctx := context.Background()
+	for _, key := range []string{
+		"resumes/test_company/j_sweep/current.pdf",
+		"resumes/test_company/j_sweep/previous.pdf",
+		"resumes/test_company/j_sweep/orphan.pdf",
+		"attachments/j_sweep/cover.pdf",
+		"attachments/j_sweep/old_cover.pdf",
+		"attachments/j_sweep/orphan.pdf",
+		"quarantine/test_company/j_sweep/infected.pdf",
+	} {
+		store.Put(ctx, key, strings.NewReader("file"), storage.PutOptions{})
+	}
+
+	// One submission with one earlier version, each with a resume and an attachment
+	var submissionID int
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, revision)
+		VALUES ('5e6f7a8b-9c0d-4e1f-8a2b-3c4d5e6f7a8b', 'J_SWEEP', 'Jane Doe', 'jane@example.com', '{}', 'resumes/test_company/j_sweep/current.pdf', 2)
+		RETURNING id`).Scan(&submissionID)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO submission_attachments (job_submission_id, field_name, file_name, file_key)
+		VALUES ($1, 'Q_CoverLetter', 'cover.pdf', 'attachments/j_sweep/cover.pdf')`, submissionID)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO job_submission_history (job_submission_id, revision, username, form_data, resume_key, attachments, submitted_at)
+		VALUES ($1, 1, 'Jane Doe', '{}', 'resumes/test_company/j_sweep/previous.pdf', '[{"field_name": "Q_CoverLetter", "file_key": "attachments/j_sweep/old_cover.pdf"}]', NOW())`,
+		submissionID)
+	assert.NoError(t, err)
+
+	// Recent files are kept, they may belong to an application that is still being saved
+	result, err := services.SweepOrphanedFiles(ctx, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, result.Checked)
+	assert.Empty(t, result.Deleted)
+
+	// Files are matched by key, attachments stay referenced when the URLs of the storage change
+	signer.BaseURL = "https://files.example.com/hiring"
+	result, err = services.SweepOrphanedFiles(ctx, 0)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"resumes/test_company/j_sweep/orphan.pdf", "attachments/j_sweep/orphan.pdf"}, result.Deleted)
+	assert.ElementsMatch(t, []string{
+		"resumes/test_company/j_sweep/current.pdf",
+		"resumes/test_company/j_sweep/previous.pdf",
+		"attachments/j_sweep/cover.pdf",
+		"attachments/j_sweep/old_cover.pdf",
+		"quarantine/test_company/j_sweep/infected.pdf",
+	}, store.Keys())
+}
+
+func TestGetResumePreview(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	var otherUserID int
+	err := db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+		VALUES ('other@example.com', 'hash', 'otherhr', 'HR', 'Other Company') RETURNING id`).Scan(&otherUserID)
+	assert.NoError(t, err)
+	var jobIdNum, formIdNum int
+	err = db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_PREVIEW', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`, hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('preview_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('6f7a8b9c-0d1e-4f2a-8b3c-4d5e6f7a8b9c', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+	var submissionID int
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key, resume_file_name)
+		VALUES ('6f7a8b9c-0d1e-4f2a-8b3c-4d5e6f7a8b9c', 'J_PREVIEW', 'Jane Doe', 'jane@example.com', '{}', 'resumes/test_company/j_preview/jane.txt', 'jane.txt')
+		RETURNING id`).Scan(&submissionID)
+	assert.NoError(t, err)
+
+	store := storage.NewMemoryStorage(&storage.URLSigner{BaseURL: "http://localhost:8080", Secret: []byte("test")})
+	services.SetStorage(store)
+	defer services.SetStorage(nil)
+	store.Put(context.Background(), "resumes/test_company/j_preview/jane.txt",
+		strings.NewReader("Jane Doe\n<img src=x onerror=alert(1)>"), storage.PutOptions{})
+
+	currentUser := hrUserID
+	router := test.SetupTestRouter()
+	authorized := router.Group("/api", func(c *gin.Context) {
+		c.Set("userID", currentUser)
+		c.Next()
+	})
+	authorized.GET("/jobs/submissions/:submission_id/resume/preview", handlers.GetResumePreviewH)
+	previewPath := fmt.Sprintf("/api/jobs/submissions/%d/resume/preview", submissionID)
+	request := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := request(previewPath)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var preview models.ResumePreview
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &preview))
+	assert.Equal(t, submissionID, preview.JobSubmissionID)
+	assert.Equal(t, "<p>Jane Doe<br>&lt;img src=x onerror=alert(1)&gt;</p>\n", preview.HTML)
+	assert.Empty(t, preview.ThumbnailURL)
+	assert.Contains(t, store.Keys(), "previews/test_company/j_preview/jane.txt.json")
+
+	// The cached preview is served even when the resume is gone
+	store.Delete(context.Background(), "resumes/test_company/j_preview/jane.txt")
+	resp = request(previewPath)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	resp = request(previewPath + "?revision=0")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// Users of other companies cannot see the preview
+	currentUser = otherUserID
+	resp = request(previewPath)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
@@ -0,0 +1,184 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/internal/models"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterviewScorecards(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	hrUserID, _ := test.InsertTestUser(db)
+	interviewerIDs := make([]int, 2)
+	slotIDs := make([]int, 2)
+	for i := range interviewerIDs {
+		err := db.QueryRow(`INSERT INTO users (email, password_hash, username, role, company_name)
+			VALUES ($1, 'hash', $2, 'Interviewer', 'Test Company') RETURNING id`,
+			fmt.Sprintf("scorer%d@example.com", i), fmt.Sprintf("scorer_%d", i)).Scan(&interviewerIDs[i])
+		assert.NoError(t, err)
+		err = db.QueryRow(`INSERT INTO availabilities (user_id, date, from_time, to_time)
+			VALUES ($1, '2030-01-15', '10:00', '11:00') RETURNING id`, interviewerIDs[i]).Scan(&slotIDs[i])
+		assert.NoError(t, err)
+	}
+
+	var jobIdNum, formIdNum, submissionID int
+	err := db.QueryRow(`INSERT INTO jobs (job_id, user_id, job_title, job_description, skills_required)
+		VALUES ('J_SCORE', $1, 'Backend Engineer', 'Test Description', $2) RETURNING id`,
+		hrUserID, pq.Array([]string{"Go"})).Scan(&jobIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO form_templates (form_template_id, user_id, fields)
+		VALUES ('score_template', $1, '[]') RETURNING id`, hrUserID).Scan(&formIdNum)
+	assert.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO application_form (form_uuid, job_id, form_id, status)
+		VALUES ('9c0d1e2f-3a4b-4c5d-8e6f-7a8b9c0d1e2f', $1, $2, 'active')`, jobIdNum, formIdNum)
+	assert.NoError(t, err)
+	err = db.QueryRow(`INSERT INTO job_submissions (form_uuid, job_id, username, email, form_data, resume_key)
+		VALUES ('9c0d1e2f-3a4b-4c5d-8e6f-7a8b9c0d1e2f', 'J_SCORE', 'Jane Doe', 'jane@example.com', '{}', 'resumes/jane.pdf')
+		RETURNING id`).Scan(&submissionID)
+	assert.NoError(t, err)
+
+	currentUser := hrUserID
+	router := test.SetupTestRouter()
+	authorized := router.Group("/api", func(c *gin.Context) {
+		c.Set("userID", currentUser)
+		c.Next()
+	})
+	authorized.GET("/jobs/:job_id/scorecard", handlers.GetScorecardH)
+	authorized.PUT("/jobs/:job_id/scorecard", handlers.UpdateScorecardH)
+	authorized.GET("/jobs/submissions/:submission_id/scorecard", handlers.GetSubmissionScorecardH)
+	authorized.POST("/interviews", handlers.CreateInterviewH)
+	authorized.GET("/interviews/:id/scorecard", handlers.GetInterviewScorecardH)
+	authorized.POST("/interviews/:id/feedback", handlers.SubmitFeedbackH)
+
+	request := func(method string, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// HR defines the scorecard of the job
+	resp := request("PUT", "/api/jobs/J_SCORE/scorecard", gin.H{"competencies": []gin.H{
+		{"name": "Problem solving"},
+		{"name": "problem solving"},
+	}})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	resp = request("PUT", "/api/jobs/J_SCORE/scorecard", gin.H{"competencies": []gin.H{
+		{"name": "Problem solving", "description": "Breaks down the system design exercise"},
+		{"name": "Communication", "rating_scale": 3, "comment_required": true},
+	}})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var scorecard models.Scorecard
+	json.Unmarshal(resp.Body.Bytes(), &scorecard)
+	assert.Len(t, scorecard.Competencies, 2)
+	assert.Equal(t, 5, scorecard.Competencies[0].RatingScale)
+	solving, communication := scorecard.Competencies[0].ID, scorecard.Competencies[1].ID
+
+	// Competencies keep their id when the scorecard is edited
+	resp = request("PUT", "/api/jobs/J_SCORE/scorecard", gin.H{"competencies": []gin.H{
+		{"name": "Communication", "rating_scale": 3, "comment_required": true},
+		{"name": "Problem solving", "description": "Breaks down the system design exercise"},
+	}})
+	assert.Equal(t, http.StatusOK, resp.Code)
+	json.Unmarshal(resp.Body.Bytes(), &scorecard)
+	assert.Equal(t, communication, scorecard.Competencies[0].ID)
+	assert.Equal(t, solving, scorecard.Competencies[1].ID)
+
+	resp = request("POST", "/api/interviews", gin.H{
+		"job_id":              "J_SCORE",
+		"job_submission_id":   submissionID,
+		"interviewer_user_id": interviewerIDs[0],
+		"availability_id":     slotIDs[0],
+		"panelists": []gin.H{
+			{"interviewer_user_id": interviewerIDs[1], "availability_id": slotIDs[1]},
+		},
+	})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var interview models.Interview
+	json.Unmarshal(resp.Body.Bytes(), &interview)
+
+	// Interviewers see the scorecard they fill in
+	currentUser = interviewerIDs[1]
+	resp = request("GET", fmt.Sprintf("/api/interviews/%d/scorecard", interview.ID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	json.Unmarshal(resp.Body.Bytes(), &scorecard)
+	assert.Len(t, scorecard.Competencies, 2)
+
+	feedbackPath := fmt.Sprintf("/api/interviews/%d/feedback", interview.ID)
+	testCases := []struct {
+		name         string
+		body         gin.H
+		expectedCode int
+	}{
+		{"Free-text feedback only", gin.H{"verdict": "yes", "feedback": "Good"}, http.StatusBadRequest},
+		{"Unconstrained verdict", gin.H{"verdict": "maybe", "scores": []gin.H{
+			{"competency_id": solving, "rating": 4},
+			{"competency_id": communication, "rating": 2, "comment": "Rambling"},
+		}}, http.StatusBadRequest},
+		{"Rating outside the scale", gin.H{"verdict": "yes", "scores": []gin.H{
+			{"competency_id": solving, "rating": 4},
+			{"competency_id": communication, "rating": 5, "comment": "Clear"},
+		}}, http.StatusBadRequest},
+		{"Missing required comment", gin.H{"verdict": "yes", "scores": []gin.H{
+			{"competency_id": solving, "rating": 4},
+			{"competency_id": communication, "rating": 2},
+		}}, http.StatusBadRequest},
+		{"Complete scorecard", gin.H{"verdict": "no", "scores": []gin.H{
+			{"competency_id": solving, "rating": 3},
+			{"competency_id": communication, "rating": 2, "comment": "Rambling"},
+		}}, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := request("POST", feedbackPath, tc.body)
+			assert.Equal(t, tc.expectedCode, resp.Code)
+		})
+	}
+
+	currentUser = interviewerIDs[0]
+	resp = request("POST", feedbackPath, gin.H{"verdict": "strong_yes", "feedback": "Hire", "scores": []gin.H{
+		{"competency_id": solving, "rating": 5},
+		{"competency_id": communication, "rating": 3, "comment": "Very clear"},
+	}})
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	// Only HR sees the scores of all interviewers
+	resp = request("GET", fmt.Sprintf("/api/jobs/submissions/%d/scorecard", submissionID), nil)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	currentUser = hrUserID
+	resp = request("GET", fmt.Sprintf("/api/jobs/submissions/%d/scorecard", submissionID), nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var result models.CandidateScorecard
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+	assert.Equal(t, map[string]int{"strong_yes": 1, "no": 1}, result.Verdicts)
+	// Ties fail, like the rounds
+	assert.Equal(t, models.InterviewVerdictFailed, result.Verdict)
+	if assert.Len(t, result.Competencies, 2) {
+		assert.Equal(t, "Communication", result.Competencies[0].Name)
+		assert.Equal(t, 3, result.Competencies[0].RatingScale)
+		assert.Equal(t, 2.5, result.Competencies[0].AverageRating)
+		assert.Len(t, result.Competencies[0].Ratings, 2)
+		assert.Equal(t, "Problem solving", result.Competencies[1].Name)
+		assert.Equal(t, 4.0, result.Competencies[1].AverageRating)
+	}
+
+	var status string
+	db.QueryRow("SELECT status FROM interviews WHERE id = $1", interview.ID).Scan(&status)
+	assert.Equal(t, "completed", status)
+}
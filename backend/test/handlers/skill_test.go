@@ -0,0 +1,130 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/api/handlers"
+	"backend/test"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkillsTaxonomy(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	userID, _ := test.InsertTestUser(db)
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Next()
+	})
+
+	router.GET("/api/skills", handlers.ListSkillsH)
+	router.POST("/api/skills", handlers.CreateSkillH)
+	router.GET("/api/skills/autocomplete", handlers.AutocompleteSkillsH)
+	router.PUT("/api/skills/:id", handlers.UpdateSkillH)
+	router.DELETE("/api/skills/:id", handlers.DeleteSkillH)
+
+	createSkill := func(body map[string]interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", "/api/skills", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(resp.Body.Bytes(), &response)
+		return resp, response
+	}
+
+	// Create a category and a skill in it
+	resp, category := createSkill(map[string]interface{}{"name": "Payments"})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	categoryID := int(category["id"].(float64))
+
+	resp, skill := createSkill(map[string]interface{}{"name": "Stripe", "aliases": []string{"stripe api", "Stripe"}, "parent_id": categoryID})
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, []interface{}{"stripe api"}, skill["aliases"])
+	assert.Equal(t, "Payments", skill["parent_name"])
+	skillID := int(skill["id"].(float64))
+
+	// An alias used by another skill is rejected
+	resp, _ = createSkill(map[string]interface{}{"name": "Stripe API"})
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// A category cannot become a child of its own skill
+	jsonBody, _ := json.Marshal(map[string]interface{}{"name": "Payments", "parent_id": skillID})
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("/api/skills/%d", categoryID), bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	// Autocomplete finds company and built-in skills
+	req, _ = http.NewRequest("GET", "/api/skills/autocomplete?q=stri", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	var suggestions []map[string]interface{}
+	json.Unmarshal(resp.Body.Bytes(), &suggestions)
+	assert.Equal(t, "Stripe", suggestions[0]["name"])
+	assert.Equal(t, "Payments", suggestions[0]["parent"])
+
+	// Job skills are stored under their canonical names
+	router.POST("/api/jobs", handlers.CreateJobH)
+	jsonBody, _ = json.Marshal(map[string]interface{}{
+		"job_id":          "TEST_JOB_SKILLS",
+		"job_title":       "Backend Engineer",
+		"job_description": "Payments backend",
+		"job_status":      "active",
+		"skills_required": []string{"golang", "stripe api", "Go"},
+	})
+	req, _ = http.NewRequest("POST", "/api/jobs", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	var job map[string]interface{}
+	json.Unmarshal(resp.Body.Bytes(), &job)
+	assert.Equal(t, []interface{}{"Go", "Stripe"}, job["skills_required"])
+
+	// Delete the skill, then it is gone
+	req, _ = http.NewRequest("DELETE", fmt.Sprintf("/api/skills/%d", skillID), nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("DELETE", fmt.Sprintf("/api/skills/%d", skillID), nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
+func TestSkillsTaxonomy_InterviewerCannotEdit(t *testing.T) {
+	// Clean up before test
+	test.CleanupTestDB(db)
+
+	userID, _ := test.InsertTestInterviewerUser(db)
+	router := test.SetupTestRouter()
+	router.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Next()
+	})
+	router.POST("/api/skills", handlers.CreateSkillH)
+
+	jsonBody, _ := json.Marshal(map[string]interface{}{"name": "Stripe"})
+	req, _ := http.NewRequest("POST", "/api/skills", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
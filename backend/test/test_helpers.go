@@ -46,8 +46,9 @@ func SetupTestDB() *sql.DB {
 		log.Printf("Warning: Could not create test database: %v", err)
 	}
 
-	// Set the global database connection
+	// Set the global database connection, uploaded files are kept in memory
 	os.Setenv("DB_NAME", testDbName)
+	os.Setenv("STORAGE_BACKEND", "memory")
 	if err := config.LoadConfig(); err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
@@ -65,6 +66,9 @@ func SetupTestDB() *sql.DB {
 func dropExistingTables(db *sqlx.DB) error {
 	// Drop tables in reverse order of dependencies
 	dropStatements := []string{
+		"DROP TABLE IF EXISTS skills CASCADE;",
+		"DROP TABLE IF EXISTS job_submissions CASCADE;",
+		"DROP TABLE IF EXISTS candidates CASCADE;",
 		"DROP TABLE IF EXISTS application_form CASCADE;",
 		"DROP TABLE IF EXISTS form_templates CASCADE;",
 		"DROP TABLE IF EXISTS jobs CASCADE;",
@@ -182,7 +186,7 @@ func InsertTestData(db *sql.DB) string {
 
 // CleanupTestDB removes all test data after a test
 func CleanupTestDB(db *sql.DB) {
-	_, err := db.Exec("DELETE FROM application_form; DELETE FROM jobs; DELETE FROM form_templates; DELETE FROM users; TRUNCATE users, jobs, job_submissions, availabilities, interviews CASCADE;")
+	_, err := db.Exec("DELETE FROM application_form; DELETE FROM jobs; DELETE FROM form_templates; DELETE FROM users; TRUNCATE users, jobs, job_submissions, availabilities, interviews, skills, candidates CASCADE;")
 	if err != nil {
 		log.Fatalf("Failed to clean up test DB: %v", err)
 	}